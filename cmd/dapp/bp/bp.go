@@ -64,6 +64,7 @@ func NewCmd() *cobra.Command {
 	common.SetupDir(&CommonCmdData, cmd)
 	common.SetupTmpDir(&CommonCmdData, cmd)
 	common.SetupHomeDir(&CommonCmdData, cmd)
+	common.SetupLockBackend(&CommonCmdData, cmd)
 	common.SetupSSHKey(&CommonCmdData, cmd)
 
 	cmd.PersistentFlags().StringVarP(&CmdData.Repo, "repo", "", "", "Docker repository name to push images to. CI_REGISTRY_IMAGE will be used by default if available.")
@@ -93,6 +94,10 @@ func runBP(dimgsToProcess []string) error {
 		return err
 	}
 
+	if err := common.InitLockBackend(&CommonCmdData); err != nil {
+		return err
+	}
+
 	if err := true_git.Init(); err != nil {
 		return err
 	}
@@ -137,7 +142,7 @@ func runBP(dimgsToProcess []string) error {
 		return err
 	}
 
-	if err := ssh_agent.Init(*CommonCmdData.SSHKeys); err != nil {
+	if err := common.InitSSHAgent(&CommonCmdData, projectDir, dappfile); err != nil {
 		return fmt.Errorf("cannot initialize ssh agent: %s", err)
 	}
 	defer func() {
@@ -161,7 +166,7 @@ func runBP(dimgsToProcess []string) error {
 
 	pushOpts := build.PushOptions{TagOptions: tagOpts, WithStages: CmdData.WithStages}
 
-	c := build.NewConveyor(dappfile, dimgsToProcess, projectDir, projectName, projectBuildDir, projectTmpDir, ssh_agent.SSHAuthSock, dockerAuthorizer)
+	c := build.NewConveyor(dappfile, dimgsToProcess, projectDir, projectName, projectBuildDir, projectTmpDir, ssh_agent.SSHAuthSock, ssh_agent.SSHConfigDir, dockerAuthorizer)
 	if err = c.BP(repo, buildOpts, pushOpts); err != nil {
 		return err
 	}
@@ -44,6 +44,7 @@ func NewCmd() *cobra.Command {
 	common.SetupDir(&CommonCmdData, cmd)
 	common.SetupTmpDir(&CommonCmdData, cmd)
 	common.SetupHomeDir(&CommonCmdData, cmd)
+	common.SetupLockBackend(&CommonCmdData, cmd)
 	common.SetupSSHKey(&CommonCmdData, cmd)
 
 	cmd.PersistentFlags().StringVarP(&CmdData.PullUsername, "pull-username", "", "", "Docker registry username to authorize pull of base images")
@@ -66,6 +67,10 @@ func runBuild(dimgsToProcess []string) error {
 		return err
 	}
 
+	if err := common.InitLockBackend(&CommonCmdData); err != nil {
+		return err
+	}
+
 	if err := true_git.Init(); err != nil {
 		return err
 	}
@@ -105,7 +110,7 @@ func runBuild(dimgsToProcess []string) error {
 		return err
 	}
 
-	if err := ssh_agent.Init(*CommonCmdData.SSHKeys); err != nil {
+	if err := common.InitSSHAgent(&CommonCmdData, projectDir, dappfile); err != nil {
 		return fmt.Errorf("cannot initialize ssh agent: %s", err)
 	}
 	defer func() {
@@ -122,7 +127,7 @@ func runBuild(dimgsToProcess []string) error {
 		},
 	}
 
-	c := build.NewConveyor(dappfile, dimgsToProcess, projectDir, projectName, projectBuildDir, projectTmpDir, ssh_agent.SSHAuthSock, dockerAuthorizer)
+	c := build.NewConveyor(dappfile, dimgsToProcess, projectDir, projectName, projectBuildDir, projectTmpDir, ssh_agent.SSHAuthSock, ssh_agent.SSHConfigDir, dockerAuthorizer)
 	if err = c.Build(buildOpts); err != nil {
 		return err
 	}
@@ -45,6 +45,8 @@ func NewCmd() *cobra.Command {
 	common.SetupDir(&CommonCmdData, cmd)
 	common.SetupTmpDir(&CommonCmdData, cmd)
 	common.SetupHomeDir(&CommonCmdData, cmd)
+	common.SetupLockBackend(&CommonCmdData, cmd)
+	common.SetupRegistryType(&CommonCmdData, cmd)
 
 	cmd.PersistentFlags().StringVarP(&CmdData.Repo, "repo", "", "", "Docker repository name")
 	cmd.PersistentFlags().StringVarP(&CmdData.RegistryUsername, "registry-username", "", "", "Docker registry username (granted read-write permission)")
@@ -66,6 +68,10 @@ func runCleanup() error {
 		return err
 	}
 
+	if err := common.InitLockBackend(&CommonCmdData); err != nil {
+		return err
+	}
+
 	if err := docker.Init(docker_authorizer.GetHomeDockerConfigDir()); err != nil {
 		return err
 	}
@@ -116,10 +122,16 @@ func runCleanup() error {
 		dimgNames = append(dimgNames, dimg.Name)
 	}
 
+	registryImplementation, err := common.GetRegistryImplementation(&CommonCmdData, repoName)
+	if err != nil {
+		return err
+	}
+
 	commonRepoOptions := cleanup.CommonRepoOptions{
-		Repository: repoName,
-		DimgsNames: dimgNames,
-		DryRun:     CmdData.DryRun,
+		Repository:             repoName,
+		RegistryImplementation: registryImplementation,
+		DimgsNames:             dimgNames,
+		DryRun:                 CmdData.DryRun,
 	}
 
 	localRepo := &git_repo.Local{}
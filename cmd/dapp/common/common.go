@@ -12,6 +12,7 @@ import (
 
 	"github.com/flant/dapp/pkg/config"
 	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/docker_registry"
 	"github.com/flant/dapp/pkg/git_repo"
 	"github.com/flant/dapp/pkg/slug"
 	"github.com/flant/kubedog/pkg/kube"
@@ -24,6 +25,21 @@ type CmdData struct {
 	HomeDir *string
 	SSHKeys *[]string
 
+	SSHHostKeys   *[]string
+	SSHConfig     *string
+	SSHKnownHosts *string
+
+	RegistryType *string
+
+	KubeLock        *bool
+	KubeLockTimeout *int
+
+	LockBackend     *string
+	LockBackendUrl  *string
+	LockNamespace   *string
+	LockKubeContext *string
+	LockTTL         *int
+
 	Tag        *[]string
 	TagBranch  *bool
 	TagBuildID *bool
@@ -56,7 +72,37 @@ func SetupHomeDir(cmdData *CmdData, cmd *cobra.Command) {
 
 func SetupSSHKey(cmdData *CmdData, cmd *cobra.Command) {
 	cmdData.SSHKeys = new([]string)
-	cmd.PersistentFlags().StringArrayVarP(cmdData.SSHKeys, "ssh-key", "", []string{}, "Enable only specified ssh keys (use system ssh-agent by default)")
+	cmdData.SSHHostKeys = new([]string)
+	cmdData.SSHConfig = new(string)
+	cmdData.SSHKnownHosts = new(string)
+
+	cmd.PersistentFlags().StringArrayVarP(cmdData.SSHKeys, "ssh-key", "", []string{}, "Enable only specified ssh keys (use system ssh-agent or ~/.ssh/id_* keys by default)")
+	cmd.PersistentFlags().StringArrayVarP(cmdData.SSHHostKeys, "ssh-host-key", "", []string{}, "Use ssh key only for hosts matched by ssh pattern with HOST=PATH, e.g. *.example.com=~/.ssh/deploy_key")
+	cmd.PersistentFlags().StringVarP(cmdData.SSHConfig, "ssh-config", "", "", "Use keys for hosts by Host and IdentityFile directives of specified ~/.ssh/config-style file")
+	cmd.PersistentFlags().StringVarP(cmdData.SSHKnownHosts, "ssh-known-hosts", "", "", fmt.Sprintf("Verify ssh host keys of remote git repos by specified known_hosts file (%s in the project dir by default if exists)", DefaultSSHKnownHostsFile))
+}
+
+func SetupRegistryType(cmdData *CmdData, cmd *cobra.Command) {
+	cmdData.RegistryType = new(string)
+	cmd.PersistentFlags().StringVarP(cmdData.RegistryType, "registry-type", "", docker_registry.AutoImplementationName, fmt.Sprintf(`Docker registry implementation to delete images with (%s).
+By default the implementation is detected by the registry host.`, strings.Join(docker_registry.RegistryImplementationNames, ", ")))
+}
+
+func GetRegistryImplementation(cmdData *CmdData, repoName string) (docker_registry.RegistryImplementation, error) {
+	registryImplementation, err := docker_registry.NewRegistryImplementation(repoName, *cmdData.RegistryType)
+	if err != nil {
+		return nil, fmt.Errorf("cannot initialize registry implementation: %s", err)
+	}
+
+	return registryImplementation, nil
+}
+
+func SetupKubeLock(cmdData *CmdData, cmd *cobra.Command) {
+	cmdData.KubeLock = new(bool)
+	cmdData.KubeLockTimeout = new(int)
+
+	cmd.PersistentFlags().BoolVarP(cmdData.KubeLock, "kube-lock", "", false, "Lock helm release by Lease objects in the release namespace to prevent concurrent operations from other hosts instead of the lock of --lock-backend")
+	cmd.PersistentFlags().IntVarP(cmdData.KubeLockTimeout, "kube-lock-timeout", "", 0, "Kubernetes lock wait timeout in seconds (24 hours by default)")
 }
 
 func SetupTag(cmdData *CmdData, cmd *cobra.Command) {
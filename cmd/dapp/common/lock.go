@@ -0,0 +1,58 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/kubedog/pkg/kube"
+)
+
+func SetupLockBackend(cmdData *CmdData, cmd *cobra.Command) {
+	cmdData.LockBackend = new(string)
+	cmdData.LockBackendUrl = new(string)
+	cmdData.LockNamespace = new(string)
+	cmdData.LockKubeContext = new(string)
+	cmdData.LockTTL = new(int)
+
+	defaultBackend := os.Getenv("DAPP_LOCK_BACKEND")
+	if defaultBackend == "" {
+		defaultBackend = lock.FileBackend
+	}
+
+	cmd.PersistentFlags().StringVarP(cmdData.LockBackend, "lock-backend", "", defaultBackend, fmt.Sprintf(`Lock backend to coordinate builds and cleanups of the project from different hosts (%s, $DAPP_LOCK_BACKEND).
+Locks of the local host resources are file locks regardless of the backend.`, strings.Join(lock.Backends, ", ")))
+	cmd.PersistentFlags().StringVarP(cmdData.LockBackendUrl, "lock-backend-url", "", os.Getenv("DAPP_LOCK_BACKEND_URL"), `Base url of etcd v2 compatible keys API for http lock backend, e.g. http://etcd:2379/v2/keys/dapp/locks ($DAPP_LOCK_BACKEND_URL).
+etcd v2 API is disabled by default since etcd 3.4 (run etcd with --enable-v2) and removed in etcd 3.6`)
+	cmd.PersistentFlags().StringVarP(cmdData.LockNamespace, "lock-namespace", "", os.Getenv("DAPP_LOCK_NAMESPACE"), "Kubernetes namespace to store Lease objects of kubernetes lock backend ($DAPP_LOCK_NAMESPACE or default kube namespace)")
+	cmd.PersistentFlags().StringVarP(cmdData.LockKubeContext, "lock-kube-context", "", "", "Kubernetes config context for kubernetes lock backend")
+	cmd.PersistentFlags().IntVarP(cmdData.LockTTL, "lock-ttl", "", 0, fmt.Sprintf("Lock ttl in seconds: lock of the holder which has not renewed it during ttl is considered stale (%d by default)", int(lock.DefaultLockTTL.Seconds())))
+}
+
+// InitLockBackend should be called after lock.Init.
+func InitLockBackend(cmdData *CmdData) error {
+	opts := lock.BackendOptions{
+		Backend: *cmdData.LockBackend,
+		TTL:     time.Duration(*cmdData.LockTTL) * time.Second,
+		HttpUrl: *cmdData.LockBackendUrl,
+	}
+
+	if opts.Backend == lock.KubernetesBackend {
+		if err := kube.Init(kube.InitOptions{KubeContext: *cmdData.LockKubeContext}); err != nil {
+			return fmt.Errorf("cannot initialize kube for lock backend: %s", err)
+		}
+
+		opts.KubernetesClient = kube.Kubernetes
+		opts.KubernetesNamespace = GetNamespace(*cmdData.LockNamespace)
+	}
+
+	if err := lock.SetupBackend(opts); err != nil {
+		return fmt.Errorf("cannot initialize lock backend: %s", err)
+	}
+
+	return nil
+}
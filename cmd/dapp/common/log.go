@@ -0,0 +1,57 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/pkg/logger"
+)
+
+var logCmdData struct {
+	Level  string
+	Format string
+	File   string
+	Quiet  bool
+
+	NoProgress bool
+}
+
+// SetupLog sets global log flags of the root command.
+func SetupLog(cmd *cobra.Command) {
+	defaultLevel := os.Getenv("DAPP_LOG_LEVEL")
+	if defaultLevel == "" {
+		defaultLevel = logger.InfoLevel.String()
+	}
+
+	defaultFormat := os.Getenv("DAPP_LOG_FORMAT")
+	if defaultFormat == "" {
+		defaultFormat = logger.TerminalFormat
+	}
+
+	cmd.PersistentFlags().StringVarP(&logCmdData.Level, "log-level", "", defaultLevel, fmt.Sprintf(`Log level (%s, $DAPP_LOG_LEVEL).
+Level of the scope can be specified after the global level: info,deploy=debug,true_git.patch=debug.
+Debug scopes: build, docker, deploy, git_repo.checksum, true_git.patch, true_git.archive, true_git.patch_parser`, strings.Join(logger.LevelNames, ", ")))
+	cmd.PersistentFlags().StringVarP(&logCmdData.Format, "log-format", "", defaultFormat, fmt.Sprintf("Log format (%s, $DAPP_LOG_FORMAT)", strings.Join(logger.Formats, ", ")))
+	cmd.PersistentFlags().StringVarP(&logCmdData.File, "log-file", "", "", "Write log to the file in addition to the output (json format for json log format and text otherwise)")
+	cmd.PersistentFlags().BoolVarP(&logCmdData.Quiet, "quiet", "", false, "Print only errors to the output (log file is not affected)")
+	cmd.PersistentFlags().BoolVarP(&logCmdData.NoProgress, "no-progress", "", false, "Disable live progress tree of the build on the terminal and print lines instead (progress is always disabled without TTY and on CI)")
+}
+
+func InitLog() error {
+	err := logger.Init(logger.Options{
+		Level:  logCmdData.Level,
+		Format: logCmdData.Format,
+		File:   logCmdData.File,
+		Quiet:  logCmdData.Quiet,
+
+		NoProgress: logCmdData.NoProgress,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot initialize logger: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package common
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+
+	"github.com/flant/dapp/pkg/config"
+	"github.com/flant/dapp/pkg/ssh_agent"
+	"github.com/flant/dapp/pkg/util"
+)
+
+const DefaultSSHKnownHostsFile = ".dapp_known_hosts"
+
+// InitSSHAgent initializes ssh agent with the keys of cli options and keys of dappfile remote git entries (sshKey).
+func InitSSHAgent(cmdData *CmdData, projectDir string, dappfile []*config.Dimg) error {
+	opts := ssh_agent.Options{Keys: *cmdData.SSHKeys}
+
+	for _, spec := range *cmdData.SSHHostKeys {
+		hostKey, err := ssh_agent.ParseHostKey(spec, "")
+		if err != nil {
+			return err
+		}
+		opts.HostKeys = append(opts.HostKeys, hostKey)
+	}
+
+	if *cmdData.SSHConfig != "" {
+		opts.ConfigFile = ssh_agent.ResolvePath(*cmdData.SSHConfig, projectDir)
+	}
+
+	if *cmdData.SSHKnownHosts != "" {
+		opts.KnownHostsFile = ssh_agent.ResolvePath(*cmdData.SSHKnownHosts, projectDir)
+	} else if path := filepath.Join(projectDir, DefaultSSHKnownHostsFile); util.IsFileExists(path) {
+		opts.KnownHostsFile = path
+	}
+
+	hostKeys, err := getDappfileHostKeys(projectDir, dappfile)
+	if err != nil {
+		return err
+	}
+	opts.HostKeys = hostKeys
+
+	return ssh_agent.Init(opts)
+}
+
+func getDappfileHostKeys(projectDir string, dappfile []*config.Dimg) ([]ssh_agent.HostKey, error) {
+	var hostKeys []ssh_agent.HostKey
+
+	for _, dimg := range dappfile {
+		for _, dimgInterface := range dimg.DimgTree() {
+			var dimgBase *config.DimgBase
+			switch d := dimgInterface.(type) {
+			case *config.Dimg:
+				dimgBase = d.DimgBase
+			case *config.DimgArtifact:
+				dimgBase = d.DimgBase
+			default:
+				continue
+			}
+
+			if dimgBase.Git == nil {
+				continue
+			}
+
+			for _, gitRemote := range dimgBase.Git.Remote {
+				if gitRemote.SSHKey == "" {
+					continue
+				}
+
+				endpoint, err := transport.NewEndpoint(gitRemote.Url)
+				if err != nil {
+					return nil, fmt.Errorf("bad git url `%s`: %s", gitRemote.Url, err)
+				}
+
+				hostKeys = append(hostKeys, ssh_agent.HostKey{
+					Host: endpoint.Host,
+					Key:  ssh_agent.ResolvePath(gitRemote.SSHKey, projectDir),
+				})
+			}
+		}
+	}
+
+	return hostKeys, nil
+}
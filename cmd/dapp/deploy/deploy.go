@@ -11,6 +11,7 @@ import (
 	"github.com/flant/dapp/pkg/deploy"
 	"github.com/flant/dapp/pkg/docker"
 	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/dapp/pkg/logger"
 	"github.com/flant/dapp/pkg/project_tmp_dir"
 	"github.com/flant/dapp/pkg/ssh_agent"
 	"github.com/flant/dapp/pkg/true_git"
@@ -21,35 +22,68 @@ import (
 var CmdData struct {
 	HelmReleaseName string
 
-	Namespace   string
-	KubeContext string
-	Timeout     int
+	Namespace    string
+	KubeContexts []string
+	Timeout      int
+
+	Targets         string
+	TargetsStrategy string
 
 	Values       []string
 	SecretValues []string
 	Set          []string
 	SetString    []string
 
+	AddAnnotations []string
+	AddLabels      []string
+
+	InjectGlobalMetadata bool
+
 	Repo             string
 	RegistryUsername string
 	RegistryPassword string
 	WithoutRegistry  bool
+
+	Diff            bool
+	ExitCode        bool
+	AutoRollback    bool
+	EnforcePolicies bool
+
+	Components    []string
+	All           bool
+	FailurePolicy string
 }
 
 var CommonCmdData common.CmdData
 
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:  "deploy HELM_RELEASE_NAME",
-		Args: cobra.MinimumNArgs(1),
+		Use: "deploy [HELM_RELEASE_NAME]",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(CmdData.Components) > 0 || CmdData.All {
+				return nil
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			CmdData.HelmReleaseName = args[0]
+			if len(args) > 0 {
+				CmdData.HelmReleaseName = args[0]
+			}
 
-			err := runDeploy()
+			hasChanges, err := runDeploy()
 			if err != nil {
 				return fmt.Errorf("deploy failed: %s", err)
 			}
 
+			if deploy.IsDeployTargetProcess() {
+				// Changes are reported to the parent dapp process, which sets the exit code
+				return deploy.WriteDeployTargetResult(hasChanges)
+			}
+
+			if CmdData.ExitCode && hasChanges {
+				os.Exit(deploy.DiffChangesExitCode)
+			}
+
 			return nil
 		},
 	}
@@ -58,67 +92,106 @@ func NewCmd() *cobra.Command {
 	common.SetupDir(&CommonCmdData, cmd)
 	common.SetupTmpDir(&CommonCmdData, cmd)
 	common.SetupHomeDir(&CommonCmdData, cmd)
+	common.SetupLockBackend(&CommonCmdData, cmd)
+	common.SetupKubeLock(&CommonCmdData, cmd)
 	common.SetupSSHKey(&CommonCmdData, cmd)
 
 	cmd.PersistentFlags().StringVarP(&CmdData.Namespace, "namespace", "", "", "Kubernetes namespace")
-	cmd.PersistentFlags().StringVarP(&CmdData.KubeContext, "kube-context", "", "", "Kubernetes config context")
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.KubeContexts, "kube-context", "", []string{}, "Kubernetes config context (release is deployed into each context if specified multiple times)")
+	cmd.PersistentFlags().StringVarP(&CmdData.Targets, "targets", "", "", "Targets file with kube contexts, namespaces and values overrides to deploy release into")
+	cmd.PersistentFlags().StringVarP(&CmdData.TargetsStrategy, "targets-strategy", "", "", "Deploy into multiple targets one by one or simultaneously: sequential or parallel (strategy of targets file or sequential by default)")
 	cmd.PersistentFlags().IntVarP(&CmdData.Timeout, "timeout", "t", 0, "watch timeout in seconds")
 
 	cmd.PersistentFlags().StringArrayVarP(&CmdData.Values, "values", "", []string{}, "Additional helm values")
 	cmd.PersistentFlags().StringArrayVarP(&CmdData.SecretValues, "secret-values", "", []string{}, "Additional helm secret values")
 	cmd.PersistentFlags().StringArrayVarP(&CmdData.Set, "set", "", []string{}, "Additional helm sets")
 	cmd.PersistentFlags().StringArrayVarP(&CmdData.SetString, "set-string", "", []string{}, "Additional helm STRING sets")
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.AddAnnotations, "add-annotation", "", []string{}, "Add annotation to deployed resources (format: key=value), see --inject-global-metadata")
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.AddLabels, "add-label", "", []string{}, "Add label to deployed resources (format: key=value), see --inject-global-metadata")
+	cmd.PersistentFlags().BoolVarP(&CmdData.InjectGlobalMetadata, "inject-global-metadata", "", false, "Inject global annotations and labels into all resources of the chart, not only into templates with dapp_global_annotations and dapp_global_labels helpers. The release is installed from templates rendered in advance: .Release.IsUpgrade, .Release.Revision and .Capabilities of the cluster are not available and NOTES.txt is skipped")
 
 	cmd.PersistentFlags().StringVarP(&CmdData.Repo, "repo", "", "", "Docker repository name to get images ids from. CI_REGISTRY_IMAGE will be used by default if available.")
 	cmd.PersistentFlags().StringVarP(&CmdData.RegistryUsername, "registry-username", "", "", "Docker registry username")
 	cmd.PersistentFlags().StringVarP(&CmdData.RegistryPassword, "registry-password", "", "", "Docker registry password")
 	cmd.PersistentFlags().BoolVarP(&CmdData.WithoutRegistry, "without-registry", "", false, "Do not get images info from registry")
 
+	cmd.PersistentFlags().BoolVarP(&CmdData.Diff, "diff", "", false, "Show the difference between the current release and rendered manifests before deploy")
+	cmd.PersistentFlags().BoolVarP(&CmdData.ExitCode, "exit-code", "", false, "Exit with code 2 when the release has been changed by successful deploy (requires --diff)")
+	cmd.PersistentFlags().BoolVarP(&CmdData.AutoRollback, "auto-rollback", "", false, "Rollback release to the last successful revision (or delete release installed for the first time) when resources tracking fails")
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.Components, "component", "", []string{}, "Deploy component chart .helm/COMPONENT declared in .helm/components.yaml (HELM_RELEASE_NAME is optional and available in release name template)")
+	cmd.PersistentFlags().BoolVarP(&CmdData.All, "all", "", false, "Deploy all components declared in .helm/components.yaml in the declared order")
+	cmd.PersistentFlags().StringVarP(&CmdData.FailurePolicy, "failure-policy", "", "", "Stop or continue deploy of remaining components after failure: stop or continue (failurePolicy of .helm/components.yaml by default)")
+	cmd.PersistentFlags().BoolVarP(&CmdData.EnforcePolicies, "enforce-policies", "", false, "Check rendered manifests against built-in and .helm/policies rules and fail deploy on violations")
+
 	common.SetupTag(&CommonCmdData, cmd)
 
 	return cmd
 }
 
-func runDeploy() error {
+func runDeploy() (bool, error) {
+	if CmdData.ExitCode && !CmdData.Diff {
+		return false, fmt.Errorf("--exit-code requires --diff")
+	}
+
+	target, err := deploy.GetCurrentDeployTarget()
+	if err != nil {
+		return false, err
+	}
+
+	if target == nil {
+		targets, strategy, err := getDeployTargets()
+		if err != nil {
+			return false, err
+		}
+
+		if len(targets) > 0 {
+			return deploy.RunTargetsDeploy(targets, strategy, os.Args[1:])
+		}
+	}
+
 	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
-		return fmt.Errorf("initialization error: %s", err)
+		return false, fmt.Errorf("initialization error: %s", err)
 	}
 
 	if err := lock.Init(); err != nil {
-		return err
+		return false, err
+	}
+
+	if err := common.InitLockBackend(&CommonCmdData); err != nil {
+		return false, err
 	}
 
 	if err := true_git.Init(); err != nil {
-		return err
+		return false, err
 	}
 
 	if err := deploy.Init(); err != nil {
-		return err
+		return false, err
 	}
 
 	if err := docker.Init(docker_authorizer.GetHomeDockerConfigDir()); err != nil {
-		return err
+		return false, err
 	}
 
 	projectDir, err := common.GetProjectDir(&CommonCmdData)
 	if err != nil {
-		return fmt.Errorf("getting project dir failed: %s", err)
+		return false, fmt.Errorf("getting project dir failed: %s", err)
 	}
 
 	projectName, err := common.GetProjectName(&CommonCmdData, projectDir)
 	if err != nil {
-		return fmt.Errorf("getting project name failed: %s", err)
+		return false, fmt.Errorf("getting project name failed: %s", err)
 	}
 
 	projectTmpDir, err := project_tmp_dir.Get()
 	if err != nil {
-		return fmt.Errorf("getting project tmp dir failed: %s", err)
+		return false, fmt.Errorf("getting project tmp dir failed: %s", err)
 	}
 	defer project_tmp_dir.Release(projectTmpDir)
 
 	dappfile, err := common.GetDappfile(projectDir)
 	if err != nil {
-		return fmt.Errorf("dappfile parsing failed: %s", err)
+		return false, fmt.Errorf("dappfile parsing failed: %s", err)
 	}
 
 	var repo string
@@ -126,45 +199,114 @@ func runDeploy() error {
 		var err error
 		repo, err = common.GetRequiredRepoName(projectName, CmdData.Repo)
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		dockerAuthorizer, err := docker_authorizer.GetDeployDockerAuthorizer(projectTmpDir, CmdData.RegistryUsername, CmdData.RegistryPassword, repo)
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		if err := dockerAuthorizer.Login(repo); err != nil {
-			return fmt.Errorf("docker login failed: %s", err)
+			return false, fmt.Errorf("docker login failed: %s", err)
 		}
 	}
 
-	if err := ssh_agent.Init(*CommonCmdData.SSHKeys); err != nil {
-		return fmt.Errorf("cannot initialize ssh-agent: %s", err)
+	if err := common.InitSSHAgent(&CommonCmdData, projectDir, dappfile); err != nil {
+		return false, fmt.Errorf("cannot initialize ssh-agent: %s", err)
 	}
+	defer func() {
+		err := ssh_agent.Terminate()
+		if err != nil {
+			logger.LogWarningF("WARNING: ssh agent termination failed: %s\n", err)
+		}
+	}()
 
 	tag, err := common.GetDeployTag(&CommonCmdData, projectDir)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	kubeContext := os.Getenv("KUBECONTEXT")
-	if kubeContext == "" {
-		kubeContext = CmdData.KubeContext
+	if kubeContext == "" && len(CmdData.KubeContexts) > 0 {
+		kubeContext = CmdData.KubeContexts[0]
 	}
+
+	namespaceOption := CmdData.Namespace
+	values, secretValues, set, setString := CmdData.Values, CmdData.SecretValues, CmdData.Set, CmdData.SetString
+
+	if target != nil {
+		if target.KubeContext != "" {
+			kubeContext = target.KubeContext
+		}
+		if target.Namespace != "" {
+			namespaceOption = target.Namespace
+		}
+
+		values, secretValues, set, setString = target.MergeValues(values, secretValues, set, setString)
+	}
+
 	err = kube.Init(kube.InitOptions{KubeContext: kubeContext})
 	if err != nil {
-		return fmt.Errorf("cannot initialize kube: %s", err)
+		return false, fmt.Errorf("cannot initialize kube: %s", err)
+	}
+
+	namespace := common.GetNamespace(namespaceOption)
+
+	deployOptions := deploy.DeployOptions{
+		Values:               values,
+		SecretValues:         secretValues,
+		Set:                  set,
+		SetString:            setString,
+		Timeout:              time.Duration(CmdData.Timeout) * time.Second,
+		WithoutRegistry:      CmdData.WithoutRegistry,
+		Diff:                 CmdData.Diff,
+		AutoRollback:         CmdData.AutoRollback,
+		AddAnnotations:       CmdData.AddAnnotations,
+		AddLabels:            CmdData.AddLabels,
+		InjectGlobalMetadata: CmdData.InjectGlobalMetadata,
+		KubeLock:             *CommonCmdData.KubeLock,
+		KubeLockTimeout:      time.Duration(*CommonCmdData.KubeLockTimeout) * time.Second,
+		EnforcePolicies:      CmdData.EnforcePolicies,
+	}
+
+	if len(CmdData.Components) > 0 || CmdData.All {
+		return deploy.RunComponentsDeploy(projectName, projectDir, CmdData.HelmReleaseName, namespace, kubeContext, repo, tag, dappfile, deploy.ComponentsDeployOptions{
+			DeployOptions: deployOptions,
+			Components:    CmdData.Components,
+			All:           CmdData.All,
+			FailurePolicy: CmdData.FailurePolicy,
+		})
 	}
 
-	namespace := common.GetNamespace(CmdData.Namespace)
+	return deploy.RunDeploy(projectName, projectDir, CmdData.HelmReleaseName, namespace, kubeContext, repo, tag, dappfile, deployOptions)
+}
+
+// getDeployTargets returns targets from targets file or multiple kube contexts.
+// No targets are returned for deploy into single kube context.
+func getDeployTargets() ([]*deploy.DeployTarget, string, error) {
+	if CmdData.Targets != "" {
+		cfg, err := deploy.LoadTargetsConfig(CmdData.Targets)
+		if err != nil {
+			return nil, "", err
+		}
+
+		strategy := cfg.Strategy
+		if CmdData.TargetsStrategy != "" {
+			strategy = CmdData.TargetsStrategy
+		}
+
+		return cfg.Targets, strategy, nil
+	}
+
+	if len(CmdData.KubeContexts) > 1 {
+		var targets []*deploy.DeployTarget
+		for _, kubeContext := range CmdData.KubeContexts {
+			targets = append(targets, &deploy.DeployTarget{Name: kubeContext, KubeContext: kubeContext})
+		}
+
+		return targets, CmdData.TargetsStrategy, nil
+	}
 
-	return deploy.RunDeploy(projectName, projectDir, CmdData.HelmReleaseName, namespace, kubeContext, repo, tag, dappfile, deploy.DeployOptions{
-		Values:          CmdData.Values,
-		SecretValues:    CmdData.SecretValues,
-		Set:             CmdData.Set,
-		SetString:       CmdData.SetString,
-		Timeout:         time.Duration(CmdData.Timeout) * time.Second,
-		WithoutRegistry: CmdData.WithoutRegistry,
-	})
+	return nil, "", nil
 }
@@ -3,6 +3,7 @@ package dismiss
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/flant/dapp/cmd/dapp/common"
 	"github.com/flant/dapp/pkg/dapp"
@@ -40,6 +41,7 @@ func NewCmd() *cobra.Command {
 
 	common.SetupTmpDir(&CommonCmdData, cmd)
 	common.SetupHomeDir(&CommonCmdData, cmd)
+	common.SetupKubeLock(&CommonCmdData, cmd)
 
 	cmd.PersistentFlags().StringVarP(&CmdData.Namespace, "namespace", "", "", "Kubernetes namespace")
 	cmd.PersistentFlags().StringVarP(&CmdData.KubeContext, "kube-context", "", "", "Kubernetes config context")
@@ -73,6 +75,9 @@ func runDismiss() error {
 	namespace := common.GetNamespace(CmdData.Namespace)
 
 	return deploy.RunDismiss(CmdData.HelmReleaseName, namespace, kubeContext, deploy.DismissOptions{
-		WithNamespace: CmdData.WithNamespace,
+		WithNamespace:   CmdData.WithNamespace,
+		KubeContext:     kubeContext,
+		KubeLock:        *CommonCmdData.KubeLock,
+		KubeLockTimeout: time.Duration(*CommonCmdData.KubeLockTimeout) * time.Second,
 	})
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/flant/dapp/pkg/docker"
 
 	"github.com/flant/dapp/pkg/docker_registry"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 type DockerCredentials struct {
@@ -24,12 +25,12 @@ type DockerAuthorizer struct {
 }
 
 func (a *DockerAuthorizer) LoginForPull(repo string) error {
-	fmt.Printf("# Login into docker repo %s for pull\n", repo)
+	logger.LogF("# Login into docker repo %s for pull\n", repo)
 	return a.login(a.PullCredentials, repo)
 }
 
 func (a *DockerAuthorizer) LoginForPush(repo string) error {
-	fmt.Printf("# Login into docker repo %s for push\n", repo)
+	logger.LogF("# Login into docker repo %s for push\n", repo)
 	return a.login(a.PushCredentials, repo)
 }
 
@@ -135,7 +136,7 @@ func getDockerAuthorizer(projectTmpDir string, credentials, pullCredentials, pus
 				return nil, fmt.Errorf("error creating tmp dir %s for docker config: %s", tmpDockerConfigDir, err)
 			}
 
-			fmt.Printf("Using tmp docker config at %s\n", tmpDockerConfigDir)
+			logger.LogF("Using tmp docker config at %s\n", tmpDockerConfigDir)
 
 			a.HostDockerConfigDir = tmpDockerConfigDir
 		} else {
@@ -43,6 +43,8 @@ func NewCmd() *cobra.Command {
 	common.SetupDir(&CommonCmdData, cmd)
 	common.SetupTmpDir(&CommonCmdData, cmd)
 	common.SetupHomeDir(&CommonCmdData, cmd)
+	common.SetupLockBackend(&CommonCmdData, cmd)
+	common.SetupRegistryType(&CommonCmdData, cmd)
 
 	cmd.PersistentFlags().StringVarP(&CmdData.Repo, "repo", "", "", "Docker repository name")
 	cmd.PersistentFlags().StringVarP(&CmdData.RegistryUsername, "registry-username", "", "", "Docker registry username (granted read-write permission)")
@@ -64,6 +66,10 @@ func runFlush() error {
 		return err
 	}
 
+	if err := common.InitLockBackend(&CommonCmdData); err != nil {
+		return err
+	}
+
 	projectDir, err := common.GetProjectDir(&CommonCmdData)
 	if err != nil {
 		return fmt.Errorf("getting project dir failed: %s", err)
@@ -105,10 +111,16 @@ func runFlush() error {
 			dimgNames = append(dimgNames, dimg.Name)
 		}
 
+		registryImplementation, err := common.GetRegistryImplementation(&CommonCmdData, CmdData.Repo)
+		if err != nil {
+			return err
+		}
+
 		commonRepoOptions := cleanup.CommonRepoOptions{
-			Repository: CmdData.Repo,
-			DimgsNames: dimgNames,
-			DryRun:     CmdData.DryRun,
+			Repository:             CmdData.Repo,
+			RegistryImplementation: registryImplementation,
+			DimgsNames:             dimgNames,
+			DryRun:                 CmdData.DryRun,
 		}
 
 		if err := cleanup.RepoImagesFlush(CmdData.WithDimgs, commonRepoOptions); err != nil {
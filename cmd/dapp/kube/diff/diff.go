@@ -0,0 +1,203 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	"github.com/flant/dapp/cmd/dapp/docker_authorizer"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy"
+	"github.com/flant/dapp/pkg/docker"
+	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/dapp/pkg/logger"
+	"github.com/flant/dapp/pkg/project_tmp_dir"
+	"github.com/flant/dapp/pkg/ssh_agent"
+	"github.com/flant/dapp/pkg/true_git"
+	"github.com/flant/kubedog/pkg/kube"
+	"github.com/spf13/cobra"
+)
+
+var CmdData struct {
+	HelmReleaseName string
+
+	Namespace   string
+	KubeContext string
+
+	Values       []string
+	SecretValues []string
+	Set          []string
+	SetString    []string
+
+	AddAnnotations []string
+	AddLabels      []string
+
+	InjectGlobalMetadata bool
+
+	Repo             string
+	RegistryUsername string
+	RegistryPassword string
+	WithoutRegistry  bool
+
+	ExitCode  bool
+	Component string
+}
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [HELM_RELEASE_NAME]",
+		Short: "Show the difference between the current release and rendered manifests",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if CmdData.Component != "" {
+				return nil
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				CmdData.HelmReleaseName = args[0]
+			}
+
+			hasChanges, err := runDiff()
+			if err != nil {
+				return fmt.Errorf("diff failed: %s", err)
+			}
+
+			if CmdData.ExitCode && hasChanges {
+				os.Exit(deploy.DiffChangesExitCode)
+			}
+
+			return nil
+		},
+	}
+
+	common.SetupName(&CommonCmdData, cmd)
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+	common.SetupSSHKey(&CommonCmdData, cmd)
+
+	cmd.PersistentFlags().StringVarP(&CmdData.Namespace, "namespace", "", "", "Kubernetes namespace")
+	cmd.PersistentFlags().StringVarP(&CmdData.KubeContext, "kube-context", "", "", "Kubernetes config context")
+
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.Values, "values", "", []string{}, "Additional helm values")
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.SecretValues, "secret-values", "", []string{}, "Additional helm secret values")
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.Set, "set", "", []string{}, "Additional helm sets")
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.SetString, "set-string", "", []string{}, "Additional helm STRING sets")
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.AddAnnotations, "add-annotation", "", []string{}, "Add annotation to deployed resources (format: key=value), see --inject-global-metadata")
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.AddLabels, "add-label", "", []string{}, "Add label to deployed resources (format: key=value), see --inject-global-metadata")
+	cmd.PersistentFlags().BoolVarP(&CmdData.InjectGlobalMetadata, "inject-global-metadata", "", false, "Inject global annotations and labels into all resources of the chart, not only into templates with dapp_global_annotations and dapp_global_labels helpers. The release is installed from templates rendered in advance: .Release.IsUpgrade, .Release.Revision and .Capabilities of the cluster are not available and NOTES.txt is skipped")
+
+	cmd.PersistentFlags().StringVarP(&CmdData.Repo, "repo", "", "", "Docker repository name to get images ids from. CI_REGISTRY_IMAGE will be used by default if available.")
+	cmd.PersistentFlags().StringVarP(&CmdData.RegistryUsername, "registry-username", "", "", "Docker registry username")
+	cmd.PersistentFlags().StringVarP(&CmdData.RegistryPassword, "registry-password", "", "", "Docker registry password")
+	cmd.PersistentFlags().BoolVarP(&CmdData.WithoutRegistry, "without-registry", "", false, "Do not get images info from registry")
+
+	cmd.PersistentFlags().BoolVarP(&CmdData.ExitCode, "exit-code", "", false, "Exit with code 2 when the release would be changed")
+	cmd.PersistentFlags().StringVarP(&CmdData.Component, "component", "", "", "Diff component chart .helm/COMPONENT declared in .helm/components.yaml with the component release (HELM_RELEASE_NAME is optional and available in release name template)")
+
+	common.SetupTag(&CommonCmdData, cmd)
+
+	return cmd
+}
+
+func runDiff() (bool, error) {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return false, fmt.Errorf("initialization error: %s", err)
+	}
+
+	if err := lock.Init(); err != nil {
+		return false, err
+	}
+
+	if err := true_git.Init(); err != nil {
+		return false, err
+	}
+
+	if err := deploy.Init(); err != nil {
+		return false, err
+	}
+
+	if err := docker.Init(docker_authorizer.GetHomeDockerConfigDir()); err != nil {
+		return false, err
+	}
+
+	projectDir, err := common.GetProjectDir(&CommonCmdData)
+	if err != nil {
+		return false, fmt.Errorf("getting project dir failed: %s", err)
+	}
+
+	projectName, err := common.GetProjectName(&CommonCmdData, projectDir)
+	if err != nil {
+		return false, fmt.Errorf("getting project name failed: %s", err)
+	}
+
+	projectTmpDir, err := project_tmp_dir.Get()
+	if err != nil {
+		return false, fmt.Errorf("getting project tmp dir failed: %s", err)
+	}
+	defer project_tmp_dir.Release(projectTmpDir)
+
+	dappfile, err := common.GetDappfile(projectDir)
+	if err != nil {
+		return false, fmt.Errorf("dappfile parsing failed: %s", err)
+	}
+
+	var repo string
+	if !CmdData.WithoutRegistry {
+		var err error
+		repo, err = common.GetRequiredRepoName(projectName, CmdData.Repo)
+		if err != nil {
+			return false, err
+		}
+
+		dockerAuthorizer, err := docker_authorizer.GetDeployDockerAuthorizer(projectTmpDir, CmdData.RegistryUsername, CmdData.RegistryPassword, repo)
+		if err != nil {
+			return false, err
+		}
+
+		if err := dockerAuthorizer.Login(repo); err != nil {
+			return false, fmt.Errorf("docker login failed: %s", err)
+		}
+	}
+
+	if err := common.InitSSHAgent(&CommonCmdData, projectDir, dappfile); err != nil {
+		return false, fmt.Errorf("cannot initialize ssh-agent: %s", err)
+	}
+	defer func() {
+		err := ssh_agent.Terminate()
+		if err != nil {
+			logger.LogWarningF("WARNING: ssh agent termination failed: %s\n", err)
+		}
+	}()
+
+	tag, err := common.GetDeployTag(&CommonCmdData, projectDir)
+	if err != nil {
+		return false, err
+	}
+
+	kubeContext := os.Getenv("KUBECONTEXT")
+	if kubeContext == "" {
+		kubeContext = CmdData.KubeContext
+	}
+	err = kube.Init(kube.InitOptions{KubeContext: kubeContext})
+	if err != nil {
+		return false, fmt.Errorf("cannot initialize kube: %s", err)
+	}
+
+	namespace := common.GetNamespace(CmdData.Namespace)
+
+	return deploy.RunDiff(projectName, projectDir, CmdData.HelmReleaseName, namespace, kubeContext, repo, tag, dappfile, deploy.DiffOptions{
+		Values:               CmdData.Values,
+		SecretValues:         CmdData.SecretValues,
+		Set:                  CmdData.Set,
+		SetString:            CmdData.SetString,
+		WithoutRegistry:      CmdData.WithoutRegistry,
+		AddAnnotations:       CmdData.AddAnnotations,
+		AddLabels:            CmdData.AddLabels,
+		InjectGlobalMetadata: CmdData.InjectGlobalMetadata,
+		Component:            CmdData.Component,
+	})
+}
@@ -0,0 +1,66 @@
+package history
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy"
+	"github.com/spf13/cobra"
+)
+
+var CmdData struct {
+	HelmReleaseName string
+
+	KubeContext string
+	Max         int
+}
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history HELM_RELEASE_NAME",
+		Short: "Show release revisions with dapp service values of each revision",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			CmdData.HelmReleaseName = args[0]
+
+			err := runHistory()
+			if err != nil {
+				return fmt.Errorf("history failed: %s", err)
+			}
+
+			return nil
+		},
+	}
+
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	cmd.PersistentFlags().StringVarP(&CmdData.KubeContext, "kube-context", "", "", "Kubernetes config context")
+	cmd.PersistentFlags().IntVarP(&CmdData.Max, "max", "", 256, "Maximum number of revisions to show")
+
+	return cmd
+}
+
+func runHistory() error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	if err := deploy.Init(); err != nil {
+		return err
+	}
+
+	kubeContext := os.Getenv("KUBECONTEXT")
+	if kubeContext == "" {
+		kubeContext = CmdData.KubeContext
+	}
+
+	return deploy.RunHistory(CmdData.HelmReleaseName, deploy.HistoryOptions{
+		Max:               CmdData.Max,
+		CommonHelmOptions: deploy.CommonHelmOptions{KubeContext: kubeContext},
+	})
+}
@@ -0,0 +1,76 @@
+package status
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy"
+	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/kubedog/pkg/kube"
+	"github.com/spf13/cobra"
+)
+
+var CmdData struct {
+	HelmReleaseName string
+
+	Namespace   string
+	KubeContext string
+}
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status HELM_RELEASE_NAME",
+		Short: "Show holders of the helm release lock in the release namespace and in the lock backend",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			CmdData.HelmReleaseName = args[0]
+
+			err := run()
+			if err != nil {
+				return fmt.Errorf("lock status failed: %s", err)
+			}
+
+			return nil
+		},
+	}
+
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+	common.SetupLockBackend(&CommonCmdData, cmd)
+
+	cmd.PersistentFlags().StringVarP(&CmdData.Namespace, "namespace", "", "", "Kubernetes namespace")
+	cmd.PersistentFlags().StringVarP(&CmdData.KubeContext, "kube-context", "", "", "Kubernetes config context")
+
+	return cmd
+}
+
+func run() error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	if err := lock.Init(); err != nil {
+		return err
+	}
+
+	if err := common.InitLockBackend(&CommonCmdData); err != nil {
+		return err
+	}
+
+	kubeContext := os.Getenv("KUBECONTEXT")
+	if kubeContext == "" {
+		kubeContext = CmdData.KubeContext
+	}
+	err := kube.Init(kube.InitOptions{KubeContext: kubeContext})
+	if err != nil {
+		return fmt.Errorf("cannot initialize kube: %s", err)
+	}
+
+	namespace := common.GetNamespace(CmdData.Namespace)
+
+	return deploy.RunLockStatus(CmdData.HelmReleaseName, namespace, kubeContext)
+}
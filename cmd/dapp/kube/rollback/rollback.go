@@ -0,0 +1,94 @@
+package rollback
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy"
+	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/kubedog/pkg/kube"
+	"github.com/spf13/cobra"
+)
+
+var CmdData struct {
+	HelmReleaseName string
+	Revision        int
+
+	Namespace   string
+	KubeContext string
+	Timeout     int
+}
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback HELM_RELEASE_NAME [REVISION]",
+		Short: "Rollback release to the revision (the last successful one by default) and watch it until ready",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			CmdData.HelmReleaseName = args[0]
+
+			if len(args) == 2 {
+				revision, err := strconv.Atoi(args[1])
+				if err != nil || revision <= 0 {
+					return fmt.Errorf("bad revision '%s': expected positive integer", args[1])
+				}
+				CmdData.Revision = revision
+			}
+
+			err := runRollback()
+			if err != nil {
+				return fmt.Errorf("rollback failed: %s", err)
+			}
+
+			return nil
+		},
+	}
+
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+	common.SetupKubeLock(&CommonCmdData, cmd)
+
+	cmd.PersistentFlags().StringVarP(&CmdData.Namespace, "namespace", "", "", "Kubernetes namespace")
+	cmd.PersistentFlags().StringVarP(&CmdData.KubeContext, "kube-context", "", "", "Kubernetes config context")
+	cmd.PersistentFlags().IntVarP(&CmdData.Timeout, "timeout", "t", 0, "watch timeout in seconds")
+
+	return cmd
+}
+
+func runRollback() error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	if err := lock.Init(); err != nil {
+		return err
+	}
+
+	if err := deploy.Init(); err != nil {
+		return err
+	}
+
+	kubeContext := os.Getenv("KUBECONTEXT")
+	if kubeContext == "" {
+		kubeContext = CmdData.KubeContext
+	}
+	err := kube.Init(kube.InitOptions{KubeContext: kubeContext})
+	if err != nil {
+		return fmt.Errorf("cannot initialize kube: %s", err)
+	}
+
+	namespace := common.GetNamespace(CmdData.Namespace)
+
+	return deploy.RunRollback(CmdData.HelmReleaseName, CmdData.Revision, namespace, deploy.RollbackOptions{
+		Timeout:         time.Duration(CmdData.Timeout) * time.Second,
+		KubeContext:     kubeContext,
+		KubeLock:        *CommonCmdData.KubeLock,
+		KubeLockTimeout: time.Duration(*CommonCmdData.KubeLockTimeout) * time.Second,
+	})
+}
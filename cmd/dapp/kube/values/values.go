@@ -0,0 +1,136 @@
+package values
+
+import (
+	"fmt"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	"github.com/flant/dapp/cmd/dapp/docker_authorizer"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy"
+	"github.com/flant/dapp/pkg/docker"
+	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/dapp/pkg/project_tmp_dir"
+	"github.com/flant/dapp/pkg/true_git"
+	"github.com/spf13/cobra"
+)
+
+var CmdData struct {
+	ValueKey string
+
+	Namespace string
+
+	Repo             string
+	RegistryUsername string
+	RegistryPassword string
+	WithoutRegistry  bool
+}
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "values [VALUE_KEY]",
+		Short: "Print service values (global.dapp.*) passed to the chart",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				CmdData.ValueKey = args[0]
+			}
+
+			err := runValues()
+			if err != nil {
+				return fmt.Errorf("values failed: %s", err)
+			}
+
+			return nil
+		},
+	}
+
+	common.SetupName(&CommonCmdData, cmd)
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	cmd.PersistentFlags().StringVarP(&CmdData.Namespace, "namespace", "", "", "Kubernetes namespace")
+
+	cmd.PersistentFlags().StringVarP(&CmdData.Repo, "repo", "", "", "Docker repository name to get images info from. CI_REGISTRY_IMAGE will be used by default if available.")
+	cmd.PersistentFlags().StringVarP(&CmdData.RegistryUsername, "registry-username", "", "", "Docker registry username")
+	cmd.PersistentFlags().StringVarP(&CmdData.RegistryPassword, "registry-password", "", "", "Docker registry password")
+	cmd.PersistentFlags().BoolVarP(&CmdData.WithoutRegistry, "without-registry", "", false, "Do not get images info from registry")
+
+	common.SetupTag(&CommonCmdData, cmd)
+
+	return cmd
+}
+
+func runValues() error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	if err := lock.Init(); err != nil {
+		return err
+	}
+
+	if err := true_git.Init(); err != nil {
+		return err
+	}
+
+	if err := deploy.Init(); err != nil {
+		return err
+	}
+
+	if err := docker.Init(docker_authorizer.GetHomeDockerConfigDir()); err != nil {
+		return err
+	}
+
+	projectDir, err := common.GetProjectDir(&CommonCmdData)
+	if err != nil {
+		return fmt.Errorf("getting project dir failed: %s", err)
+	}
+
+	projectName, err := common.GetProjectName(&CommonCmdData, projectDir)
+	if err != nil {
+		return fmt.Errorf("getting project name failed: %s", err)
+	}
+
+	projectTmpDir, err := project_tmp_dir.Get()
+	if err != nil {
+		return fmt.Errorf("getting project tmp dir failed: %s", err)
+	}
+	defer project_tmp_dir.Release(projectTmpDir)
+
+	dappfile, err := common.GetDappfile(projectDir)
+	if err != nil {
+		return fmt.Errorf("dappfile parsing failed: %s", err)
+	}
+
+	var repo string
+	if !CmdData.WithoutRegistry {
+		var err error
+		repo, err = common.GetRequiredRepoName(projectName, CmdData.Repo)
+		if err != nil {
+			return err
+		}
+
+		dockerAuthorizer, err := docker_authorizer.GetDeployDockerAuthorizer(projectTmpDir, CmdData.RegistryUsername, CmdData.RegistryPassword, repo)
+		if err != nil {
+			return err
+		}
+
+		if err := dockerAuthorizer.Login(repo); err != nil {
+			return fmt.Errorf("docker login failed: %s", err)
+		}
+	}
+
+	tag, err := common.GetDeployTag(&CommonCmdData, projectDir)
+	if err != nil {
+		return err
+	}
+
+	namespace := common.GetNamespace(CmdData.Namespace)
+
+	return deploy.RunValues(projectName, projectDir, namespace, repo, tag, CmdData.ValueKey, dappfile, deploy.ValuesOptions{
+		WithoutRegistry: CmdData.WithoutRegistry,
+	})
+}
@@ -2,10 +2,12 @@ package lint
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/flant/dapp/cmd/dapp/common"
 	"github.com/flant/dapp/pkg/dapp"
 	"github.com/flant/dapp/pkg/deploy"
+	"github.com/flant/dapp/pkg/deploy/schema"
 	"github.com/flant/dapp/pkg/lock"
 	"github.com/flant/dapp/pkg/true_git"
 	"github.com/spf13/cobra"
@@ -16,6 +18,11 @@ var CmdData struct {
 	SecretValues []string
 	Set          []string
 	SetString    []string
+	KubeVersion  string
+	CrdsDirs     []string
+
+	SecretProviders bool
+	Component       string
 }
 
 var CommonCmdData common.CmdData
@@ -41,6 +48,10 @@ func NewCmd() *cobra.Command {
 	cmd.PersistentFlags().StringArrayVarP(&CmdData.SecretValues, "secret-values", "", []string{}, "Additional helm secret values")
 	cmd.PersistentFlags().StringArrayVarP(&CmdData.Set, "set", "", []string{}, "Additional helm sets")
 	cmd.PersistentFlags().StringArrayVarP(&CmdData.SetString, "set-string", "", []string{}, "Additional helm STRING sets")
+	cmd.PersistentFlags().StringVarP(&CmdData.KubeVersion, "kube-version", "", deploy.DefaultLintKubeVersion, fmt.Sprintf("Kubernetes version to validate rendered manifests against (supported: %s)", strings.Join(schema.SupportedKubeVersions(), ", ")))
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.CrdsDirs, "crds-dir", "", []string{}, "Directory with CustomResourceDefinition manifests to validate custom resources against")
+	cmd.PersistentFlags().BoolVarP(&CmdData.SecretProviders, "secret-providers", "", false, "Lint with secret values from providers of secret-providers.yaml: exec commands are run and secret stores credentials are required")
+	cmd.PersistentFlags().StringVarP(&CmdData.Component, "component", "", "", "Lint component chart .helm/COMPONENT declared in .helm/components.yaml")
 
 	return cmd
 }
@@ -82,5 +93,10 @@ func runLint() error {
 		SecretValues: CmdData.SecretValues,
 		Set:          CmdData.Set,
 		SetString:    CmdData.SetString,
+		KubeVersion:  CmdData.KubeVersion,
+		CrdsDirs:     CmdData.CrdsDirs,
+
+		SecretProviders: CmdData.SecretProviders,
+		Component:       CmdData.Component,
 	})
 }
@@ -0,0 +1,83 @@
+package list
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/lock"
+)
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List active file locks of the host with holders and waiting processes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runList()
+			if err != nil {
+				return fmt.Errorf("locks list failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	return cmd
+}
+
+func runList() error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	if err := lock.Init(); err != nil {
+		return err
+	}
+
+	states, err := lock.ListFileLocks()
+	if err != nil {
+		return err
+	}
+
+	if len(states) == 0 {
+		fmt.Println("No active locks")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "LOCK\tSTATE\tMODE\tPID\tHOST\tSINCE\tCOMMAND\n")
+
+	printHolder := func(name, state string, holder *lock.FileLockHolder) {
+		mode := "exclusive"
+		if holder.ReadOnly {
+			mode = "shared"
+		}
+
+		if !holder.IsAlive() {
+			state = fmt.Sprintf("%s (dead)", state)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", name, state, mode, holder.Pid, holder.Host, holder.StartedAt.Format(time.RFC3339), holder.Command)
+	}
+
+	for _, state := range states {
+		for _, holder := range state.Holders {
+			printHolder(state.Name, "held", holder)
+		}
+		for _, waiter := range state.Waiters {
+			printHolder(state.Name, "waiting", waiter)
+		}
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,56 @@
+package release
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/dapp/pkg/logger"
+)
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release LOCK_NAME",
+		Short: "Forcibly release the file lock whose holder process is dead (for manual recovery)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runRelease(args[0])
+			if err != nil {
+				return fmt.Errorf("locks release failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	return cmd
+}
+
+func runRelease(name string) error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	if err := lock.Init(); err != nil {
+		return err
+	}
+
+	released, err := lock.ReleaseFileLock(name)
+	for _, holder := range released {
+		logger.LogF("Removed record of dead process %s\n", holder)
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.LogF("Lock `%s` released\n", name)
+
+	return nil
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -9,6 +8,7 @@ import (
 	"github.com/flant/dapp/cmd/dapp/bp"
 	"github.com/flant/dapp/cmd/dapp/build"
 	"github.com/flant/dapp/cmd/dapp/cleanup"
+	"github.com/flant/dapp/cmd/dapp/common"
 	"github.com/flant/dapp/cmd/dapp/completion"
 	"github.com/flant/dapp/cmd/dapp/deploy"
 	"github.com/flant/dapp/cmd/dapp/dismiss"
@@ -20,13 +20,35 @@ import (
 	"github.com/flant/dapp/cmd/dapp/reset"
 	"github.com/flant/dapp/cmd/dapp/sync"
 	"github.com/flant/dapp/cmd/dapp/version"
+	"github.com/flant/dapp/pkg/logger"
 	"github.com/flant/dapp/pkg/process_exterminator"
 
+	kube_diff "github.com/flant/dapp/cmd/dapp/kube/diff"
+	kube_history "github.com/flant/dapp/cmd/dapp/kube/history"
+	kube_lock_release "github.com/flant/dapp/cmd/dapp/kube/lock/release"
+	kube_lock_status "github.com/flant/dapp/cmd/dapp/kube/lock/status"
+	kube_rollback "github.com/flant/dapp/cmd/dapp/kube/rollback"
+	kube_values "github.com/flant/dapp/cmd/dapp/kube/values"
+
+	locks_list "github.com/flant/dapp/cmd/dapp/locks/list"
+	locks_release "github.com/flant/dapp/cmd/dapp/locks/release"
+
+	secret_diff "github.com/flant/dapp/cmd/dapp/secret/diff"
 	secret_edit "github.com/flant/dapp/cmd/dapp/secret/edit"
 	secret_extract "github.com/flant/dapp/cmd/dapp/secret/extract"
 	secret_generate "github.com/flant/dapp/cmd/dapp/secret/generate"
+	secret_get "github.com/flant/dapp/cmd/dapp/secret/get"
+	secret_git_setup "github.com/flant/dapp/cmd/dapp/secret/git_setup"
+	secret_identity_generate "github.com/flant/dapp/cmd/dapp/secret/identity_generate"
 	secret_key_generate "github.com/flant/dapp/cmd/dapp/secret/key_generate"
+	secret_migrate "github.com/flant/dapp/cmd/dapp/secret/migrate"
+	secret_recipient_add "github.com/flant/dapp/cmd/dapp/secret/recipient/add"
+	secret_recipient_list "github.com/flant/dapp/cmd/dapp/secret/recipient/list"
+	secret_recipient_remove "github.com/flant/dapp/cmd/dapp/secret/recipient/remove"
 	secret_regenerate "github.com/flant/dapp/cmd/dapp/secret/regenerate"
+	secret_set "github.com/flant/dapp/cmd/dapp/secret/set"
+	secret_status "github.com/flant/dapp/cmd/dapp/secret/status"
+	secret_unset "github.com/flant/dapp/cmd/dapp/secret/unset"
 
 	slug_namespace "github.com/flant/dapp/cmd/dapp/slug/namespace"
 	slug_release "github.com/flant/dapp/cmd/dapp/slug/release"
@@ -39,15 +61,21 @@ func main() {
 	trapTerminationSignals()
 
 	if err := process_exterminator.Init(); err != nil {
-		fmt.Fprintf(os.Stderr, "Process exterminator initialization error: %s\n", err)
+		logger.LogErrorF("Process exterminator initialization error: %s\n", err)
 		os.Exit(1)
 	}
 
 	cmd := &cobra.Command{
-		Use:          "dapp",
-		SilenceUsage: true,
+		Use:           "dapp",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return common.InitLog()
+		},
 	}
 
+	common.SetupLog(cmd)
+
 	cmd.AddCommand(
 		build.NewCmd(),
 		push.NewCmd(),
@@ -64,6 +92,8 @@ func main() {
 		cleanup.NewCmd(),
 		gc.NewCmd(),
 
+		kubeCmd(),
+		locksCmd(),
 		secretCmd(),
 		slugCmd(),
 
@@ -71,19 +101,79 @@ func main() {
 		version.NewCmd(),
 	)
 
-	if err := cmd.Execute(); err != nil {
+	err := cmd.Execute()
+	if err != nil {
+		logger.LogErrorF("Error: %s\n", err)
+	}
+
+	logger.Close()
+
+	if err != nil {
 		os.Exit(1)
 	}
 }
 
+func kubeCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "kube"}
+	cmd.AddCommand(
+		kube_diff.NewCmd(),
+		kube_history.NewCmd(),
+		kube_rollback.NewCmd(),
+		kube_values.NewCmd(),
+		kubeLockCmd(),
+	)
+
+	return cmd
+}
+
+func kubeLockCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "lock"}
+	cmd.AddCommand(
+		kube_lock_status.NewCmd(),
+		kube_lock_release.NewCmd(),
+	)
+
+	return cmd
+}
+
+func locksCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "locks"}
+	cmd.AddCommand(
+		locks_list.NewCmd(),
+		locks_release.NewCmd(),
+	)
+
+	return cmd
+}
+
 func secretCmd() *cobra.Command {
 	cmd := &cobra.Command{Use: "secret"}
 	cmd.AddCommand(
 		secret_key_generate.NewCmd(),
+		secret_identity_generate.NewCmd(),
 		secret_generate.NewCmd(),
 		secret_extract.NewCmd(),
 		secret_edit.NewCmd(),
+		secret_set.NewCmd(),
+		secret_get.NewCmd(),
+		secret_unset.NewCmd(),
 		secret_regenerate.NewCmd(),
+		secret_migrate.NewCmd(),
+		secret_status.NewCmd(),
+		secret_diff.NewCmd(),
+		secret_git_setup.NewCmd(),
+		secretRecipientCmd(),
+	)
+
+	return cmd
+}
+
+func secretRecipientCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "recipient"}
+	cmd.AddCommand(
+		secret_recipient_add.NewCmd(),
+		secret_recipient_remove.NewCmd(),
+		secret_recipient_list.NewCmd(),
 	)
 
 	return cmd
@@ -107,7 +197,8 @@ func trapTerminationSignals() {
 	go func() {
 		<-c
 
-		fmt.Fprintf(os.Stderr, "Interrupted\n")
+		logger.LogError("Interrupted")
+		logger.Close()
 
 		os.Exit(17)
 	}()
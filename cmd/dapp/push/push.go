@@ -43,6 +43,7 @@ func NewCmd() *cobra.Command {
 	common.SetupDir(&CommonCmdData, cmd)
 	common.SetupTmpDir(&CommonCmdData, cmd)
 	common.SetupHomeDir(&CommonCmdData, cmd)
+	common.SetupLockBackend(&CommonCmdData, cmd)
 	common.SetupSSHKey(&CommonCmdData, cmd)
 
 	cmd.PersistentFlags().StringVarP(&CmdData.Repo, "repo", "", "", "Docker repository name to push images to. CI_REGISTRY_IMAGE will be used by default if available.")
@@ -67,6 +68,10 @@ func runPush(dimgsToProcess []string) error {
 		return err
 	}
 
+	if err := common.InitLockBackend(&CommonCmdData); err != nil {
+		return err
+	}
+
 	if err := true_git.Init(); err != nil {
 		return err
 	}
@@ -111,7 +116,7 @@ func runPush(dimgsToProcess []string) error {
 		return err
 	}
 
-	if err := ssh_agent.Init(*CommonCmdData.SSHKeys); err != nil {
+	if err := common.InitSSHAgent(&CommonCmdData, projectDir, dappfile); err != nil {
 		return fmt.Errorf("cannot initialize ssh agent: %s", err)
 	}
 	defer func() {
@@ -128,7 +133,7 @@ func runPush(dimgsToProcess []string) error {
 
 	pushOpts := build.PushOptions{TagOptions: tagOpts, WithStages: CmdData.WithStages}
 
-	c := build.NewConveyor(dappfile, dimgsToProcess, projectDir, projectName, projectBuildDir, projectTmpDir, ssh_agent.SSHAuthSock, dockerAuthorizer)
+	c := build.NewConveyor(dappfile, dimgsToProcess, projectDir, projectName, projectBuildDir, projectTmpDir, ssh_agent.SSHAuthSock, ssh_agent.SSHConfigDir, dockerAuthorizer)
 	if err = c.Push(repo, pushOpts); err != nil {
 		return err
 	}
@@ -16,6 +16,13 @@ var CmdData struct {
 	SecretValues []string
 	Set          []string
 	SetString    []string
+
+	AddAnnotations []string
+	AddLabels      []string
+
+	InjectGlobalMetadata bool
+	SecretProviders      bool
+	Component            string
 }
 
 var CommonCmdData common.CmdData
@@ -41,6 +48,11 @@ func NewCmd() *cobra.Command {
 	cmd.PersistentFlags().StringArrayVarP(&CmdData.SecretValues, "secret-values", "", []string{}, "Additional helm secret values")
 	cmd.PersistentFlags().StringArrayVarP(&CmdData.Set, "set", "", []string{}, "Additional helm sets")
 	cmd.PersistentFlags().StringArrayVarP(&CmdData.SetString, "set-string", "", []string{}, "Additional helm STRING sets")
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.AddAnnotations, "add-annotation", "", []string{}, "Add annotation to deployed resources (format: key=value), see --inject-global-metadata")
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.AddLabels, "add-label", "", []string{}, "Add label to deployed resources (format: key=value), see --inject-global-metadata")
+	cmd.PersistentFlags().BoolVarP(&CmdData.InjectGlobalMetadata, "inject-global-metadata", "", false, "Inject global annotations and labels into all resources of the chart, not only into templates with dapp_global_annotations and dapp_global_labels helpers. The release is installed from templates rendered in advance: .Release.IsUpgrade, .Release.Revision and .Capabilities of the cluster are not available and NOTES.txt is skipped")
+	cmd.PersistentFlags().BoolVarP(&CmdData.SecretProviders, "secret-providers", "", false, "Get secret values from providers of secret-providers.yaml: exec commands are run and fetched secrets are printed in plain text")
+	cmd.PersistentFlags().StringVarP(&CmdData.Component, "component", "", "", "Render component chart .helm/COMPONENT declared in .helm/components.yaml")
 
 	return cmd
 }
@@ -78,9 +90,14 @@ func runRender() error {
 	}
 
 	return deploy.RunRender(projectName, projectDir, dappfile, deploy.RenderOptions{
-		Values:       CmdData.Values,
-		SecretValues: CmdData.SecretValues,
-		Set:          CmdData.Set,
-		SetString:    CmdData.SetString,
+		Values:               CmdData.Values,
+		SecretValues:         CmdData.SecretValues,
+		Set:                  CmdData.Set,
+		SetString:            CmdData.SetString,
+		AddAnnotations:       CmdData.AddAnnotations,
+		AddLabels:            CmdData.AddLabels,
+		InjectGlobalMetadata: CmdData.InjectGlobalMetadata,
+		SecretProviders:      CmdData.SecretProviders,
+		Component:            CmdData.Component,
 	})
 }
@@ -0,0 +1,170 @@
+package secret
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"k8s.io/kubernetes/pkg/util/file"
+
+	"github.com/flant/dapp/pkg/deploy/secret"
+	"github.com/flant/dapp/pkg/logger"
+)
+
+// GetProjectSecretFilesPaths returns secret files (secret dirs) and secret values files of project chart and component charts.
+func GetProjectSecretFilesPaths(projectDir string) ([]string, []string, error) {
+	var secretFilesPaths, secretValuesPaths []string
+
+	helmChartPath := filepath.Join(projectDir, ".helm")
+
+	chartsPaths := []string{helmChartPath}
+	componentsChartsPaths, err := filepath.Glob(filepath.Join(helmChartPath, "*", "Chart.yaml"))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, path := range componentsChartsPaths {
+		chartsPaths = append(chartsPaths, filepath.Dir(path))
+	}
+
+	for _, chartPath := range chartsPaths {
+		defaultSecretValuesPath := filepath.Join(chartPath, "secret-values.yaml")
+		isDefaultSecretValuesExist, err := file.FileExists(defaultSecretValuesPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if isDefaultSecretValuesExist {
+			secretValuesPaths = append(secretValuesPaths, defaultSecretValuesPath)
+		}
+
+		secretDirectory := filepath.Join(chartPath, "secret")
+		isSecretDirectoryExist, err := file.FileExists(secretDirectory)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if isSecretDirectoryExist {
+			err = filepath.Walk(secretDirectory,
+				func(path string, info os.FileInfo, err error) error {
+					if err != nil {
+						return err
+					}
+
+					if !info.IsDir() {
+						secretFilesPaths = append(secretFilesPaths, path)
+					}
+
+					return nil
+				})
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return secretFilesPaths, secretValuesPaths, nil
+}
+
+// RegenerateProjectSecretFiles decodes project secret files with old manager and encodes with new manager.
+func RegenerateProjectSecretFiles(newManager, oldManager secret.Manager, projectDir string, secretValuesPaths ...string) error {
+	secretFilesPaths, projectSecretValuesPaths, err := GetProjectSecretFilesPaths(projectDir)
+	if err != nil {
+		return err
+	}
+	secretValuesPaths = append(secretValuesPaths, projectSecretValuesPaths...)
+
+	return RewriteSecretFiles(
+		secretFilesPaths,
+		secretValuesPaths,
+		regenerateFunc(oldManager.Extract, newManager.Generate),
+		regenerateFunc(oldManager.ExtractYamlData, newManager.GenerateYamlData),
+	)
+}
+
+func regenerateFunc(decodeFunc, encodeFunc func([]byte) ([]byte, error)) func([]byte) ([]byte, error) {
+	return func(fileData []byte) ([]byte, error) {
+		data, err := decodeFunc(fileData)
+		if err != nil {
+			return nil, fmt.Errorf("check old encryption key and file data: %s", err)
+		}
+
+		return encodeFunc(data)
+	}
+}
+
+// RewriteSecretFiles transforms data of secret files and secret values files and saves files after all data is transformed.
+func RewriteSecretFiles(secretFilesPaths, secretValuesPaths []string, filesFunc, valuesFunc func([]byte) ([]byte, error)) error {
+	rewrittenFilesData := map[string][]byte{}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	secretFilesData, err := readFilesToDecode(secretFilesPaths, pwd)
+	if err != nil {
+		return err
+	}
+
+	secretValuesFilesData, err := readFilesToDecode(secretValuesPaths, pwd)
+	if err != nil {
+		return err
+	}
+
+	if err := rewriteSecrets(secretFilesData, rewrittenFilesData, filesFunc); err != nil {
+		return err
+	}
+
+	if err := rewriteSecrets(secretValuesFilesData, rewrittenFilesData, valuesFunc); err != nil {
+		return err
+	}
+
+	for filePath, fileData := range rewrittenFilesData {
+		logger.LogF("save file '%s'\n", filePath)
+
+		fileData = append(bytes.TrimSpace(fileData), []byte("\n")...)
+		if err := ioutil.WriteFile(filePath, fileData, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rewriteSecrets(filesData, rewrittenFilesData map[string][]byte, rewriteFunc func([]byte) ([]byte, error)) error {
+	for filePath, fileData := range filesData {
+		logger.LogF("regenerate file '%s' data\n", filePath)
+
+		resultData, err := rewriteFunc(fileData)
+		if err != nil {
+			return fmt.Errorf("file '%s': %s", filePath, err)
+		}
+
+		rewrittenFilesData[filePath] = resultData
+	}
+
+	return nil
+}
+
+func readFilesToDecode(filePaths []string, pwd string) (map[string][]byte, error) {
+	filesData := map[string][]byte{}
+	for _, filePath := range filePaths {
+		fileData, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if filepath.IsAbs(filePath) {
+			filePath, err = filepath.Rel(pwd, filePath)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		filesData[filePath] = bytes.TrimSpace(fileData)
+	}
+
+	return filesData, nil
+}
@@ -0,0 +1,249 @@
+package secret
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	secret_common "github.com/flant/dapp/cmd/dapp/secret/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy/secret"
+)
+
+var CmdData struct {
+	Plain bool
+}
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [REV1] [REV2]",
+		Short: "Show key level diff of decrypted secret files between git revisions",
+		Long: `Show key level diff of decrypted secret files between git revisions.
+REV1 is HEAD by default, working tree is compared if REV2 is not specified.
+Values are masked unless --plain is specified.`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rev1, rev2 := "HEAD", ""
+			if len(args) > 0 {
+				rev1 = args[0]
+			}
+			if len(args) > 1 {
+				rev2 = args[1]
+			}
+
+			err := runSecretDiff(rev1, rev2)
+			if err != nil {
+				return fmt.Errorf("secret diff failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	cmd.PersistentFlags().BoolVarP(&CmdData.Plain, "plain", "", false, "Show decrypted values instead of masks")
+
+	return cmd
+}
+
+type revisionFiles struct {
+	Rev        string
+	RepoDir    string
+	ProjectRel string
+	Files      map[string]bool
+}
+
+func runSecretDiff(rev1, rev2 string) error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	projectDir, err := common.GetProjectDir(&CommonCmdData)
+	if err != nil {
+		return fmt.Errorf("getting project dir failed: %s", err)
+	}
+
+	m, err := secret.GetManager(projectDir)
+	if err != nil {
+		return err
+	}
+
+	repoDir, err := secret_common.GitOutput(projectDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return err
+	}
+
+	projectRel, err := filepath.Rel(repoDir, projectDir)
+	if err != nil {
+		return err
+	}
+
+	oldFiles, err := getRevisionSecretFiles(repoDir, projectDir, projectRel, rev1)
+	if err != nil {
+		return err
+	}
+
+	newFiles, err := getRevisionSecretFiles(repoDir, projectDir, projectRel, rev2)
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	for path := range oldFiles.Files {
+		paths = append(paths, path)
+	}
+	for path := range newFiles.Files {
+		if !oldFiles.Files[path] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		isValues := filepath.Base(path) == "secret-values.yaml"
+
+		oldData, err := oldFiles.decodedData(m, path, isValues)
+		if err != nil {
+			return err
+		}
+
+		newData, err := newFiles.decodedData(m, path, isValues)
+		if err != nil {
+			return err
+		}
+
+		var changes []*secret.ValueChange
+		if isValues {
+			changes, err = secret.ValuesDiff(oldData, newData)
+			if err != nil {
+				return fmt.Errorf("%s: %s", path, err)
+			}
+		} else {
+			changes = secret.FileDiff(oldData, newData, oldFiles.Files[path], newFiles.Files[path])
+		}
+
+		secret.PrintValuesDiff(os.Stdout, path, changes, CmdData.Plain)
+	}
+
+	return nil
+}
+
+// getRevisionSecretFiles returns secret files paths relative to project dir, working tree files are returned for empty rev.
+func getRevisionSecretFiles(repoDir, projectDir, projectRel, rev string) (*revisionFiles, error) {
+	res := &revisionFiles{Rev: rev, RepoDir: repoDir, ProjectRel: projectRel, Files: map[string]bool{}}
+
+	if rev == "" {
+		secretFilesPaths, secretValuesPaths, err := secret_common.GetProjectSecretFilesPaths(projectDir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range append(secretFilesPaths, secretValuesPaths...) {
+			relPath, err := filepath.Rel(projectDir, path)
+			if err != nil {
+				return nil, err
+			}
+
+			res.Files[filepath.ToSlash(relPath)] = true
+		}
+
+		return res, nil
+	}
+
+	output, err := secret_common.GitOutput(repoDir, "ls-tree", "-r", "--name-only", rev, "--", filepath.ToSlash(filepath.Join(projectRel, ".helm")))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		relPath, err := filepath.Rel(projectRel, line)
+		if err != nil {
+			return nil, err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if isSecretFilePath(relPath) {
+			res.Files[relPath] = true
+		}
+	}
+
+	return res, nil
+}
+
+// isSecretFilePath checks path relative to project dir: secret values and secret dir files of project chart and component charts.
+func isSecretFilePath(path string) bool {
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[0] != ".helm" {
+		return false
+	}
+
+	parts = parts[1:]
+	if len(parts) > 1 && parts[0] != "secret" && parts[0] != "templates" && parts[0] != "charts" {
+		// Component chart
+		parts = parts[1:]
+	}
+
+	if len(parts) == 1 {
+		return parts[0] == "secret-values.yaml"
+	}
+
+	return parts[0] == "secret"
+}
+
+func (f *revisionFiles) decodedData(m secret.Manager, path string, isValues bool) ([]byte, error) {
+	if !f.Files[path] {
+		return nil, nil
+	}
+
+	var data []byte
+	var err error
+	if f.Rev == "" {
+		data, err = ioutil.ReadFile(filepath.Join(f.RepoDir, f.ProjectRel, path))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", f.Rev, filepath.ToSlash(filepath.Join(f.ProjectRel, path))))
+		cmd.Dir = f.RepoDir
+		cmd.Stderr = os.Stderr
+		data, err = cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s from revision %s: %s", path, f.Rev, err)
+		}
+	}
+
+	data = bytes.TrimSpace(data)
+
+	var decodedData []byte
+	if isValues {
+		decodedData, err = m.ExtractYamlData(data)
+	} else {
+		decodedData, err = m.Extract(data)
+	}
+	if err != nil {
+		revision := f.Rev
+		if revision == "" {
+			revision = "working tree"
+		}
+
+		return nil, fmt.Errorf("%s (%s): %s", path, revision, err)
+	}
+
+	return decodedData, nil
+}
@@ -293,7 +293,7 @@ func unmarshalYaml(data []byte) (yaml.MapSlice, error) {
 
 func mergeYamlEncodedData(d, eD, newD, newED interface{}) (interface{}, error) {
 	dType := reflect.TypeOf(d)
-	newDType := reflect.TypeOf(d)
+	newDType := reflect.TypeOf(newD)
 
 	if dType != newDType {
 		return newED, nil
@@ -351,6 +351,29 @@ func mergeYamlEncodedData(d, eD, newD, newED interface{}) (interface{}, error) {
 		resultMapItem.Value = resultValue
 
 		return resultMapItem, nil
+	case []interface{}:
+		newDList := newD.([]interface{})
+		newEDList := newED.([]interface{})
+		dList := d.([]interface{})
+		eDList := eD.([]interface{})
+
+		// Unchanged elements keep encoded data by index
+		resultList := make([]interface{}, len(newDList))
+		for ind := range newDList {
+			if ind >= len(dList) || ind >= len(eDList) {
+				resultList[ind] = newEDList[ind]
+				continue
+			}
+
+			result, err := mergeYamlEncodedData(dList[ind], eDList[ind], newDList[ind], newEDList[ind])
+			if err != nil {
+				return nil, err
+			}
+
+			resultList[ind] = result
+		}
+
+		return resultList, nil
 	default:
 		if !reflect.DeepEqual(d, newD) {
 			return newED, nil
@@ -117,7 +117,7 @@ func secretExtract(m secret.Manager, options *secret_common.GenerateOptions) err
 			}
 		}
 
-		fmt.Printf(string(data))
+		fmt.Print(string(data))
 	}
 
 	return nil
@@ -0,0 +1,77 @@
+package secret
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	secret_common "github.com/flant/dapp/cmd/dapp/secret/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy/secret"
+)
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get FILE_PATH KEY_PATH",
+		Short: "Decrypt and print value of secret values file by dot separated key path",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runSecretGet(args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("secret get failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	return cmd
+}
+
+func runSecretGet(filePath, keyPath string) error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	projectDir, err := common.GetProjectDir(&CommonCmdData)
+	if err != nil {
+		return fmt.Errorf("getting project dir failed: %s", err)
+	}
+
+	path, err := secret.ParseValuePath(keyPath)
+	if err != nil {
+		return err
+	}
+
+	data, err := secret_common.ReadFileData(filePath)
+	if err != nil {
+		return err
+	}
+
+	m, err := secret.GetManager(projectDir)
+	if err != nil {
+		return err
+	}
+
+	value, err := secret.GetValue(m, data, path)
+	if err != nil {
+		return err
+	}
+
+	if terminal.IsTerminal(int(os.Stdout.Fd())) && !bytes.HasSuffix(value, []byte("\n")) {
+		value = append(value, []byte("\n")...)
+	}
+
+	fmt.Print(string(value))
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package secret
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	secret_common "github.com/flant/dapp/cmd/dapp/secret/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/logger"
+)
+
+const (
+	valuesDiffDriver = "dapp-secret-values"
+	filesDiffDriver  = "dapp-secret"
+)
+
+var CmdData struct {
+	DappBin string
+}
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "git-setup",
+		Short: "Setup local git textconv driver to show decrypted secret files in git diff and git log -p",
+		Long: `Setup local git textconv driver to show decrypted secret files in git diff and git log -p.
+Drivers are configured in the local repository config and attributes are added into .git/info/attributes,
+so repository files are not changed and the setup is not shared with other users.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runSecretGitSetup()
+			if err != nil {
+				return fmt.Errorf("secret git-setup failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	cmd.PersistentFlags().StringVarP(&CmdData.DappBin, "dapp-bin", "", "dapp", "Dapp binary used by git to decrypt secret files")
+
+	return cmd
+}
+
+func runSecretGitSetup() error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	projectDir, err := common.GetProjectDir(&CommonCmdData)
+	if err != nil {
+		return fmt.Errorf("getting project dir failed: %s", err)
+	}
+
+	repoDir, err := secret_common.GitOutput(projectDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return err
+	}
+
+	gitDir, err := secret_common.GitOutput(projectDir, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return err
+	}
+
+	projectRel, err := filepath.Rel(repoDir, projectDir)
+	if err != nil {
+		return err
+	}
+
+	// Textconv is run in the repository root with the path of the temporary file with blob data
+	dirOption := ""
+	if projectRel != "." {
+		dirOption = fmt.Sprintf(" --dir %s", projectRel)
+	}
+
+	drivers := map[string]string{
+		valuesDiffDriver: fmt.Sprintf("%s secret extract%s --values --file-path", CmdData.DappBin, dirOption),
+		filesDiffDriver:  fmt.Sprintf("%s secret extract%s --file-path", CmdData.DappBin, dirOption),
+	}
+
+	for _, driver := range []string{valuesDiffDriver, filesDiffDriver} {
+		if _, err := secret_common.GitOutput(projectDir, "config", "--local", fmt.Sprintf("diff.%s.textconv", driver), drivers[driver]); err != nil {
+			return err
+		}
+
+		// Decrypted secrets must not be cached in git notes
+		if _, err := secret_common.GitOutput(projectDir, "config", "--local", fmt.Sprintf("diff.%s.cachetextconv", driver), "false"); err != nil {
+			return err
+		}
+
+		logger.LogF("git diff driver %s: %s\n", driver, drivers[driver])
+	}
+
+	prefix := ""
+	if projectRel != "." {
+		prefix = filepath.ToSlash(projectRel) + "/"
+	}
+
+	attributes := []string{
+		fmt.Sprintf("%s.helm/secret-values.yaml diff=%s", prefix, valuesDiffDriver),
+		fmt.Sprintf("%s.helm/*/secret-values.yaml diff=%s", prefix, valuesDiffDriver),
+		fmt.Sprintf("%s.helm/secret/** diff=%s", prefix, filesDiffDriver),
+		fmt.Sprintf("%s.helm/*/secret/** diff=%s", prefix, filesDiffDriver),
+	}
+
+	return addGitAttributes(filepath.Join(gitDir, "info", "attributes"), attributes)
+}
+
+func addGitAttributes(path string, attributes []string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	for _, attribute := range attributes {
+		if existing[attribute] {
+			continue
+		}
+
+		content += attribute + "\n"
+		logger.LogF("add attribute '%s' into %s\n", attribute, path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, []byte(content), 0644)
+}
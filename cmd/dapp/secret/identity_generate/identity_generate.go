@@ -0,0 +1,40 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/pkg/deploy/secret"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "identity-keygen",
+		Short: "Generate X25519 key pair: public key is added as recipient, private key decrypts secrets",
+		Long: `Generate X25519 key pair: public key is added as recipient, private key decrypts secrets.
+Keys are printed as age-style strings (age1... and AGE-SECRET-KEY-1...), age keys may be used as well.
+Secrets are encrypted in dapp format: age tool cannot decrypt or produce secret values.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runSecretIdentityGenerate()
+			if err != nil {
+				return fmt.Errorf("secret identity-keygen failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func runSecretIdentityGenerate() error {
+	identity, err := secret.GenerateIdentity()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("# public key: %s\n", identity.Recipient())
+	fmt.Println(identity)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	secret_common "github.com/flant/dapp/cmd/dapp/secret/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy/secret"
+)
+
+var CmdData struct {
+	OldKey string
+}
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [EXTRA_SECRET_VALUES_FILE_PATH...]",
+		Short: "Re-encrypt secret files data encrypted in legacy format or with non-primary key of the key ring",
+		Long: `Re-encrypt secret files data encrypted in legacy format or with non-primary key of the key ring.
+
+Legacy data has no key id and is decrypted with the primary key of the single key ring.
+Migrate legacy data before adding a new primary key to the key ring or pass the key of legacy data with --old-key.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runSecretMigrate(args...)
+			if err != nil {
+				return fmt.Errorf("secret migrate failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	cmd.PersistentFlags().StringVarP(&CmdData.OldKey, "old-key", "", "", "Secret key to decrypt legacy data without key id instead of the primary key")
+
+	return cmd
+}
+
+func runSecretMigrate(secretValuesPaths ...string) error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	projectDir, err := common.GetProjectDir(&CommonCmdData)
+	if err != nil {
+		return fmt.Errorf("getting project dir failed: %s", err)
+	}
+
+	m, err := secret.GetManagerWithOptions(projectDir, secret.NewManagerOptions{LegacyKey: []byte(CmdData.OldKey)})
+	if err != nil {
+		return err
+	}
+
+	secretFilesPaths, projectSecretValuesPaths, err := secret_common.GetProjectSecretFilesPaths(projectDir)
+	if err != nil {
+		return err
+	}
+	secretValuesPaths = append(secretValuesPaths, projectSecretValuesPaths...)
+
+	return secret_common.RewriteSecretFiles(secretFilesPaths, secretValuesPaths, m.Migrate, m.MigrateYamlData)
+}
@@ -0,0 +1,103 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	secret_common "github.com/flant/dapp/cmd/dapp/secret/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy/secret"
+	"github.com/flant/dapp/pkg/logger"
+	pkg_secret "github.com/flant/dapp/pkg/secret"
+)
+
+var CmdData struct {
+	SecretValues []string
+}
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add PUBLIC_KEY [NAME]",
+		Short: "Add public key secrets are encrypted to and re-encrypt secret files",
+		Long: `Add public key secrets are encrypted to and re-encrypt secret files.
+Adding the first recipient switches project from encryption key to recipients: secret files are re-encrypted with the key.
+PUBLIC_KEY is age-style X25519 public key (age1...), see dapp secret identity-keygen.
+Secrets are encrypted in dapp format: age tool cannot decrypt or produce secret values.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if len(args) == 2 {
+				name = args[1]
+			}
+
+			err := runSecretRecipientAdd(args[0], name)
+			if err != nil {
+				return fmt.Errorf("secret recipient add failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.SecretValues, "secret-values", "", []string{}, "Extra secret values file to re-encrypt (can specify multiple)")
+
+	return cmd
+}
+
+func runSecretRecipientAdd(publicKey, name string) error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	projectDir, err := common.GetProjectDir(&CommonCmdData)
+	if err != nil {
+		return fmt.Errorf("getting project dir failed: %s", err)
+	}
+
+	r, err := pkg_secret.ParseX25519Recipient(publicKey)
+	if err != nil {
+		return err
+	}
+
+	recipients, err := secret.GetRecipients(projectDir)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range recipients {
+		if existing.X25519Recipient.String() == r.String() {
+			return fmt.Errorf("recipient %s already added", r)
+		}
+	}
+
+	newRecipients := append(recipients, &secret.Recipient{X25519Recipient: r, Name: name})
+
+	oldManager, err := secret.GetManager(projectDir)
+	if err != nil {
+		return err
+	}
+
+	newManager, err := secret.NewRecipientsManager(projectDir, newRecipients)
+	if err != nil {
+		return err
+	}
+
+	if err := secret_common.RegenerateProjectSecretFiles(newManager, oldManager, projectDir, CmdData.SecretValues...); err != nil {
+		return err
+	}
+
+	if err := secret.SaveRecipients(projectDir, newRecipients); err != nil {
+		return err
+	}
+
+	logger.LogF("recipient %s added\n", r)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy/secret"
+)
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List public keys secrets are encrypted to",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runSecretRecipientList()
+			if err != nil {
+				return fmt.Errorf("secret recipient list failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	return cmd
+}
+
+func runSecretRecipientList() error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	projectDir, err := common.GetProjectDir(&CommonCmdData)
+	if err != nil {
+		return fmt.Errorf("getting project dir failed: %s", err)
+	}
+
+	recipients, err := secret.GetRecipients(projectDir)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range recipients {
+		if r.Name != "" {
+			fmt.Printf("%s %s\n", r.X25519Recipient, r.Name)
+		} else {
+			fmt.Printf("%s\n", r.X25519Recipient)
+		}
+	}
+
+	return nil
+}
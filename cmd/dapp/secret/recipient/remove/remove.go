@@ -0,0 +1,101 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	secret_common "github.com/flant/dapp/cmd/dapp/secret/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy/secret"
+	"github.com/flant/dapp/pkg/logger"
+)
+
+var CmdData struct {
+	SecretValues []string
+}
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove PUBLIC_KEY|NAME",
+		Short: "Remove public key secrets are encrypted to and re-encrypt secret files",
+		Long: `Remove public key secrets are encrypted to and re-encrypt secret files.
+Removed recipient is still able to decrypt secrets from the repository history: rotate secret values.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runSecretRecipientRemove(args[0])
+			if err != nil {
+				return fmt.Errorf("secret recipient remove failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	cmd.PersistentFlags().StringArrayVarP(&CmdData.SecretValues, "secret-values", "", []string{}, "Extra secret values file to re-encrypt (can specify multiple)")
+
+	return cmd
+}
+
+func runSecretRecipientRemove(publicKeyOrName string) error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	projectDir, err := common.GetProjectDir(&CommonCmdData)
+	if err != nil {
+		return fmt.Errorf("getting project dir failed: %s", err)
+	}
+
+	recipients, err := secret.GetRecipients(projectDir)
+	if err != nil {
+		return err
+	}
+
+	var newRecipients, removedRecipients []*secret.Recipient
+	for _, r := range recipients {
+		if r.X25519Recipient.String() == publicKeyOrName || (r.Name != "" && r.Name == publicKeyOrName) {
+			removedRecipients = append(removedRecipients, r)
+		} else {
+			newRecipients = append(newRecipients, r)
+		}
+	}
+
+	if len(removedRecipients) == 0 {
+		return fmt.Errorf("recipient '%s' not found in %s", publicKeyOrName, secret.ProjectRecipientsFile)
+	}
+
+	if len(newRecipients) == 0 {
+		return fmt.Errorf("cannot remove the last recipient: secrets would not be encrypted to anyone")
+	}
+
+	oldManager, err := secret.GetManager(projectDir)
+	if err != nil {
+		return err
+	}
+
+	newManager, err := secret.NewRecipientsManager(projectDir, newRecipients)
+	if err != nil {
+		return err
+	}
+
+	if err := secret_common.RegenerateProjectSecretFiles(newManager, oldManager, projectDir, CmdData.SecretValues...); err != nil {
+		return err
+	}
+
+	if err := secret.SaveRecipients(projectDir, newRecipients); err != nil {
+		return err
+	}
+
+	for _, r := range removedRecipients {
+		logger.LogF("recipient %s removed\n", r.X25519Recipient)
+	}
+
+	return nil
+}
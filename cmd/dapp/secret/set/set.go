@@ -0,0 +1,103 @@
+package secret
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	secret_common "github.com/flant/dapp/cmd/dapp/secret/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy/secret"
+)
+
+var CmdData struct {
+	ValueFile string
+}
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set FILE_PATH KEY_PATH[=VALUE]",
+		Short: "Encrypt and set value of secret values file by dot separated key path",
+		Long: `Encrypt and set value of secret values file by dot separated key path (dot in a key is escaped with backslash).
+Value is read from --value-file or stdin if not specified in the argument.
+Comments, key order and other encrypted values of the file are kept.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runSecretSet(args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("secret set failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	cmd.PersistentFlags().StringVarP(&CmdData.ValueFile, "value-file", "", "", "Read value from the file (e.g. multi-line certificate)")
+
+	return cmd
+}
+
+func runSecretSet(filePath, keyPathArg string) error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	projectDir, err := common.GetProjectDir(&CommonCmdData)
+	if err != nil {
+		return fmt.Errorf("getting project dir failed: %s", err)
+	}
+
+	keyPath, value, hasValue := keyPathArg, "", false
+	if ind := strings.Index(keyPathArg, "="); ind != -1 {
+		keyPath, value, hasValue = keyPathArg[:ind], keyPathArg[ind+1:], true
+	}
+
+	path, err := secret.ParseValuePath(keyPath)
+	if err != nil {
+		return err
+	}
+
+	var valueData []byte
+	switch {
+	case hasValue && CmdData.ValueFile != "":
+		return fmt.Errorf("value should be specified either in the argument or with --value-file")
+	case hasValue:
+		valueData = []byte(value)
+	case CmdData.ValueFile != "":
+		valueData, err = ioutil.ReadFile(CmdData.ValueFile)
+		if err != nil {
+			return err
+		}
+	default:
+		valueData, err = secret_common.ReadStdin()
+		if err != nil {
+			return err
+		}
+	}
+
+	m, err := secret.GetManager(projectDir)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	newData, err := secret.SetValue(m, data, path, valueData)
+	if err != nil {
+		return err
+	}
+
+	return secret_common.SaveGeneratedData(filePath, newData)
+}
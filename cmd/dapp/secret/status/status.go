@@ -0,0 +1,143 @@
+package secret
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	secret_common "github.com/flant/dapp/cmd/dapp/secret/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy/secret"
+)
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [EXTRA_SECRET_VALUES_FILE_PATH...]",
+		Short: "Show secret files and values which are not encrypted with the primary key",
+		Long: `Show secret files and values which are not encrypted with the primary key of the key ring
+(or not encrypted to recipients in recipients mode). Use dapp secret migrate to re-encrypt them.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runSecretStatus(args...)
+			if err != nil {
+				return fmt.Errorf("secret status failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	return cmd
+}
+
+func runSecretStatus(secretValuesPaths ...string) error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	projectDir, err := common.GetProjectDir(&CommonCmdData)
+	if err != nil {
+		return fmt.Errorf("getting project dir failed: %s", err)
+	}
+
+	var primaryKeyId string
+	if isRecipientsMode, err := secret.IsRecipientsMode(projectDir); err != nil {
+		return err
+	} else if isRecipientsMode {
+		primaryKeyId = secret.RecipientsDataKeyId
+	} else {
+		primaryKeyId, err = secret.GetPrimaryKeyId(projectDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	secretFilesPaths, projectSecretValuesPaths, err := secret_common.GetProjectSecretFilesPaths(projectDir)
+	if err != nil {
+		return err
+	}
+	secretValuesPaths = append(secretValuesPaths, projectSecretValuesPaths...)
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	var total, nonPrimary int
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "FILE\tVALUE\tKEY ID\n")
+
+	printNonPrimary := func(path string, keyIds []*secret.ValueKeyId) {
+		for _, valueKeyId := range keyIds {
+			total++
+			if valueKeyId.KeyId == primaryKeyId {
+				continue
+			}
+			nonPrimary++
+
+			valuePath := valueKeyId.Path
+			if valuePath == "" {
+				valuePath = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", relativePath(pwd, path), valuePath, valueKeyId.KeyId)
+		}
+	}
+
+	for _, path := range secretFilesPaths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		printNonPrimary(path, []*secret.ValueKeyId{{KeyId: secret.DataKeyId(data)}})
+	}
+
+	for _, path := range secretValuesPaths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		keyIds, err := secret.YamlDataKeyIds(data)
+		if err != nil {
+			return fmt.Errorf("bad secret values file %s: %s", path, err)
+		}
+
+		printNonPrimary(path, keyIds)
+	}
+
+	if nonPrimary > 0 {
+		w.Flush()
+		fmt.Println()
+	}
+
+	fmt.Printf("%d of %d secret values are not encrypted with primary key %s\n", nonPrimary, total, primaryKeyId)
+
+	return nil
+}
+
+func relativePath(pwd, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(pwd, path); err == nil {
+			return rel
+		}
+	}
+
+	return path
+}
@@ -0,0 +1,58 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flant/dapp/cmd/dapp/common"
+	secret_common "github.com/flant/dapp/cmd/dapp/secret/common"
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/deploy/secret"
+)
+
+var CommonCmdData common.CmdData
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unset FILE_PATH KEY_PATH",
+		Short: "Remove value of secret values file by dot separated key path",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runSecretUnset(args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("secret unset failed: %s", err)
+			}
+			return nil
+		},
+	}
+
+	common.SetupDir(&CommonCmdData, cmd)
+	common.SetupTmpDir(&CommonCmdData, cmd)
+	common.SetupHomeDir(&CommonCmdData, cmd)
+
+	return cmd
+}
+
+func runSecretUnset(filePath, keyPath string) error {
+	if err := dapp.Init(*CommonCmdData.TmpDir, *CommonCmdData.HomeDir); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+
+	path, err := secret.ParseValuePath(keyPath)
+	if err != nil {
+		return err
+	}
+
+	data, err := secret_common.ReadFileData(filePath)
+	if err != nil {
+		return err
+	}
+
+	newData, err := secret.UnsetValue(data, path)
+	if err != nil {
+		return err
+	}
+
+	return secret_common.SaveGeneratedData(filePath, newData)
+}
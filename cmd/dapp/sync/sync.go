@@ -41,6 +41,8 @@ func NewCmd() *cobra.Command {
 	common.SetupDir(&CommonCmdData, cmd)
 	common.SetupTmpDir(&CommonCmdData, cmd)
 	common.SetupHomeDir(&CommonCmdData, cmd)
+	common.SetupLockBackend(&CommonCmdData, cmd)
+	common.SetupRegistryType(&CommonCmdData, cmd)
 
 	cmd.PersistentFlags().StringVarP(&CmdData.Repo, "repo", "", "", "Docker repository name to get images information")
 	cmd.PersistentFlags().StringVarP(&CmdData.RegistryUsername, "registry-username", "", "", "Docker registry username (granted read permission)")
@@ -60,6 +62,10 @@ func runSync() error {
 		return err
 	}
 
+	if err := common.InitLockBackend(&CommonCmdData); err != nil {
+		return err
+	}
+
 	if err := docker.Init(docker_authorizer.GetHomeDockerConfigDir()); err != nil {
 		return err
 	}
@@ -113,10 +119,16 @@ func runSync() error {
 		CommonOptions: cleanup.CommonOptions{DryRun: CmdData.DryRun},
 	}
 
+	registryImplementation, err := common.GetRegistryImplementation(&CommonCmdData, repoName)
+	if err != nil {
+		return err
+	}
+
 	commonRepoOptions := cleanup.CommonRepoOptions{
-		Repository: repoName,
-		DimgsNames: dimgNames,
-		DryRun:     CmdData.DryRun,
+		Repository:             repoName,
+		RegistryImplementation: registryImplementation,
+		DimgsNames:             dimgNames,
+		DryRun:                 CmdData.DryRun,
 	}
 
 	if err := cleanup.ProjectDimgstagesSync(commonProjectOptions, commonRepoOptions); err != nil {
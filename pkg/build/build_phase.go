@@ -5,6 +5,7 @@ import (
 
 	"github.com/flant/dapp/pkg/image"
 	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 func NewBuildPhase(opts BuildOptions) *BuildPhase {
@@ -20,14 +21,15 @@ type BuildPhase struct {
 }
 
 func (p *BuildPhase) Run(c *Conveyor) error {
-	if debug() {
-		fmt.Printf("BuildPhase.Run\n")
+	logger.LogDebugF("build", "BuildPhase.Run\n")
+
+	if p.ImageBuildOptions.IntrospectBeforeError || p.ImageBuildOptions.IntrospectAfterError {
+		// Introspection runs interactive shell in the container, so the output should not be captured by the live progress
+		c.progress = newBuildProgress(c.dimgsInOrder, false)
 	}
 
 	for _, dimg := range c.dimgsInOrder {
-		if debug() {
-			fmt.Printf("  dimg: '%s'\n", dimg.GetName())
-		}
+		logger.LogDebugF("build", "  dimg: '%s'\n", dimg.GetName())
 
 		var acquiredLocks []string
 
@@ -60,34 +62,29 @@ func (p *BuildPhase) Run(c *Conveyor) error {
 		}
 
 		// build
+		progress := c.getProgress()
 		for _, s := range dimg.GetStages() {
 			img := s.GetImage()
 			if img.IsExists() {
-				if dimg.GetName() == "" {
-					fmt.Printf("# Using cached image %s for dimg %s\n", img.Name(), fmt.Sprintf("stage/%s", s.Name()))
-				} else {
-					fmt.Printf("# Using cached image %s for dimg/%s %s\n", img.Name(), dimg.GetName(), fmt.Sprintf("stage/%s", s.Name()))
-				}
-
+				progress.stageCached(dimg, s)
 				continue
 			}
 
-			if dimg.GetName() == "" {
-				fmt.Printf("# Building image %s for dimg %s\n", img.Name(), fmt.Sprintf("stage/%s", s.Name()))
-			} else {
-				fmt.Printf("# Building image %s for dimg/%s %s\n", img.Name(), dimg.GetName(), fmt.Sprintf("stage/%s", s.Name()))
-			}
+			logger.LogDebugF("build", "    %s\n", s.Name())
 
-			if debug() {
-				fmt.Printf("    %s\n", s.Name())
-			}
+			err := progress.buildStage(dimg, s, func() error {
+				if err := s.PreRunHook(c); err != nil {
+					return fmt.Errorf("stage '%s' preRunHook failed: %s", s.Name(), err)
+				}
 
-			if err := s.PreRunHook(c); err != nil {
-				return fmt.Errorf("stage '%s' preRunHook failed: %s", s.Name(), err)
-			}
+				if err := img.Build(p.ImageBuildOptions); err != nil {
+					return fmt.Errorf("failed to build %s: %s", img.Name(), err)
+				}
 
-			if err := img.Build(p.ImageBuildOptions); err != nil {
-				return fmt.Errorf("failed to build %s: %s", img.Name(), err)
+				return nil
+			})
+			if err != nil {
+				return err
 			}
 		}
 
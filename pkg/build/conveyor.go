@@ -23,6 +23,8 @@ type Conveyor struct {
 	remoteGitRepos                map[string]*git_repo.Remote
 	imagesBySignature             map[string]image.Image
 
+	progress *buildProgress
+
 	tmpDir string
 }
 
@@ -39,7 +41,8 @@ type conveyorPermanentFields struct {
 
 	dockerAuthorizer DockerAuthorizer
 
-	sshAuthSock string
+	sshAuthSock  string
+	sshConfigDir string
 }
 
 type DockerAuthorizer interface {
@@ -47,7 +50,7 @@ type DockerAuthorizer interface {
 	LoginForPush(repo string) error
 }
 
-func NewConveyor(dappfile []*config.Dimg, dimgNamesToProcess []string, projectDir, projectName, buildDir, baseTmpDir, sshAuthSock string, authorizer DockerAuthorizer) *Conveyor {
+func NewConveyor(dappfile []*config.Dimg, dimgNamesToProcess []string, projectDir, projectName, buildDir, baseTmpDir, sshAuthSock, sshConfigDir string, authorizer DockerAuthorizer) *Conveyor {
 	c := &Conveyor{
 		conveyorPermanentFields: &conveyorPermanentFields{
 			dappfile:           dappfile,
@@ -62,7 +65,8 @@ func NewConveyor(dappfile []*config.Dimg, dimgNamesToProcess []string, projectDi
 
 			dockerAuthorizer: authorizer,
 
-			sshAuthSock: sshAuthSock,
+			sshAuthSock:  sshAuthSock,
+			sshConfigDir: sshConfigDir,
 		},
 	}
 	c.ReInitRuntimeFields()
@@ -78,6 +82,8 @@ func (c *Conveyor) ReInitRuntimeFields() {
 
 	c.remoteGitRepos = make(map[string]*git_repo.Remote)
 
+	c.progress = nil
+
 	c.tmpDir = filepath.Join(c.baseTmpDir, string(util.GenerateConsistentRandomString(10)))
 }
 
@@ -184,6 +190,13 @@ func (c *Conveyor) bp(repo string, buildOpts BuildOptions, pushOpts PushOptions)
 }
 
 func (c *Conveyor) runPhases(phases []Phase) error {
+	defer func() {
+		if c.progress != nil {
+			c.progress.stop()
+			c.progress = nil
+		}
+	}()
+
 	for _, phase := range phases {
 		err := phase.Run(c)
 		if err != nil {
@@ -202,6 +215,15 @@ func (c *Conveyor) lockAllImagesReadOnly() (string, error) {
 	return lockName, nil
 }
 
+// getProgress returns progress of the dimgs stages, the progress is started by the first phase which builds or pushes images.
+func (c *Conveyor) getProgress() *buildProgress {
+	if c.progress == nil {
+		c.progress = newBuildProgress(c.dimgsInOrder, true)
+	}
+
+	return c.progress
+}
+
 func (c *Conveyor) GetImage(name string) *image.Stage {
 	return c.stageImages[name]
 }
@@ -80,9 +80,9 @@ func (d *Dimg) PrepareBaseImage(c *Conveyor) error {
 	}
 
 	if d.GetName() == "" {
-		fmt.Printf("# Pulling base image for dimg\n")
+		logger.LogF("# Pulling base image for dimg\n")
 	} else {
-		fmt.Printf("# Pulling base image for dimg/%s\n", d.GetName())
+		logger.LogF("# Pulling base image for dimg/%s\n", d.GetName())
 	}
 
 	if d.baseImage.IsExists() {
@@ -12,6 +12,7 @@ import (
 	"github.com/flant/dapp/pkg/git_repo"
 	"github.com/flant/dapp/pkg/logger"
 	"github.com/flant/dapp/pkg/slug"
+	"github.com/flant/dapp/pkg/ssh_agent"
 )
 
 type InitializationPhase struct{}
@@ -164,7 +165,7 @@ func generateStages(dimgConfig config.DimgInterface, c *Conveyor) ([]stage.Inter
 			return nil, fmt.Errorf("unable to get commit of repo '%s': %s", ga.GitRepo().String(), err)
 		}
 
-		fmt.Printf("Using commit '%s' of repo '%s'\n", commit, ga.GitRepo().String())
+		logger.LogF("Using commit '%s' of repo '%s'\n", commit, ga.GitRepo().String())
 	}
 
 	// from
@@ -245,6 +246,10 @@ func generateGitArtifacts(dimgBaseConfig *config.DimgBase, c *Conveyor) ([]*stag
 				ClonePath: clonePath,
 			}
 
+			if remoteGAConfig.SSHKey != "" {
+				remoteGitRepo.SSHKey = ssh_agent.ResolvePath(remoteGAConfig.SSHKey, c.projectDir)
+			}
+
 			if err := remoteGitRepo.CloneAndFetch(); err != nil {
 				return nil, err
 			}
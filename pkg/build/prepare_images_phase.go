@@ -5,6 +5,8 @@ import (
 
 	"github.com/flant/dapp/pkg/dapp"
 	"github.com/flant/dapp/pkg/image"
+	"github.com/flant/dapp/pkg/logger"
+	"github.com/flant/dapp/pkg/ssh_agent"
 )
 
 func NewPrepareImagesPhase() *PrepareImagesPhase {
@@ -16,14 +18,10 @@ type PrepareImagesPhase struct{}
 const DappCacheVersionLabel = "dapp-cache-version"
 
 func (p *PrepareImagesPhase) Run(c *Conveyor) error {
-	if debug() {
-		fmt.Printf("PrepareImagesPhase.Run\n")
-	}
+	logger.LogDebugF("build", "PrepareImagesPhase.Run\n")
 
 	for _, dimg := range c.dimgsInOrder {
-		if debug() {
-			fmt.Printf("  dimg: '%s'\n", dimg.GetName())
-		}
+		logger.LogDebugF("build", "  dimg: '%s'\n", dimg.GetName())
 
 		var prevImage, prevBuiltImage image.Image
 
@@ -45,9 +43,7 @@ func (p *PrepareImagesPhase) Run(c *Conveyor) error {
 				continue
 			}
 
-			if debug() {
-				fmt.Printf("    %s\n", s.Name())
-			}
+			logger.LogDebugF("build", "    %s\n", s.Name())
 
 			imageServiceCommitChangeOptions := img.Container().ServiceCommitChangeOptions()
 			imageServiceCommitChangeOptions.AddLabel(map[string]string{
@@ -64,6 +60,12 @@ func (p *PrepareImagesPhase) Run(c *Conveyor) error {
 				imageRunOptions.AddEnv(map[string]string{"SSH_AUTH_SOCK": "/tmp/dapp-ssh-agent"})
 			}
 
+			if c.sshConfigDir != "" {
+				imageRunOptions := img.Container().RunOptions()
+				imageRunOptions.AddVolume(fmt.Sprintf("%s:%s:ro", c.sshConfigDir, ssh_agent.ContainerSSHConfigDir))
+				imageRunOptions.AddEnv(map[string]string{"GIT_SSH_COMMAND": fmt.Sprintf("ssh -F %s/config", ssh_agent.ContainerSSHConfigDir)})
+			}
+
 			err := s.PrepareImage(c, prevBuiltImage, img)
 			if err != nil {
 				return fmt.Errorf("error preparing stage %s: %s", s.Name(), err)
@@ -72,9 +74,9 @@ func (p *PrepareImagesPhase) Run(c *Conveyor) error {
 			c.SetImageBySignature(s.GetSignature(), img)
 
 			if dimg.GetName() == "" {
-				fmt.Printf("# Prepared for build image %s for dimg %s\n", img.Name(), fmt.Sprintf("stage/%s", s.Name()))
+				logger.LogF("# Prepared for build image %s for dimg %s\n", img.Name(), fmt.Sprintf("stage/%s", s.Name()))
 			} else {
-				fmt.Printf("# Prepared for build image %s for dimg/%s %s\n", img.Name(), dimg.GetName(), fmt.Sprintf("stage/%s", s.Name()))
+				logger.LogF("# Prepared for build image %s for dimg/%s %s\n", img.Name(), dimg.GetName(), fmt.Sprintf("stage/%s", s.Name()))
 			}
 
 			prevImage = img
@@ -0,0 +1,255 @@
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/flant/dapp/pkg/build/stage"
+	"github.com/flant/dapp/pkg/logger"
+)
+
+const (
+	StagePending  = "pending"
+	StageCached   = "cached"
+	StageBuilding = "building"
+	StageBuilt    = "built"
+	StagePushing  = "pushing"
+	StagePushed   = "pushed"
+	StageInRepo   = "in repo"
+	StageFailed   = "failed"
+)
+
+// buildProgress keeps states and durations of dimgs stages for the live progress tree and the summary.
+// Without the live progress (no TTY, CI) the line output is used.
+type buildProgress struct {
+	progress *logger.Progress
+	dimgs    []*dimgProgress
+}
+
+type dimgProgress struct {
+	dimg   *Dimg
+	task   *logger.ProgressTask
+	stages map[stage.StageName]*taskProgress
+	tasks  []*taskProgress
+}
+
+type taskProgress struct {
+	name     string
+	status   string
+	duration time.Duration
+	task     *logger.ProgressTask
+
+	isStage       bool
+	cacheHit      bool
+	built         bool
+	buildDuration time.Duration
+}
+
+func newBuildProgress(dimgs []*Dimg, live bool) *buildProgress {
+	p := &buildProgress{}
+	if live {
+		p.progress = logger.StartProgress()
+	}
+
+	for _, dimg := range dimgs {
+		d := &dimgProgress{
+			dimg:   dimg,
+			task:   p.progress.AddTask(dimgLogName(dimg)),
+			stages: make(map[stage.StageName]*taskProgress),
+		}
+
+		for _, s := range dimg.GetStages() {
+			t := d.addTask(string(s.Name()))
+			t.isStage = true
+
+			if img := s.GetImage(); img != nil && img.IsExists() {
+				t.finish(StageCached)
+			} else {
+				t.setStatus(StagePending)
+			}
+
+			d.stages[s.Name()] = t
+		}
+
+		p.dimgs = append(p.dimgs, d)
+	}
+
+	return p
+}
+
+func dimgLogName(dimg *Dimg) string {
+	if dimg.GetName() == "" {
+		return "dimg"
+	}
+	return fmt.Sprintf("dimg/%s", dimg.GetName())
+}
+
+func (p *buildProgress) isLive() bool {
+	return p.progress != nil
+}
+
+func (p *buildProgress) getDimg(dimg *Dimg) *dimgProgress {
+	for _, d := range p.dimgs {
+		if d.dimg == dimg {
+			return d
+		}
+	}
+
+	d := &dimgProgress{dimg: dimg, task: p.progress.AddTask(dimgLogName(dimg)), stages: make(map[stage.StageName]*taskProgress)}
+	p.dimgs = append(p.dimgs, d)
+
+	return d
+}
+
+func (d *dimgProgress) addTask(name string) *taskProgress {
+	t := &taskProgress{name: name, task: d.task.AddTask(name)}
+	d.tasks = append(d.tasks, t)
+	return t
+}
+
+func (d *dimgProgress) getStage(s stage.Interface) *taskProgress {
+	if t, hasKey := d.stages[s.Name()]; hasKey {
+		return t
+	}
+
+	t := d.addTask(string(s.Name()))
+	t.isStage = true
+	d.stages[s.Name()] = t
+
+	return t
+}
+
+func (t *taskProgress) setStatus(status string) {
+	t.status = status
+	t.task.SetState(status)
+}
+
+func (t *taskProgress) finish(status string) {
+	if status == StageCached {
+		t.cacheHit = true
+	}
+
+	t.status = status
+	t.task.Finish(status)
+}
+
+func (t *taskProgress) run(status, doneStatus string, f func() error) error {
+	t.status = status
+
+	startTime := time.Now()
+	err := t.task.Run(status, doneStatus, f)
+	t.duration = time.Since(startTime)
+
+	if err != nil {
+		t.status = StageFailed
+		return err
+	}
+
+	t.status = doneStatus
+	if doneStatus == StageBuilt {
+		t.built = true
+		t.buildDuration = t.duration
+	}
+
+	return nil
+}
+
+func (p *buildProgress) stageCached(dimg *Dimg, s stage.Interface) {
+	p.getDimg(dimg).getStage(s).finish(StageCached)
+
+	if !p.isLive() {
+		logger.LogF("# Using cached image %s for %s stage/%s\n", s.GetImage().Name(), dimgLogName(dimg), s.Name())
+	}
+}
+
+func (p *buildProgress) buildStage(dimg *Dimg, s stage.Interface, f func() error) error {
+	if !p.isLive() {
+		logger.LogF("# Building image %s for %s stage/%s\n", s.GetImage().Name(), dimgLogName(dimg), s.Name())
+	}
+
+	return p.getDimg(dimg).getStage(s).run(StageBuilding, StageBuilt, f)
+}
+
+func (p *buildProgress) stageInRepo(dimg *Dimg, s stage.Interface, imageName string) {
+	p.getDimg(dimg).getStage(s).finish(StageInRepo)
+
+	if !p.isLive() {
+		logger.LogF("# Ignore existing in repo image %s for %s stage/%s\n", imageName, dimgLogName(dimg), s.Name())
+	}
+}
+
+func (p *buildProgress) pushStage(dimg *Dimg, s stage.Interface, f func() error) error {
+	return p.getDimg(dimg).getStage(s).run(StagePushing, StagePushed, f)
+}
+
+func (p *buildProgress) dimgImageInRepo(dimg *Dimg, imageName string) {
+	p.getDimg(dimg).addTask(imageName).finish(StageInRepo)
+
+	if !p.isLive() {
+		logger.LogF("# Ignore existing in repo image %s for %s\n", imageName, dimgLogName(dimg))
+	}
+}
+
+func (p *buildProgress) pushDimgImage(dimg *Dimg, imageName string, f func() error) error {
+	return p.getDimg(dimg).addTask(imageName).run(StagePushing, StagePushed, f)
+}
+
+// stop stops the live progress and prints the summary of stages durations and cache hits.
+func (p *buildProgress) stop() {
+	p.progress.Stop()
+
+	var hasTasks bool
+	for _, d := range p.dimgs {
+		if len(d.tasks) > 0 {
+			hasTasks = true
+		}
+	}
+	if !hasTasks {
+		return
+	}
+
+	logger.Log("")
+	logger.LogF("%s", p.summary())
+}
+
+func (p *buildProgress) summary() string {
+	buf := &bytes.Buffer{}
+
+	var stages, cached, built int
+	var buildDuration time.Duration
+
+	w := tabwriter.NewWriter(buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "DIMG\tSTAGE\tSTATUS\tDURATION\n")
+	for _, d := range p.dimgs {
+		for _, t := range d.tasks {
+			duration := "-"
+			if t.duration != 0 {
+				duration = t.duration.Round(100 * time.Millisecond).String()
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", dimgLogName(d.dimg), t.name, t.status, duration)
+
+			if !t.isStage {
+				continue
+			}
+
+			stages++
+			if t.cacheHit {
+				cached++
+			}
+			if t.built {
+				built++
+				buildDuration += t.buildDuration
+			}
+		}
+	}
+	w.Flush()
+
+	if stages > 0 {
+		fmt.Fprintf(buf, "\nStages: %d, cached: %d (%d%%), built: %d in %s\n", stages, cached, cached*100/stages, built, buildDuration.Round(time.Second))
+	}
+
+	return buf.String()
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/flant/dapp/pkg/docker_registry"
 	"github.com/flant/dapp/pkg/image"
 	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/dapp/pkg/logger"
 	"github.com/flant/dapp/pkg/util"
 )
 
@@ -39,21 +40,19 @@ type PushPhase struct {
 }
 
 func (p *PushPhase) Run(c *Conveyor) error {
-	if debug() {
-		fmt.Printf("PushPhase.Run\n")
-	}
+	logger.LogDebugF("build", "PushPhase.Run\n")
 
 	err := c.GetDockerAuthorizer().LoginForPush(p.Repo)
 	if err != nil {
 		return fmt.Errorf("login into '%s' for push failed: %s", p.Repo, err)
 	}
 
+	progress := c.getProgress()
+
 	for _, dimg := range c.dimgsInOrder {
 		if p.WithStages {
-			if dimg.GetName() == "" {
-				fmt.Printf("# Pushing dimg stages cache\n")
-			} else {
-				fmt.Printf("# Pushing dimg/%s stages cache\n", dimg.GetName())
+			if !progress.isLive() {
+				logger.LogF("# Pushing %s stages cache\n", dimgLogName(dimg))
 			}
 
 			err := p.pushDimgStages(c, dimg)
@@ -63,10 +62,8 @@ func (p *PushPhase) Run(c *Conveyor) error {
 		}
 
 		if !dimg.isArtifact {
-			if dimg.GetName() == "" {
-				fmt.Printf("# Pushing dimg\n")
-			} else {
-				fmt.Printf("# Pushing dimg/%s\n", dimg.GetName())
+			if !progress.isLive() {
+				logger.LogF("# Pushing %s\n", dimgLogName(dimg))
 			}
 
 			err := p.pushDimg(c, dimg)
@@ -92,16 +89,11 @@ func (p *PushPhase) pushDimgStages(c *Conveyor, dimg *Dimg) error {
 		stageImageName := fmt.Sprintf("%s:%s", p.Repo, stageTagName)
 
 		if util.IsStringsContainValue(existingStagesTags, stageTagName) {
-			if dimg.GetName() == "" {
-				fmt.Printf("# Ignore existing in repo image %s for dimg stage/%s\n", stageImageName, stage.Name())
-			} else {
-				fmt.Printf("# Ignore existing in repo image %s for dimg/%s stage/%s\n", stageImageName, dimg.GetName(), stage.Name())
-			}
-
+			c.getProgress().stageInRepo(dimg, stage, stageImageName)
 			continue
 		}
 
-		err := func() error {
+		err := c.getProgress().pushStage(dimg, stage, func() error {
 			var err error
 
 			imageLockName := fmt.Sprintf("image.%s", util.Sha256Hash(stageImageName))
@@ -112,9 +104,9 @@ func (p *PushPhase) pushDimgStages(c *Conveyor, dimg *Dimg) error {
 			defer lock.Unlock(imageLockName)
 
 			if dimg.GetName() == "" {
-				fmt.Printf("# Pushing image %s for dimg stage/%s\n", stageImageName, stage.Name())
+				logger.LogF("# Pushing image %s for dimg stage/%s\n", stageImageName, stage.Name())
 			} else {
-				fmt.Printf("# Pushing image %s for dimg/%s stage/%s\n", stageImageName, dimg.GetName(), stage.Name())
+				logger.LogF("# Pushing image %s for dimg/%s stage/%s\n", stageImageName, dimg.GetName(), stage.Name())
 			}
 
 			stageImage := c.GetImage(stage.GetImage().Name())
@@ -125,7 +117,7 @@ func (p *PushPhase) pushDimgStages(c *Conveyor, dimg *Dimg) error {
 			}
 
 			return nil
-		}()
+		})
 
 		if err != nil {
 			return err
@@ -150,6 +142,7 @@ func (p *PushPhase) pushDimg(c *Conveyor, dimg *Dimg) error {
 
 	stages := dimg.GetStages()
 	lastStageImage := stages[len(stages)-1].GetImage()
+	lastStageSignature := stages[len(stages)-1].GetSignature()
 
 	for scheme, tags := range p.TagsByScheme {
 	ProcessingTags:
@@ -163,16 +156,12 @@ func (p *PushPhase) pushDimg(c *Conveyor, dimg *Dimg) error {
 				}
 
 				if lastStageImage.ID() == parentID {
-					if dimg.GetName() == "" {
-						fmt.Printf("# Ignore existing in repo image %s for dimg\n", dimgImageName)
-					} else {
-						fmt.Printf("# Ignore existing in repo image %s for dimg/%s\n", dimgImageName, dimg.GetName())
-					}
+					c.getProgress().dimgImageInRepo(dimg, dimgImageName)
 					continue ProcessingTags
 				}
 			}
 
-			err := func() error {
+			err := c.getProgress().pushDimgImage(dimg, dimgImageName, func() error {
 				var err error
 
 				imageLockName := fmt.Sprintf("image.%s", util.Sha256Hash(dimgImageName))
@@ -182,13 +171,14 @@ func (p *PushPhase) pushDimg(c *Conveyor, dimg *Dimg) error {
 				}
 				defer lock.Unlock(imageLockName)
 
-				fmt.Printf("# Build %s layer with tag scheme '%s'\n", dimgImageName, scheme)
+				logger.LogF("# Build %s layer with tag scheme '%s'\n", dimgImageName, scheme)
 
 				pushImage := image.NewDimgImage(c.GetImage(lastStageImage.Name()), dimgImageName)
 
 				pushImage.Container().ServiceCommitChangeOptions().AddLabel(map[string]string{
-					"dapp-tag-scheme": string(scheme),
-					"dapp-dimg":       "true",
+					"dapp-tag-scheme":                       string(scheme),
+					"dapp-dimg":                             "true",
+					docker_registry.DimgStageSignatureLabel: lastStageSignature,
 				})
 
 				err = pushImage.Build(image.BuildOptions{})
@@ -197,9 +187,9 @@ func (p *PushPhase) pushDimg(c *Conveyor, dimg *Dimg) error {
 				}
 
 				if dimg.GetName() == "" {
-					fmt.Printf("# Pushing image %s for dimg\n", dimgImageName)
+					logger.LogF("# Pushing image %s for dimg\n", dimgImageName)
 				} else {
-					fmt.Printf("# Pushing image %s for dimg/%s\n", dimgImageName, dimg.GetName())
+					logger.LogF("# Pushing image %s for dimg/%s\n", dimgImageName, dimg.GetName())
 				}
 
 				err = pushImage.Export()
@@ -208,7 +198,7 @@ func (p *PushPhase) pushDimg(c *Conveyor, dimg *Dimg) error {
 				}
 
 				return nil
-			}()
+			})
 
 			if err != nil {
 				return err
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 func NewRenewPhase() *RenewPhase {
@@ -14,15 +15,11 @@ func NewRenewPhase() *RenewPhase {
 type RenewPhase struct{}
 
 func (p *RenewPhase) Run(c *Conveyor) error {
-	if debug() {
-		fmt.Printf("RenewPhase.Run\n")
-	}
+	logger.LogDebugF("build", "RenewPhase.Run\n")
 
 	var conveyorShouldBeReset bool
 	for _, dimg := range c.dimgsInOrder {
-		if debug() {
-			fmt.Printf("  dimg: '%s'\n", dimg.GetName())
-		}
+		logger.LogDebugF("build", "  dimg: '%s'\n", dimg.GetName())
 
 		var acquiredLocks []string
 
@@ -64,9 +61,9 @@ func (p *RenewPhase) Run(c *Conveyor) error {
 					conveyorShouldBeReset = true
 
 					if dimg.GetName() == "" {
-						fmt.Printf("# Reseting image %s for dimg %s\n", img.Name(), fmt.Sprintf("stage/%s", s.Name()))
+						logger.LogF("# Reseting image %s for dimg %s\n", img.Name(), fmt.Sprintf("stage/%s", s.Name()))
 					} else {
-						fmt.Printf("# Reseting image %s for dimg/%s %s\n", img.Name(), dimg.GetName(), fmt.Sprintf("stage/%s", s.Name()))
+						logger.LogF("# Reseting image %s for dimg/%s %s\n", img.Name(), dimg.GetName(), fmt.Sprintf("stage/%s", s.Name()))
 					}
 
 					if err := img.Untag(); err != nil {
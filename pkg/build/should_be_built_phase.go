@@ -2,9 +2,9 @@ package build
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/flant/dapp/pkg/build/stage"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 type ShouldBeBuiltPhase struct{}
@@ -14,16 +14,12 @@ func NewShouldBeBuiltPhase() *ShouldBeBuiltPhase {
 }
 
 func (p *ShouldBeBuiltPhase) Run(c *Conveyor) error {
-	if debug() {
-		fmt.Printf("ShouldBeBuiltPhase.Run\n")
-	}
+	logger.LogDebugF("build", "ShouldBeBuiltPhase.Run\n")
 
 	var badDimgs []*Dimg
 
 	for _, dimg := range c.dimgsInOrder {
-		if debug() {
-			fmt.Printf("  dimg: '%s'\n", dimg.GetName())
-		}
+		logger.LogDebugF("build", "  dimg: '%s'\n", dimg.GetName())
 
 		var badStages []stage.Interface
 
@@ -37,9 +33,9 @@ func (p *ShouldBeBuiltPhase) Run(c *Conveyor) error {
 
 		for _, s := range badStages {
 			if dimg.GetName() != "" {
-				fmt.Fprintf(os.Stderr, "Dimg '%s' stage '%s' is not built\n", dimg.GetName(), s.Name())
+				logger.LogErrorF("Dimg '%s' stage '%s' is not built\n", dimg.GetName(), s.Name())
 			} else {
-				fmt.Fprintf(os.Stderr, "Dimg stage '%s' is not built\n", s.Name())
+				logger.LogErrorF("Dimg stage '%s' is not built\n", s.Name())
 			}
 		}
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/flant/dapp/pkg/build/stage"
 	"github.com/flant/dapp/pkg/image"
+	"github.com/flant/dapp/pkg/logger"
 	"github.com/flant/dapp/pkg/util"
 )
 
@@ -22,14 +23,10 @@ func NewSignaturesPhase() *SignaturesPhase {
 type SignaturesPhase struct{}
 
 func (p *SignaturesPhase) Run(c *Conveyor) error {
-	if debug() {
-		fmt.Printf("SignaturesPhase.Run\n")
-	}
+	logger.LogDebugF("build", "SignaturesPhase.Run\n")
 
 	for _, dimg := range c.dimgsInOrder {
-		if debug() {
-			fmt.Printf("  dimg: '%s'\n", dimg.GetName())
-		}
+		logger.LogDebugF("build", "  dimg: '%s'\n", dimg.GetName())
 
 		var prevStage stage.Interface
 
@@ -57,9 +54,7 @@ func (p *SignaturesPhase) Run(c *Conveyor) error {
 				continue
 			}
 
-			if debug() {
-				fmt.Printf("    %s\n", s.Name())
-			}
+			logger.LogDebugF("build", "    %s\n", s.Name())
 
 			stageDependencies, err := s.GetDependencies(c, prevImage)
 			if err != nil {
@@ -89,9 +84,9 @@ func (p *SignaturesPhase) Run(c *Conveyor) error {
 			}
 
 			if dimg.GetName() == "" {
-				fmt.Printf("# Calculated signature %s for dimg %s\n", stageSig, fmt.Sprintf("stage/%s", s.Name()))
+				logger.LogF("# Calculated signature %s for dimg %s\n", stageSig, fmt.Sprintf("stage/%s", s.Name()))
 			} else {
-				fmt.Printf("# Calculated signature %s for dimg/%s %s\n", stageSig, dimg.GetName(), fmt.Sprintf("stage/%s", s.Name()))
+				logger.LogF("# Calculated signature %s for dimg/%s %s\n", stageSig, dimg.GetName(), fmt.Sprintf("stage/%s", s.Name()))
 			}
 
 			newStagesList = append(newStagesList, s)
@@ -87,7 +87,7 @@ func (ga *GitArtifact) IsLocal() bool {
 
 func (ga *GitArtifact) LatestCommit() (string, error) {
 	if ga.Commit != "" {
-		fmt.Printf("Using specified commit `%s` of repository `%s`\n", ga.Commit, ga.GitRepo().String())
+		logger.LogInfoF("Using specified commit `%s` of repository `%s`\n", ga.Commit, ga.GitRepo().String())
 		return ga.Commit, nil
 	}
 
@@ -92,12 +92,12 @@ Loop:
 	}
 
 	if len(exceptedRepoDimgs) != 0 {
-		fmt.Println("Keep in repo images that are being used in kubernetes")
+		logger.Log("Keep in repo images that are being used in kubernetes")
 		for _, exceptedRepoDimg := range exceptedRepoDimgs {
 			imageName := fmt.Sprintf("%s:%s", exceptedRepoDimg.Repository, exceptedRepoDimg.Tag)
-			fmt.Println(imageName)
+			logger.Log(imageName)
 		}
-		fmt.Println()
+		logger.Log("")
 	}
 
 	return newRepoDimgs, nil
@@ -154,29 +154,29 @@ Loop:
 	}
 
 	if len(nonexistentGitTagRepoImages) != 0 {
-		fmt.Println("git tag nonexistent")
+		logger.Log("git tag nonexistent")
 		if err := repoImagesRemove(nonexistentGitTagRepoImages, options.CommonRepoOptions); err != nil {
 			return nil, err
 		}
-		fmt.Println()
+		logger.Log("")
 		repoDimgs = exceptRepoImages(repoDimgs, nonexistentGitTagRepoImages...)
 	}
 
 	if len(nonexistentGitBranchRepoImages) != 0 {
-		fmt.Println("git branch nonexistent")
+		logger.Log("git branch nonexistent")
 		if err := repoImagesRemove(nonexistentGitBranchRepoImages, options.CommonRepoOptions); err != nil {
 			return nil, err
 		}
-		fmt.Println()
+		logger.Log("")
 		repoDimgs = exceptRepoImages(repoDimgs, nonexistentGitBranchRepoImages...)
 	}
 
 	if len(nonexistentGitCommitRepoImages) != 0 {
-		fmt.Println("git commit nonexistent")
+		logger.Log("git commit nonexistent")
 		if err := repoImagesRemove(nonexistentGitCommitRepoImages, options.CommonRepoOptions); err != nil {
 			return nil, err
 		}
-		fmt.Println()
+		logger.Log("")
 		repoDimgs = exceptRepoImages(repoDimgs, nonexistentGitCommitRepoImages...)
 	}
 
@@ -322,18 +322,18 @@ func repoDimgsCleanupByPolicy(repoDimgs, repoDimgsWithScheme []docker_registry.R
 		}
 
 		if len(expiredRepoDimgs) != 0 {
-			fmt.Printf("%s: git %s date policy (created before %s)\n", repository, options.gitPrimitive, expiryTime.String())
+			logger.LogF("%s: git %s date policy (created before %s)\n", repository, options.gitPrimitive, expiryTime.String())
 			repoImagesRemove(expiredRepoDimgs, options.commonRepoOptions)
-			fmt.Println()
+			logger.Log("")
 			repoDimgs = exceptRepoImages(repoDimgs, expiredRepoDimgs...)
 		}
 
 		if int64(len(notExpiredRepoDimgs)) > options.expiryLimit {
-			fmt.Printf("%s: git %s limit policy (> %d)\n", repository, options.gitPrimitive, options.expiryLimit)
+			logger.LogF("%s: git %s limit policy (> %d)\n", repository, options.gitPrimitive, options.expiryLimit)
 			if err := repoImagesRemove(notExpiredRepoDimgs[options.expiryLimit:], options.commonRepoOptions); err != nil {
 				return nil, err
 			}
-			fmt.Println()
+			logger.Log("")
 			repoDimgs = exceptRepoImages(repoDimgs, notExpiredRepoDimgs[options.expiryLimit:]...)
 		}
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/flant/dapp/pkg/build"
 	"github.com/flant/dapp/pkg/docker"
 	"github.com/flant/dapp/pkg/image"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 type CommonOptions struct {
@@ -135,7 +136,7 @@ func ignoreUsedImages(images []types.ImageSummary) ([]types.ImageSummary, error)
 	for _, container := range containers {
 		for _, img := range images {
 			if img.ID == container.ImageID {
-				fmt.Printf("Skip image '%s' (used by container '%s')\n", img.ID, container.ID)
+				logger.LogF("Skip image '%s' (used by container '%s')\n", img.ID, container.ID)
 				imagesToExclude = append(imagesToExclude, img)
 			}
 		}
@@ -162,8 +163,8 @@ func exceptImage(images []types.ImageSummary, imageToExclude types.ImageSummary)
 func containersRemove(containers []types.Container, options CommonOptions) error {
 	for _, container := range containers {
 		if options.DryRun {
-			fmt.Println(container.ID)
-			fmt.Println()
+			logger.Log(container.ID)
+			logger.Log("")
 		} else {
 			if err := docker.ContainerRemove(container.ID, types.ContainerRemoveOptions{}); err != nil {
 				return err
@@ -177,8 +178,7 @@ func containersRemove(containers []types.Container, options CommonOptions) error
 func imageReferencesRemove(references []string, options CommonOptions) error {
 	if len(references) != 0 {
 		if options.DryRun {
-			fmt.Printf(strings.Join(references, "\n"))
-			fmt.Println()
+			logger.Log(strings.Join(references, "\n"))
 		} else {
 			var args []string
 			args = append(args, "--force")
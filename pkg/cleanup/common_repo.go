@@ -5,12 +5,14 @@ import (
 	"strings"
 
 	"github.com/flant/dapp/pkg/docker_registry"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 type CommonRepoOptions struct {
-	Repository string
-	DimgsNames []string
-	DryRun     bool
+	Repository             string
+	RegistryImplementation docker_registry.RegistryImplementation
+	DimgsNames             []string
+	DryRun                 bool
 }
 
 func repoDimgImages(options CommonRepoOptions) ([]docker_registry.RepoImage, error) {
@@ -44,59 +46,19 @@ func repoDimgstageImages(options CommonRepoOptions) ([]docker_registry.RepoImage
 }
 
 func repoImagesRemove(images []docker_registry.RepoImage, options CommonRepoOptions) error {
-	isGCR, err := docker_registry.IsGCR(options.Repository)
-	if err != nil {
-		return err
-	}
-
 	for _, image := range images {
-		if isGCR {
-			if err := GCRImageRemove(image, options); err != nil {
-				return err
-			}
-		} else {
-			if err := repoImageRemove(image, options); err != nil {
-				return err
-			}
+		if err := repoImageRemove(image, options); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func GCRImageRemove(image docker_registry.RepoImage, options CommonRepoOptions) error {
-	reference := strings.Join([]string{image.Repository, image.Tag}, ":")
-	if err := repoReferenceRemove(reference, options); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func repoImageRemove(image docker_registry.RepoImage, options CommonRepoOptions) error {
-	digest, err := image.Digest()
-	if err != nil {
-		return err
-	}
-
-	reference := strings.Join([]string{image.Repository, digest.String()}, "@")
-	if err != nil {
-		return err
-	}
-
-	fmt.Printf("%s:\n  ", image.Tag)
-	if err := repoReferenceRemove(reference, options); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func repoReferenceRemove(reference string, options CommonRepoOptions) error {
-	fmt.Println(reference)
+	logger.Log(strings.Join([]string{image.Repository, image.Tag}, ":"))
 	if !options.DryRun {
-		err := docker_registry.ImageDelete(reference)
-		if err != nil {
+		if err := options.RegistryImplementation.DeleteRepoImage(image); err != nil {
 			return err
 		}
 	}
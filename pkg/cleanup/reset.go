@@ -1,13 +1,13 @@
 package cleanup
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/docker/docker/api/types/filters"
 
 	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 func ResetAll(options CommonOptions) error {
@@ -41,10 +41,10 @@ func deleteDappFiles(options CommonOptions) error {
 	}
 
 	if len(directoryPathToDelete) != 0 {
-		fmt.Println("reset dapp cache")
+		logger.Log("reset dapp cache")
 		for _, directoryPath := range directoryPathToDelete {
 			if options.DryRun {
-				fmt.Println(directoryPath)
+				logger.Log(directoryPath)
 			} else {
 				err := os.RemoveAll(directoryPath)
 				if err != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/flant/dapp/pkg/build"
 	"github.com/flant/dapp/pkg/docker_registry"
 	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 const syncIgnoreProjectDimgstagePeriod = 2 * 60 * 60
@@ -95,7 +96,7 @@ func repoDimgstagesSyncByCacheVersion(options CommonRepoOptions) error {
 
 		version, ok := labels[build.DappCacheVersionLabel]
 		if !ok || (version != build.BuildCacheVersion) {
-			fmt.Printf("%s %s %s\n", repoDimgstage.Tag, version, build.BuildCacheVersion)
+			logger.LogF("%s %s %s\n", repoDimgstage.Tag, version, build.BuildCacheVersion)
 			repoImagesToDelete = append(repoImagesToDelete, repoDimgstage)
 		}
 	}
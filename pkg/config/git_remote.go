@@ -2,9 +2,10 @@ package config
 
 type GitRemote struct {
 	*GitRemoteExport
-	As   string
-	Name string
-	Url  string
+	As     string
+	Name   string
+	Url    string
+	SSHKey string
 
 	raw *rawGit
 }
@@ -12,6 +12,7 @@ type rawGit struct {
 	Branch               string                `yaml:"branch,omitempty"`
 	Tag                  string                `yaml:"tag,omitempty"`
 	Commit               string                `yaml:"commit,omitempty"`
+	SSHKey               string                `yaml:"sshKey,omitempty"`
 	RawStageDependencies *rawStageDependencies `yaml:"stageDependencies,omitempty"`
 
 	rawDimg *rawDimg `yaml:"-"` // parent
@@ -82,6 +83,10 @@ func (c *rawGit) validateGitLocalDirective(gitLocal *GitLocal) (err error) {
 		return newDetailedConfigError("specify `branch: BRANCH`, `tag: TAG` and `commit: COMMIT` only for remote git!", nil, c.rawDimg.doc)
 	}
 
+	if c.SSHKey != "" {
+		return newDetailedConfigError("specify `sshKey: PATH` only for remote git!", nil, c.rawDimg.doc)
+	}
+
 	if err := gitLocal.validate(); err != nil {
 		return err
 	}
@@ -135,6 +140,7 @@ func (c *rawGit) toGitRemoteDirective() (gitRemote *GitRemote, err error) {
 
 	gitRemote.As = c.As
 	gitRemote.Url = c.Url
+	gitRemote.SSHKey = c.SSHKey
 
 	if url, err := c.getNameFromUrl(); err != nil {
 		return nil, newDetailedConfigError(err.Error(), c, c.rawDimg.doc)
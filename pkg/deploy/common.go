@@ -1,47 +1,72 @@
 package deploy
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/flant/dapp/pkg/deploy/secret"
+	"github.com/flant/dapp/pkg/logger"
 )
 
-func getSafeSecretManager(projectDir string, secretValues []string) (secret.Manager, error) {
+// getSafeSecretManager requires the secret key only if secrets of the deployed charts or secret values are used.
+func getSafeSecretManager(projectDir string, chartDirs []string, secretValues []string) (secret.Manager, error) {
 	isSecretsExists := false
-	if _, err := os.Stat(filepath.Join(projectDir, ProjectSecretDir)); !os.IsNotExist(err) {
-		isSecretsExists = true
-	}
-	if _, err := os.Stat(filepath.Join(projectDir, ProjectDefaultSecretValuesFile)); !os.IsNotExist(err) {
-		isSecretsExists = true
+	for _, chartDir := range chartDirs {
+		for _, path := range []string{chartSecretDir, chartSecretValuesFile} {
+			if _, err := os.Stat(filepath.Join(chartDir, path)); !os.IsNotExist(err) {
+				isSecretsExists = true
+			}
+		}
 	}
 	if len(secretValues) > 0 {
 		isSecretsExists = true
 	}
 	if isSecretsExists {
-		key, err := secret.GetSecretKey(projectDir)
+		if isRecipientsMode, err := secret.IsRecipientsMode(projectDir); err != nil {
+			return nil, err
+		} else if isRecipientsMode {
+			return secret.GetManager(projectDir)
+		}
+
+		keys, err := secret.GetSecretKeys(projectDir)
 		if err != nil {
 			if strings.HasPrefix(err.Error(), "encryption key not found in") {
-				fmt.Fprintln(os.Stderr, err)
+				logger.LogWarningF("WARNING: %s\n", err)
 			} else {
 				return nil, err
 			}
 		} else {
-			return secret.NewManager(key, secret.NewManagerOptions{})
+			return secret.NewKeyRingManager(keys, secret.NewManagerOptions{})
 		}
 	}
 
 	return secret.NewSafeManager()
 }
 
-func getDappChart(projectDir string, m secret.Manager, values, secretValues, set, setString []string, serviceValues map[string]interface{}) (*DappChart, error) {
-	dappChart, err := GenerateDappChart(projectDir, m)
+// getDappChartFromDir prepares chart with values, secret providers are resolved only with withSecretProviders:
+// providers run exec commands and fetch secrets from external stores, which is not expected by lint and render.
+func getDappChartFromDir(projectHelmDir, projectDir string, m secret.Manager, withSecretProviders bool, values, secretValues, set, setString []string, serviceValues map[string]interface{}) (*DappChart, error) {
+	dappChart, err := generateDappChartFromDir(projectHelmDir, m)
 	if err != nil {
 		return nil, err
 	}
 
+	if withSecretProviders {
+		// Values of secret providers override chart secret values and are overridden by values from options
+		providers, err := secret.GetProviders(projectHelmDir, projectDir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, provider := range providers {
+			err = dappChart.SetProviderSecretValues(provider)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	for _, path := range values {
 		err = dappChart.SetValuesFile(path)
 		if err != nil {
@@ -77,9 +102,7 @@ func getDappChart(projectDir string, m secret.Manager, values, secretValues, set
 		}
 	}
 
-	if debug() {
-		fmt.Printf("Dapp chart: %#v\n", dappChart)
-	}
+	logger.LogDebugF("deploy", "Dapp chart: %#v\n", dappChart)
 
 	return dappChart, nil
 }
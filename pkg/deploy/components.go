@@ -0,0 +1,345 @@
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/flant/dapp/pkg/config"
+	"github.com/flant/dapp/pkg/logger"
+	"github.com/ghodss/yaml"
+)
+
+const (
+	ProjectComponentsConfigFile = ProjectHelmChartDir + "/components.yaml"
+
+	ComponentsFailurePolicyStop     = "stop"
+	ComponentsFailurePolicyContinue = "continue"
+
+	DefaultComponentReleaseTemplate = "{{ if .Release }}{{ .Release }}{{ else }}{{ .Project }}{{ end }}-{{ .Component }}"
+
+	ComponentStatusDeployed = "DEPLOYED"
+	ComponentStatusFailed   = "FAILED"
+	ComponentStatusSkipped  = "SKIPPED"
+)
+
+// Component is an independently released chart .helm/<name> of the project.
+type Component struct {
+	Name string `json:"name"`
+	// Release is a release name template with .Project, .Component, .Namespace and .Release (deploy argument) variables
+	Release string `json:"release,omitempty"`
+	// Dimgs are used for component service values, all dimgs are used by default
+	Dimgs []string `json:"dimgs,omitempty"`
+}
+
+// ComponentsConfig declares components in the order of deploy.
+type ComponentsConfig struct {
+	Components    []*Component `json:"components"`
+	FailurePolicy string       `json:"failurePolicy,omitempty"`
+}
+
+func GetComponentsConfig(projectDir string) (*ComponentsConfig, error) {
+	path := filepath.Join(projectDir, ProjectComponentsConfigFile)
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no components declared: %s not found", ProjectComponentsConfigFile)
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading %s: %s", path, err)
+	}
+
+	var cfg ComponentsConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("bad components config %s: %s", path, err)
+	}
+
+	if err := validateComponentsConfig(&cfg, projectDir); err != nil {
+		return nil, fmt.Errorf("bad components config %s: %s", path, err)
+	}
+
+	return &cfg, nil
+}
+
+func validateComponentsConfig(cfg *ComponentsConfig, projectDir string) error {
+	switch cfg.FailurePolicy {
+	case "":
+		cfg.FailurePolicy = ComponentsFailurePolicyStop
+	case ComponentsFailurePolicyStop, ComponentsFailurePolicyContinue:
+	default:
+		return fmt.Errorf("bad failurePolicy '%s': expected %s or %s", cfg.FailurePolicy, ComponentsFailurePolicyStop, ComponentsFailurePolicyContinue)
+	}
+
+	names := make(map[string]bool)
+	for _, component := range cfg.Components {
+		if component.Name == "" || strings.ContainsAny(component.Name, "/\\") || strings.HasPrefix(component.Name, ".") {
+			return fmt.Errorf("bad component name '%s'", component.Name)
+		}
+
+		if names[component.Name] {
+			return fmt.Errorf("component '%s' is declared more than once", component.Name)
+		}
+		names[component.Name] = true
+
+		if component.Release == "" {
+			component.Release = DefaultComponentReleaseTemplate
+		}
+		if _, err := template.New(component.Name).Parse(component.Release); err != nil {
+			return fmt.Errorf("component '%s': bad release template: %s", component.Name, err)
+		}
+
+		chartDir := component.ChartDir(projectDir)
+		if _, err := os.Stat(filepath.Join(chartDir, "Chart.yaml")); err != nil {
+			return fmt.Errorf("component '%s': chart %s not found: %s", component.Name, chartDir, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Component) ChartDir(projectDir string) string {
+	return filepath.Join(projectDir, ProjectHelmChartDir, c.Name)
+}
+
+func (c *Component) ReleaseName(projectName, namespace, release string) (string, error) {
+	tmpl, err := template.New(c.Name).Option("missingkey=error").Parse(c.Release)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, map[string]string{
+		"Project":   projectName,
+		"Component": c.Name,
+		"Namespace": namespace,
+		"Release":   release,
+	})
+	if err != nil {
+		return "", fmt.Errorf("component '%s': bad release template: %s", c.Name, err)
+	}
+
+	releaseName := strings.TrimSpace(buf.String())
+	if releaseName == "" {
+		return "", fmt.Errorf("component '%s': release template gives empty release name", c.Name)
+	}
+
+	return releaseName, nil
+}
+
+// getComponentChart returns the component with its chart dir and dimgs,
+// project chart dir and all dimgs are returned if the component is not specified.
+func getComponentChart(projectDir, componentName string, dappfile []*config.Dimg) (*Component, string, []*config.Dimg, error) {
+	if componentName == "" {
+		return nil, filepath.Join(projectDir, ProjectHelmChartDir), dappfile, nil
+	}
+
+	cfg, err := GetComponentsConfig(projectDir)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	components, err := selectComponents(cfg, []string{componentName}, false)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	component := components[0]
+
+	componentDappfile, err := component.componentDimgs(dappfile)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return component, component.ChartDir(projectDir), componentDappfile, nil
+}
+
+// componentDimgs returns component dimgs from dappfile, nameless dimg is referred by empty name.
+func (c *Component) componentDimgs(dappfile []*config.Dimg) ([]*config.Dimg, error) {
+	if len(c.Dimgs) == 0 {
+		return dappfile, nil
+	}
+
+	var res []*config.Dimg
+	for _, name := range c.Dimgs {
+		var found *config.Dimg
+		for _, dimg := range dappfile {
+			if dimg.Name == name {
+				found = dimg
+				break
+			}
+		}
+
+		if found == nil {
+			return nil, fmt.Errorf("component '%s': dimg '%s' not found in dappfile", c.Name, name)
+		}
+
+		res = append(res, found)
+	}
+
+	return res, nil
+}
+
+type ComponentsDeployOptions struct {
+	DeployOptions
+
+	Components []string
+	All        bool
+	// FailurePolicy overrides failurePolicy of components config
+	FailurePolicy string
+}
+
+type componentDeployResult struct {
+	Component string
+	Release   string
+	Status    string
+	Duration  time.Duration
+	Err       error
+}
+
+// RunComponentsDeploy deploys selected components one by one in the declared order.
+// Deploy of remaining components is stopped or continued after failure according to the failure policy.
+// Returns true when any component release has been changed according to the diff (only with Diff option).
+func RunComponentsDeploy(projectName, projectDir, releaseName, namespace, kubeContext, repo, tag string, dappfile []*config.Dimg, opts ComponentsDeployOptions) (bool, error) {
+	logger.LogDebugF("deploy", "Components deploy options: %#v\n", opts)
+	logger.LogDebugF("deploy", "Namespace: %s\n", namespace)
+
+	cfg, err := GetComponentsConfig(projectDir)
+	if err != nil {
+		return false, err
+	}
+
+	failurePolicy := cfg.FailurePolicy
+	switch opts.FailurePolicy {
+	case "":
+	case ComponentsFailurePolicyStop, ComponentsFailurePolicyContinue:
+		failurePolicy = opts.FailurePolicy
+	default:
+		return false, fmt.Errorf("bad failure policy '%s': expected %s or %s", opts.FailurePolicy, ComponentsFailurePolicyStop, ComponentsFailurePolicyContinue)
+	}
+
+	components, err := selectComponents(cfg, opts.Components, opts.All)
+	if err != nil {
+		return false, err
+	}
+
+	var chartDirs []string
+	for _, component := range components {
+		chartDirs = append(chartDirs, component.ChartDir(projectDir))
+	}
+
+	m, err := getSafeSecretManager(projectDir, chartDirs, opts.SecretValues)
+	if err != nil {
+		return false, fmt.Errorf("cannot get project secret: %s", err)
+	}
+
+	var results []*componentDeployResult
+	var failed, hasChanges bool
+
+	for _, component := range components {
+		result := &componentDeployResult{Component: component.Name}
+		results = append(results, result)
+
+		if failed && failurePolicy == ComponentsFailurePolicyStop {
+			result.Status = ComponentStatusSkipped
+			continue
+		}
+
+		startTime := time.Now()
+		result.Err = func() error {
+			componentReleaseName, err := component.ReleaseName(projectName, namespace, releaseName)
+			if err != nil {
+				return err
+			}
+			result.Release = componentReleaseName
+
+			componentDappfile, err := component.componentDimgs(dappfile)
+			if err != nil {
+				return err
+			}
+
+			logger.LogF("# Deploy component %s into release %s\n", component.Name, componentReleaseName)
+
+			changed, err := deployChart(component.ChartDir(projectDir), projectName, projectDir, componentReleaseName, namespace, kubeContext, repo, tag, componentDappfile, m, opts.DeployOptions)
+			hasChanges = hasChanges || changed
+
+			return err
+		}()
+		result.Duration = time.Since(startTime)
+
+		if result.Err != nil {
+			logger.LogErrorF("# Deploy component %s FAILED: %s\n", component.Name, result.Err)
+			result.Status = ComponentStatusFailed
+			failed = true
+		} else {
+			logger.LogF("# Deploy component %s DONE\n", component.Name)
+			result.Status = ComponentStatusDeployed
+		}
+	}
+
+	printComponentDeployResults(logger.GetOutStream(), results)
+
+	var errs []string
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Sprintf("component %s: %s", result.Component, result.Err))
+		}
+	}
+	if len(errs) > 0 {
+		return false, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	return hasChanges, nil
+}
+
+// selectComponents returns requested components in the declared order.
+func selectComponents(cfg *ComponentsConfig, names []string, all bool) ([]*Component, error) {
+	if all {
+		if len(cfg.Components) == 0 {
+			return nil, fmt.Errorf("no components declared in %s", ProjectComponentsConfigFile)
+		}
+		return cfg.Components, nil
+	}
+
+	requested := make(map[string]bool)
+	for _, name := range names {
+		requested[name] = true
+	}
+
+	var res []*Component
+	for _, component := range cfg.Components {
+		if requested[component.Name] {
+			res = append(res, component)
+			delete(requested, component.Name)
+		}
+	}
+
+	for _, name := range names {
+		if requested[name] {
+			return nil, fmt.Errorf("component '%s' is not declared in %s", name, ProjectComponentsConfigFile)
+		}
+	}
+
+	return res, nil
+}
+
+func printComponentDeployResults(out io.Writer, results []*componentDeployResult) {
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "COMPONENT\tRELEASE\tSTATUS\tDURATION\tMESSAGE\n")
+	for _, result := range results {
+		var message string
+		if result.Err != nil {
+			message = strings.Replace(result.Err.Error(), "\n", " ", -1)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", result.Component, result.Release, result.Status, result.Duration.Round(time.Second), message)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out)
+}
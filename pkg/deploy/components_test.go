@@ -0,0 +1,169 @@
+package deploy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/flant/dapp/pkg/config"
+)
+
+func prepareComponentsProject(t *testing.T, components ...string) string {
+	projectDir, err := ioutil.TempDir("", "dapp-components-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range components {
+		chartDir := filepath.Join(projectDir, ProjectHelmChartDir, name)
+		if err := os.MkdirAll(chartDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte("name: "+name+"\nversion: 0.1.0\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return projectDir
+}
+
+func componentNames(components []*Component) []string {
+	var res []string
+	for _, component := range components {
+		res = append(res, component.Name)
+	}
+	return res
+}
+
+func TestValidateComponentsConfig(t *testing.T) {
+	projectDir := prepareComponentsProject(t, "api", "worker")
+	defer os.RemoveAll(projectDir)
+
+	cfg := &ComponentsConfig{Components: []*Component{{Name: "api"}, {Name: "worker", Release: "{{ .Project }}-jobs"}}}
+	if err := validateComponentsConfig(cfg, projectDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.FailurePolicy != ComponentsFailurePolicyStop {
+		t.Errorf("\n[EXPECTED]: %s\n[GOT]: %s", ComponentsFailurePolicyStop, cfg.FailurePolicy)
+	}
+
+	if cfg.Components[0].Release != DefaultComponentReleaseTemplate {
+		t.Errorf("\n[EXPECTED]: %s\n[GOT]: %s", DefaultComponentReleaseTemplate, cfg.Components[0].Release)
+	}
+}
+
+func TestValidateComponentsConfig_negative(t *testing.T) {
+	projectDir := prepareComponentsProject(t, "api")
+	defer os.RemoveAll(projectDir)
+
+	for _, cfg := range []*ComponentsConfig{
+		{Components: []*Component{{Name: "api"}}, FailurePolicy: "ignore"},
+		{Components: []*Component{{Name: ""}}},
+		{Components: []*Component{{Name: "../api"}}},
+		{Components: []*Component{{Name: ".hidden"}}},
+		{Components: []*Component{{Name: "api"}, {Name: "api"}}},
+		{Components: []*Component{{Name: "api", Release: "{{ .Project "}}},
+		{Components: []*Component{{Name: "worker"}}},
+	} {
+		if err := validateComponentsConfig(cfg, projectDir); err == nil {
+			t.Errorf("\n[CONFIG]: %+v %+v\n[EXPECTED]: error\n[GOT]: no error", cfg, cfg.Components[0])
+		}
+	}
+}
+
+func TestSelectComponents(t *testing.T) {
+	cfg := &ComponentsConfig{Components: []*Component{{Name: "db"}, {Name: "api"}, {Name: "worker"}}}
+
+	for _, e := range []struct {
+		names    []string
+		all      bool
+		expected []string
+	}{
+		{nil, true, []string{"db", "api", "worker"}},
+		{[]string{"worker", "db"}, false, []string{"db", "worker"}},
+		{[]string{"api", "api"}, false, []string{"api"}},
+	} {
+		components, err := selectComponents(cfg, e.names, e.all)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := componentNames(components); !reflect.DeepEqual(got, e.expected) {
+			t.Errorf("\n[NAMES]: %v\n[EXPECTED]: %v\n[GOT]: %v", e.names, e.expected, got)
+		}
+	}
+
+	if _, err := selectComponents(cfg, []string{"api", "frontend"}, false); err == nil {
+		t.Errorf("\n[EXPECTED]: undeclared component error\n[GOT]: no error")
+	}
+
+	if _, err := selectComponents(&ComponentsConfig{}, nil, true); err == nil {
+		t.Errorf("\n[EXPECTED]: no components error\n[GOT]: no error")
+	}
+}
+
+func TestComponent_ReleaseName(t *testing.T) {
+	for _, e := range []struct {
+		template string
+		release  string
+		expected string
+	}{
+		{DefaultComponentReleaseTemplate, "", "myproject-api"},
+		{DefaultComponentReleaseTemplate, "review-42", "review-42-api"},
+		{"{{ .Project }}-{{ .Namespace }}-{{ .Component }}", "", "myproject-production-api"},
+		{" {{ .Component }}\n", "", "api"},
+	} {
+		c := &Component{Name: "api", Release: e.template}
+
+		got, err := c.ReleaseName("myproject", "production", e.release)
+		if err != nil {
+			t.Fatalf("\n[TEMPLATE]: %q\n[ERROR]: %s", e.template, err)
+		}
+
+		if got != e.expected {
+			t.Errorf("\n[TEMPLATE]: %q\n[EXPECTED]: %s\n[GOT]: %s", e.template, e.expected, got)
+		}
+	}
+
+	for _, template := range []string{"{{ .Unknown }}", "{{ if false }}x{{ end }}"} {
+		c := &Component{Name: "api", Release: template}
+		if _, err := c.ReleaseName("myproject", "production", ""); err == nil {
+			t.Errorf("\n[TEMPLATE]: %q\n[EXPECTED]: error\n[GOT]: no error", template)
+		}
+	}
+}
+
+func TestGetComponentChart(t *testing.T) {
+	projectDir := prepareComponentsProject(t, "api", "worker")
+	defer os.RemoveAll(projectDir)
+
+	if err := ioutil.WriteFile(filepath.Join(projectDir, ProjectComponentsConfigFile), []byte("components:\n- name: api\n  dimgs: [backend]\n- name: worker\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dappfile := []*config.Dimg{{DimgBase: &config.DimgBase{Name: "backend"}}, {DimgBase: &config.DimgBase{Name: "frontend"}}}
+
+	component, chartDir, dimgs, err := getComponentChart(projectDir, "", dappfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component != nil || chartDir != filepath.Join(projectDir, ProjectHelmChartDir) || len(dimgs) != 2 {
+		t.Errorf("\n[EXPECTED]: project chart with all dimgs\n[GOT]: %v %s %d dimgs", component, chartDir, len(dimgs))
+	}
+
+	component, chartDir, dimgs, err = getComponentChart(projectDir, "api", dappfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component == nil || chartDir != filepath.Join(projectDir, ProjectHelmChartDir, "api") || len(dimgs) != 1 || dimgs[0].Name != "backend" {
+		t.Errorf("\n[EXPECTED]: api chart with backend dimg\n[GOT]: %v %s %d dimgs", component, chartDir, len(dimgs))
+	}
+
+	if _, _, _, err := getComponentChart(projectDir, "frontend", dappfile); err == nil {
+		t.Errorf("\n[EXPECTED]: undeclared component error\n[GOT]: no error")
+	}
+}
@@ -0,0 +1,272 @@
+package deploy
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flant/kubedog/pkg/kube"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flant/dapp/pkg/logger"
+)
+
+const containersLogsPollPeriod = 2 * time.Second
+
+// followContainersLogs prints logs of the resource pods of the current revision until stop is closed.
+// Only logs of the specified containers are printed if containers are set.
+func followContainersLogs(template *Template, namespace string, containers []string, since time.Time, prefix string, stop <-chan struct{}) {
+	followed := make(map[string]bool)
+	var mux sync.Mutex
+
+	for {
+		pods, err := resourcePods(template, namespace)
+		if err != nil {
+			logger.LogErrorF("%sERROR getting pods: %s\n", prefix, err)
+		}
+
+		for _, pod := range pods {
+			podContainers := containers
+			if len(podContainers) == 0 {
+				for _, container := range pod.Spec.Containers {
+					podContainers = append(podContainers, container.Name)
+				}
+			}
+
+			for _, container := range podContainers {
+				if !isContainerStarted(pod, container) {
+					continue
+				}
+
+				id := fmt.Sprintf("%s/%s", pod.Name, container)
+
+				mux.Lock()
+				if followed[id] {
+					mux.Unlock()
+					continue
+				}
+				followed[id] = true
+				mux.Unlock()
+
+				go func(podName, container, id string) {
+					if err := followContainerLogs(podName, container, namespace, since, prefix, stop); err != nil {
+						logger.LogErrorF("%sERROR following po/%s container/%s logs: %s\n", prefix, podName, container, err)
+
+						mux.Lock()
+						delete(followed, id)
+						mux.Unlock()
+					}
+				}(pod.Name, container, id)
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(containersLogsPollPeriod):
+		}
+	}
+}
+
+func followContainerLogs(podName, container, namespace string, since time.Time, prefix string, stop <-chan struct{}) error {
+	req := kube.Kubernetes.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container: container,
+		Follow:    true,
+		SinceTime: &metav1.Time{Time: since},
+	})
+
+	stream, err := req.Stream()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-stop:
+		case <-done:
+		}
+		stream.Close()
+	}()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		logger.LogF("%spo/%s container/%s: %s\n", prefix, podName, container, scanner.Text())
+	}
+
+	return nil
+}
+
+func isContainerStarted(pod corev1.Pod, container string) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container {
+			return status.State.Running != nil || status.State.Terminated != nil
+		}
+	}
+
+	return false
+}
+
+const (
+	podTemplateHashLabel         = "pod-template-hash"
+	controllerRevisionHashLabel  = "controller-revision-hash"
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+)
+
+// resourcePods returns pods of the current revision of the resource.
+// Pods of old ReplicaSets and controller revisions are skipped: failures of the previous release should not fail tracking of the new one.
+// No pods are returned until the controller observes the current generation of the resource.
+func resourcePods(template *Template, namespace string) ([]corev1.Pod, error) {
+	name := template.Metadata.Name
+
+	var selector *metav1.LabelSelector
+	var revisionLabel, revision string
+
+	switch strings.ToLower(template.Kind) {
+	case "pod":
+		pod, err := kube.Kubernetes.CoreV1().Pods(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return []corev1.Pod{*pod}, nil
+	case "deployment":
+		obj, err := kube.Kubernetes.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if obj.Status.ObservedGeneration < obj.Generation {
+			return nil, nil
+		}
+
+		selector = obj.Spec.Selector
+		revisionLabel = podTemplateHashLabel
+		revision, err = deploymentPodTemplateHash(obj, namespace)
+		if err != nil {
+			return nil, err
+		}
+	case "statefulset":
+		obj, err := kube.Kubernetes.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if obj.Status.ObservedGeneration < obj.Generation {
+			return nil, nil
+		}
+
+		selector = obj.Spec.Selector
+		revisionLabel = controllerRevisionHashLabel
+		revision = obj.Status.UpdateRevision
+	case "daemonset":
+		obj, err := kube.Kubernetes.AppsV1().DaemonSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if obj.Status.ObservedGeneration < obj.Generation {
+			return nil, nil
+		}
+
+		selector = obj.Spec.Selector
+		revisionLabel = controllerRevisionHashLabel
+		revision, err = daemonSetRevisionHash(obj, namespace)
+		if err != nil {
+			return nil, err
+		}
+	case "job":
+		// Pods of the job are selected by controller-uid of the job object
+		obj, err := kube.Kubernetes.BatchV1().Jobs(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = obj.Spec.Selector
+	default:
+		return nil, fmt.Errorf("kind %s is not supported", template.Kind)
+	}
+
+	if revisionLabel != "" && revision == "" {
+		// Pods of the current revision are not created yet
+		return nil, nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := kube.Kubernetes.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	if revisionLabel == "" {
+		return list.Items, nil
+	}
+
+	var pods []corev1.Pod
+	for _, pod := range list.Items {
+		if pod.Labels[revisionLabel] == revision {
+			pods = append(pods, pod)
+		}
+	}
+
+	return pods, nil
+}
+
+// deploymentPodTemplateHash returns pod-template-hash of the ReplicaSet of the current deployment revision.
+func deploymentPodTemplateHash(deployment *appsv1.Deployment, namespace string) (string, error) {
+	deploymentRevision := deployment.Annotations[deploymentRevisionAnnotation]
+	if deploymentRevision == "" {
+		return "", nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+
+	list, err := kube.Kubernetes.AppsV1().ReplicaSets(namespace).List(metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		return "", err
+	}
+
+	for _, rs := range list.Items {
+		if metav1.IsControlledBy(&rs, deployment) && rs.Annotations[deploymentRevisionAnnotation] == deploymentRevision {
+			return rs.Labels[podTemplateHashLabel], nil
+		}
+	}
+
+	return "", nil
+}
+
+// daemonSetRevisionHash returns controller-revision-hash of the latest ControllerRevision of the daemonset.
+func daemonSetRevisionHash(daemonSet *appsv1.DaemonSet, namespace string) (string, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(daemonSet.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+
+	list, err := kube.Kubernetes.AppsV1().ControllerRevisions(namespace).List(metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		return "", err
+	}
+
+	var latest *appsv1.ControllerRevision
+	for ind := range list.Items {
+		revision := &list.Items[ind]
+		if metav1.IsControlledBy(revision, daemonSet) && (latest == nil || revision.Revision > latest.Revision) {
+			latest = revision
+		}
+	}
+
+	if latest == nil {
+		return "", nil
+	}
+
+	return latest.Labels[controllerRevisionHashLabel], nil
+}
@@ -0,0 +1,115 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/flant/kubedog/pkg/kube"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testPod(name string, labels map[string]string, waitingReason string) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "app", Labels: labels}}
+
+	state := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	if waitingReason != "" {
+		state = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: waitingReason}}
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "web", State: state}}
+
+	return pod
+}
+
+func testPodNames(pods []corev1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+// setTestKubernetes sets fake kubernetes client with the objects and returns function to restore the client.
+func setTestKubernetes(objects ...runtime.Object) func() {
+	client := kube.Kubernetes
+	kube.Kubernetes = fake.NewSimpleClientset(objects...)
+	return func() { kube.Kubernetes = client }
+}
+
+func TestResourcePods_Deployment(t *testing.T) {
+	isController := true
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "app", UID: types.UID("web-uid"), Generation: 2, Annotations: map[string]string{deploymentRevisionAnnotation: "2"}},
+		Spec:       appsv1.DeploymentSpec{Selector: selector},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 2},
+	}
+	ownerRefs := []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: deployment.UID, Controller: &isController}}
+
+	replicaSet := func(name, revision, hash string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "app", OwnerReferences: ownerRefs,
+			Labels:      map[string]string{"app": "web", podTemplateHashLabel: hash},
+			Annotations: map[string]string{deploymentRevisionAnnotation: revision},
+		}}
+	}
+
+	defer setTestKubernetes(
+		deployment,
+		replicaSet("web-old", "1", "old"),
+		replicaSet("web-new", "2", "new"),
+		testPod("web-old-1", map[string]string{"app": "web", podTemplateHashLabel: "old"}, "CrashLoopBackOff"),
+		testPod("web-new-1", map[string]string{"app": "web", podTemplateHashLabel: "new"}, ""),
+	)()
+
+	template := parseTestTemplate(t, "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n")
+
+	pods, err := resourcePods(template, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := testPodNames(pods); len(names) != 1 || names[0] != "web-new-1" {
+		t.Fatalf("\n[EXPECTED]: [web-new-1]\n[GOT]: %v", names)
+	}
+
+	if err := resourceFailure("Deployment", map[string]interface{}{}, pods); err != nil {
+		t.Errorf("\n[EXPECTED]: crash looping pods of the old ReplicaSet are ignored\n[GOT]: %s", err)
+	}
+
+	// Rollout is not observed by the controller yet: the current ReplicaSet is unknown
+	deployment.Generation = 3
+	if _, err := kube.Kubernetes.AppsV1().Deployments("app").Update(deployment); err != nil {
+		t.Fatal(err)
+	}
+
+	if pods, err := resourcePods(template, "app"); err != nil || len(pods) != 0 {
+		t.Errorf("\n[EXPECTED]: no pods\n[GOT]: %v %v", testPodNames(pods), err)
+	}
+}
+
+func TestResourcePods_StatefulSet(t *testing.T) {
+	defer setTestKubernetes(
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "app"},
+			Spec:       appsv1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+			Status:     appsv1.StatefulSetStatus{UpdateRevision: "db-new"},
+		},
+		testPod("db-0", map[string]string{"app": "db", controllerRevisionHashLabel: "db-new"}, ""),
+		testPod("db-1", map[string]string{"app": "db", controllerRevisionHashLabel: "db-old"}, "ImagePullBackOff"),
+	)()
+
+	pods, err := resourcePods(parseTestTemplate(t, "apiVersion: apps/v1\nkind: StatefulSet\nmetadata:\n  name: db\n"), "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := testPodNames(pods); len(names) != 1 || names[0] != "db-0" {
+		t.Errorf("\n[EXPECTED]: [db-0]\n[GOT]: %v", names)
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/flant/dapp/pkg/dapp"
 	"github.com/flant/dapp/pkg/deploy/secret"
+	"github.com/flant/dapp/pkg/logger"
 	"github.com/ghodss/yaml"
 	"github.com/otiai10/copy"
 	"github.com/satori/go.uuid"
@@ -19,8 +20,11 @@ import (
 
 const (
 	ProjectHelmChartDir            = ".helm"
-	ProjectDefaultSecretValuesFile = ProjectHelmChartDir + "/secret-values.yaml"
-	ProjectSecretDir               = ProjectHelmChartDir + "/secret"
+	ProjectDefaultSecretValuesFile = ProjectHelmChartDir + "/" + chartSecretValuesFile
+	ProjectSecretDir               = ProjectHelmChartDir + "/" + chartSecretDir
+
+	chartSecretValuesFile = "secret-values.yaml"
+	chartSecretDir        = "secret"
 
 	DappChartDecodedSecretDir = "decoded-secret"
 	DappChartMoreValuesDir    = "more-values"
@@ -33,13 +37,40 @@ type DappChart struct {
 	SetString []string
 
 	moreValuesCounter uint
+
+	globalAnnotations    map[string]string
+	globalLabels         map[string]string
+	injectGlobalMetadata bool
 }
 
 func (chart *DappChart) SetGlobalAnnotation(name, value string) error {
-	// TODO: https://github.com/flant/dapp/issues/1069
+	if chart.globalAnnotations == nil {
+		chart.globalAnnotations = make(map[string]string)
+	}
+	chart.globalAnnotations[name] = value
+
 	return nil
 }
 
+func (chart *DappChart) SetGlobalLabel(name, value string) error {
+	if chart.globalLabels == nil {
+		chart.globalLabels = make(map[string]string)
+	}
+	chart.globalLabels[name] = value
+
+	return nil
+}
+
+// SetInjectGlobalMetadata enables injection of global annotations and labels into rendered manifests of all resources.
+// Such chart is installed as already rendered static templates, see prepareRenderedChart.
+func (chart *DappChart) SetInjectGlobalMetadata(inject bool) {
+	chart.injectGlobalMetadata = inject
+}
+
+func (chart *DappChart) hasInjectedGlobalMetadata() bool {
+	return chart.injectGlobalMetadata && (len(chart.globalAnnotations) > 0 || len(chart.globalLabels) > 0)
+}
+
 func (chart *DappChart) SetValues(values map[string]interface{}) error {
 	path := filepath.Join(chart.ChartDir, DappChartMoreValuesDir, fmt.Sprintf("%d.yaml", chart.moreValuesCounter))
 	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
@@ -107,20 +138,52 @@ func (chart *DappChart) SetSecretValuesFile(path string, m secret.Manager) error
 	return nil
 }
 
+func (chart *DappChart) SetProviderSecretValues(provider secret.SecretValuesProvider) error {
+	values, err := provider.GetValues()
+	if err != nil {
+		return fmt.Errorf("cannot get secret values from provider '%s': %s", provider.Name(), err)
+	}
+
+	return chart.SetValues(values)
+}
+
 func (chart *DappChart) Deploy(releaseName string, namespace string, opts HelmChartOptions) error {
-	return DeployHelmChart(chart.ChartDir, releaseName, namespace, HelmChartOptions{
-		CommonHelmOptions: CommonHelmOptions{KubeContext: opts.KubeContext},
+	chartDir := chart.ChartDir
+	if chart.hasInjectedGlobalMetadata() {
+		renderedChartDir, err := chart.prepareRenderedChart(releaseName, namespace)
+		if err != nil {
+			return err
+		}
+		if !logger.IsDebug("deploy") {
+			defer os.RemoveAll(renderedChartDir)
+		}
+
+		chartDir = renderedChartDir
+	}
+
+	return DeployHelmChart(chartDir, releaseName, namespace, HelmChartOptions{
+		CommonHelmOptions: opts.CommonHelmOptions,
 		Set:               append(chart.Set, opts.Set...),
 		SetString:         append(chart.SetString, opts.SetString...),
 		Values:            append(chart.Values, opts.Values...),
 		DryRun:            opts.DryRun,
 		Debug:             opts.Debug,
+		Timeout:           opts.Timeout,
+		AutoRollback:      opts.AutoRollback,
 	})
 }
 
 func (chart *DappChart) Render(namespace string) (string, error) {
+	return chart.RenderRelease("", namespace)
+}
+
+func (chart *DappChart) RenderRelease(releaseName, namespace string) (string, error) {
 	args := []string{"template", chart.ChartDir}
 
+	if releaseName != "" {
+		args = append(args, "--name", releaseName)
+	}
+
 	args = append(args, "--namespace", namespace)
 
 	for _, set := range chart.Set {
@@ -138,9 +201,69 @@ func (chart *DappChart) Render(namespace string) (string, error) {
 		return "", fmt.Errorf("%s\n%s", stdout, stderr)
 	}
 
+	if chart.hasInjectedGlobalMetadata() {
+		return injectGlobalMetadata(stdout, chart.globalAnnotations, chart.globalLabels)
+	}
+
 	return stdout, nil
 }
 
+// prepareRenderedChart creates chart with already rendered templates of the release:
+// helm has no way to change manifests between rendering and installing, so global annotations and labels
+// are injected into rendered manifests, which are installed as static templates.
+// Values are still passed to helm to keep them in the release.
+// Templates are rendered without the release state and the cluster capabilities (.Release.IsUpgrade, .Release.Revision, .Capabilities),
+// NOTES.txt is not included, because it cannot be rendered by helm template.
+func (chart *DappChart) prepareRenderedChart(releaseName, namespace string) (string, error) {
+	manifest, err := chart.RenderRelease(releaseName, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	renderedChartDir := filepath.Join(dapp.GetTmpDir(), fmt.Sprintf("dapp-rendered-chart-%s", uuid.NewV4().String()))
+	if err := os.MkdirAll(filepath.Join(renderedChartDir, "templates"), os.ModePerm); err != nil {
+		return "", err
+	}
+
+	for _, file := range []string{"Chart.yaml", "values.yaml"} {
+		data, err := ioutil.ReadFile(filepath.Join(chart.ChartDir, file))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(renderedChartDir, file), data, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	for ind, doc := range splitManifestDocs(manifest) {
+		path := filepath.Join(renderedChartDir, "templates", fmt.Sprintf("dapp-rendered-%04d.yaml", ind))
+		data := strings.Replace(doc, "{{", "{{\"{{\"}}", -1)
+
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			return "", fmt.Errorf("error writing file %s: %s", path, err)
+		}
+	}
+
+	return renderedChartDir, nil
+}
+
+func (chart *DappChart) Diff(releaseName, namespace string, opts CommonHelmOptions) ([]*ResourceDiff, error) {
+	desiredManifest, err := chart.RenderRelease(releaseName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	currentManifest, err := GetHelmReleaseManifest(releaseName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffManifests(currentManifest, desiredManifest, namespace)
+}
+
 type ChartConfig struct {
 	Name string `json:"name"`
 }
@@ -161,9 +284,7 @@ func (chart *DappChart) Lint() error {
 		return fmt.Errorf("error reading %s: %s", chartConfigPath, err)
 	}
 
-	if debug() {
-		fmt.Printf("Read chart config:\n%s\n", data)
-	}
+	logger.LogDebugF("deploy", "Read chart config:\n%s\n", data)
 
 	var cc ChartConfig
 	err = yaml.Unmarshal(data, &cc)
@@ -196,20 +317,29 @@ func (chart *DappChart) Lint() error {
 		return fmt.Errorf("helm lint failed: %s\n%s", err, output.String())
 	}
 
-	fmt.Printf("%s", output.String())
+	logger.LogF("%s", output.String())
 
 	return nil
 }
 
 func GenerateDappChart(projectDir string, m secret.Manager) (*DappChart, error) {
+	return generateDappChartFromDir(filepath.Join(projectDir, ProjectHelmChartDir), m)
+}
+
+func generateDappChartFromDir(projectHelmDir string, m secret.Manager) (*DappChart, error) {
 	tmpChartPath := filepath.Join(dapp.GetTmpDir(), fmt.Sprintf("dapp-chart-%s", uuid.NewV4().String()))
-	return PrepareDappChart(projectDir, tmpChartPath, m)
+	return prepareDappChartFromDir(projectHelmDir, tmpChartPath, m)
 }
 
 func PrepareDappChart(projectDir string, targetDir string, m secret.Manager) (*DappChart, error) {
+	return prepareDappChartFromDir(filepath.Join(projectDir, ProjectHelmChartDir), targetDir, m)
+}
+
+// prepareDappChartFromDir prepares chart from project chart dir (.helm or component dir .helm/<component>)
+// with secret-values.yaml and secret dir of the chart dir.
+func prepareDappChartFromDir(projectHelmDir string, targetDir string, m secret.Manager) (*DappChart, error) {
 	dappChart := &DappChart{ChartDir: targetDir}
 
-	projectHelmDir := filepath.Join(projectDir, ".helm")
 	err := copy.Copy(projectHelmDir, targetDir)
 	if err != nil {
 		return nil, fmt.Errorf("unable to copy project helm dir %s into %s: %s", projectHelmDir, targetDir, err)
@@ -225,7 +355,7 @@ func PrepareDappChart(projectDir string, targetDir string, m secret.Manager) (*D
 		return nil, fmt.Errorf("unable to write %s: %s", helpersTplPath, err)
 	}
 
-	defaultSecretValues := filepath.Join(projectDir, ProjectDefaultSecretValuesFile)
+	defaultSecretValues := filepath.Join(projectHelmDir, chartSecretValuesFile)
 	if _, err := os.Stat(defaultSecretValues); !os.IsNotExist(err) {
 		err := dappChart.SetSecretValuesFile(defaultSecretValues, m)
 		if err != nil {
@@ -233,7 +363,7 @@ func PrepareDappChart(projectDir string, targetDir string, m secret.Manager) (*D
 		}
 	}
 
-	secretDir := filepath.Join(projectDir, ProjectSecretDir)
+	secretDir := filepath.Join(projectHelmDir, chartSecretDir)
 	if _, err := os.Stat(secretDir); !os.IsNotExist(err) {
 		err := filepath.Walk(secretDir, func(path string, info os.FileInfo, accessErr error) error {
 			if accessErr != nil {
@@ -133,4 +133,16 @@ image: {{ tuple $name $context | include "_dimg2" }}
 {{      tuple $context | include "_dapp_container_env" }}
 {{-   end -}}
 {{- end -}}
+
+{{- define "dapp_global_annotations" -}}
+{{-   range $name, $value := (default (dict) (default (dict) .Values.global.dapp.metadata).annotations) }}
+{{ $name }}: {{ $value | quote }}
+{{-   end -}}
+{{- end -}}
+
+{{- define "dapp_global_labels" -}}
+{{-   range $name, $value := (default (dict) (default (dict) .Values.global.dapp.metadata).labels) }}
+{{ $name }}: {{ $value | quote }}
+{{-   end -}}
+{{- end -}}
 `)
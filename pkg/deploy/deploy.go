@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/flant/dapp/pkg/config"
+	"github.com/flant/dapp/pkg/deploy/secret"
 	"github.com/flant/dapp/pkg/docker_registry"
 	"github.com/flant/dapp/pkg/git_repo"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 type DeployOptions struct {
@@ -18,6 +20,15 @@ type DeployOptions struct {
 	SetString       []string
 	Timeout         time.Duration
 	WithoutRegistry bool
+	Diff            bool
+	AutoRollback    bool
+	AddAnnotations  []string
+	AddLabels       []string
+	KubeLock        bool
+	KubeLockTimeout time.Duration
+	EnforcePolicies bool
+
+	InjectGlobalMetadata bool
 }
 
 type DimgInfoGetterStub struct {
@@ -45,6 +56,14 @@ func (d *DimgInfoGetterStub) GetImageId() (string, error) {
 	return docker_registry.ImageId(d.GetImageName())
 }
 
+func (d *DimgInfoGetterStub) GetImageDigest() (string, error) {
+	return docker_registry.ImageDigest(d.GetImageName())
+}
+
+func (d *DimgInfoGetterStub) GetStageSignature() (string, error) {
+	return getImageStageSignature(d.GetImageName())
+}
+
 type DimgInfo struct {
 	Config          *config.Dimg
 	WithoutRegistry bool
@@ -76,24 +95,73 @@ func (d *DimgInfo) GetImageId() (string, error) {
 
 	res, err := docker_registry.ImageId(imageName)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR getting image %s id: %s\n", imageName, err)
+		logger.LogErrorF("ERROR getting image %s id: %s\n", imageName, err)
+		return "", nil
+	}
+
+	return res, nil
+}
+
+func (d *DimgInfo) GetImageDigest() (string, error) {
+	if d.WithoutRegistry {
+		return "", nil
+	}
+
+	imageName := d.GetImageName()
+
+	res, err := docker_registry.ImageDigest(imageName)
+	if err != nil {
+		logger.LogErrorF("ERROR getting image %s digest: %s\n", imageName, err)
+		return "", nil
+	}
+
+	return res, nil
+}
+
+func (d *DimgInfo) GetStageSignature() (string, error) {
+	if d.WithoutRegistry {
+		return "", nil
+	}
+
+	imageName := d.GetImageName()
+
+	res, err := getImageStageSignature(imageName)
+	if err != nil {
+		logger.LogErrorF("ERROR getting image %s stage signature: %s\n", imageName, err)
 		return "", nil
 	}
 
 	return res, nil
 }
 
-func RunDeploy(projectName, projectDir, releaseName, namespace, kubeContext, repo, tag string, dappfile []*config.Dimg, opts DeployOptions) error {
-	if debug() {
-		fmt.Printf("Deploy options: %#v\n", opts)
-		fmt.Printf("Namespace: %s\n", namespace)
+// getImageStageSignature returns signature of the last stage dimg image was built from (empty for images pushed by old dapp versions).
+func getImageStageSignature(imageName string) (string, error) {
+	configFile, err := docker_registry.ImageConfigFile(imageName)
+	if err != nil {
+		return "", err
 	}
 
-	m, err := getSafeSecretManager(projectDir, opts.SecretValues)
+	return configFile.Config.Labels[docker_registry.DimgStageSignatureLabel], nil
+}
+
+// RunDeploy deploys the project chart.
+// Returns true when the release has been changed according to the diff shown before deploy (only with Diff option).
+func RunDeploy(projectName, projectDir, releaseName, namespace, kubeContext, repo, tag string, dappfile []*config.Dimg, opts DeployOptions) (bool, error) {
+	logger.LogDebugF("deploy", "Deploy options: %#v\n", opts)
+	logger.LogDebugF("deploy", "Namespace: %s\n", namespace)
+
+	projectHelmDir := filepath.Join(projectDir, ProjectHelmChartDir)
+
+	m, err := getSafeSecretManager(projectDir, []string{projectHelmDir}, opts.SecretValues)
 	if err != nil {
-		return fmt.Errorf("cannot get project secret: %s", err)
+		return false, fmt.Errorf("cannot get project secret: %s", err)
 	}
 
+	return deployChart(projectHelmDir, projectName, projectDir, releaseName, namespace, kubeContext, repo, tag, dappfile, m, opts)
+}
+
+// deployChart deploys project chart dir (.helm or component dir) with service values of the dimgs.
+func deployChart(projectHelmDir, projectName, projectDir, releaseName, namespace, kubeContext, repo, tag string, dappfile []*config.Dimg, m secret.Manager, opts DeployOptions) (bool, error) {
 	localGit := &git_repo.Local{Path: projectDir, GitDir: filepath.Join(projectDir, ".git")}
 
 	var images []DimgInfoGetter
@@ -104,17 +172,52 @@ func RunDeploy(projectName, projectDir, releaseName, namespace, kubeContext, rep
 
 	serviceValues, err := GetServiceValues(projectName, repo, namespace, tag, localGit, images, ServiceValuesOptions{})
 	if err != nil {
-		return fmt.Errorf("error creating service values: %s", err)
+		return false, fmt.Errorf("error creating service values: %s", err)
 	}
 
-	dappChart, err := getDappChart(projectDir, m, opts.Values, opts.SecretValues, opts.Set, opts.SetString, serviceValues)
+	dappChart, err := getDappChartFromDir(projectHelmDir, projectDir, m, true, opts.Values, opts.SecretValues, opts.Set, opts.SetString, serviceValues)
 	if err != nil {
-		return err
+		return false, err
 	}
-	if !debug() {
+	if !logger.IsDebug("deploy") {
 		// Do not remove tmp chart in debug
 		defer os.RemoveAll(dappChart.ChartDir)
 	}
 
-	return dappChart.Deploy(releaseName, namespace, HelmChartOptions{CommonHelmOptions: CommonHelmOptions{KubeContext: kubeContext}, Timeout: opts.Timeout})
+	if err := setGlobalMetadata(dappChart, projectName, projectDir, globalMetadataOptions{AddAnnotations: opts.AddAnnotations, AddLabels: opts.AddLabels, Inject: opts.InjectGlobalMetadata}); err != nil {
+		return false, err
+	}
+
+	var hasChanges bool
+	if opts.Diff {
+		diffs, err := dappChart.Diff(releaseName, namespace, CommonHelmOptions{KubeContext: kubeContext})
+		if err != nil {
+			return false, fmt.Errorf("cannot get release diff: %s", err)
+		}
+
+		PrintManifestsDiff(logger.GetOutStream(), diffs)
+		hasChanges = len(diffs) != 0
+	}
+
+	if opts.EnforcePolicies {
+		manifest, err := dappChart.RenderRelease(releaseName, namespace)
+		if err != nil {
+			return false, fmt.Errorf("cannot render chart: %s", err)
+		}
+
+		if err := CheckPolicies(manifest, projectDir, PolicyOptions{Repo: repo}); err != nil {
+			return false, err
+		}
+	}
+
+	err = dappChart.Deploy(releaseName, namespace, HelmChartOptions{
+		CommonHelmOptions: CommonHelmOptions{KubeContext: kubeContext, KubeLock: opts.KubeLock, KubeLockTimeout: opts.KubeLockTimeout},
+		Timeout:           opts.Timeout,
+		AutoRollback:      opts.AutoRollback,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return hasChanges, nil
 }
@@ -0,0 +1,88 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/flant/dapp/pkg/config"
+	"github.com/flant/dapp/pkg/git_repo"
+	"github.com/flant/dapp/pkg/logger"
+)
+
+type DiffOptions struct {
+	Values          []string
+	SecretValues    []string
+	Set             []string
+	SetString       []string
+	WithoutRegistry bool
+	AddAnnotations  []string
+	AddLabels       []string
+
+	InjectGlobalMetadata bool
+
+	// Component chart .helm/COMPONENT is compared with the component release instead of the project chart
+	Component string
+}
+
+// DiffChangesExitCode is exit code of diff and deploy with diff when the release is changed and exit code is requested.
+const DiffChangesExitCode = 2
+
+// RunDiff prints the difference between manifests of the current release revision and the rendered chart.
+// Returns true when the release would be changed by deploy.
+func RunDiff(projectName, projectDir, releaseName, namespace, kubeContext, repo, tag string, dappfile []*config.Dimg, opts DiffOptions) (bool, error) {
+	logger.LogDebugF("deploy", "Diff options: %#v\n", opts)
+	logger.LogDebugF("deploy", "Namespace: %s\n", namespace)
+
+	component, projectHelmDir, dappfile, err := getComponentChart(projectDir, opts.Component, dappfile)
+	if err != nil {
+		return false, err
+	}
+
+	if component != nil {
+		releaseName, err = component.ReleaseName(projectName, namespace, releaseName)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	m, err := getSafeSecretManager(projectDir, []string{projectHelmDir}, opts.SecretValues)
+	if err != nil {
+		return false, fmt.Errorf("cannot get project secret: %s", err)
+	}
+
+	localGit := &git_repo.Local{Path: projectDir, GitDir: filepath.Join(projectDir, ".git")}
+
+	var images []DimgInfoGetter
+	for _, dimg := range dappfile {
+		d := &DimgInfo{Config: dimg, WithoutRegistry: opts.WithoutRegistry, Repo: repo, Tag: tag}
+		images = append(images, d)
+	}
+
+	serviceValues, err := GetServiceValues(projectName, repo, namespace, tag, localGit, images, ServiceValuesOptions{})
+	if err != nil {
+		return false, fmt.Errorf("error creating service values: %s", err)
+	}
+
+	dappChart, err := getDappChartFromDir(projectHelmDir, projectDir, m, true, opts.Values, opts.SecretValues, opts.Set, opts.SetString, serviceValues)
+	if err != nil {
+		return false, err
+	}
+	if !logger.IsDebug("deploy") {
+		// Do not remove tmp chart in debug
+		defer os.RemoveAll(dappChart.ChartDir)
+	}
+
+	if err := setGlobalMetadata(dappChart, projectName, projectDir, globalMetadataOptions{AddAnnotations: opts.AddAnnotations, AddLabels: opts.AddLabels, Inject: opts.InjectGlobalMetadata}); err != nil {
+		return false, err
+	}
+
+	diffs, err := dappChart.Diff(releaseName, namespace, CommonHelmOptions{KubeContext: kubeContext})
+	if err != nil {
+		return false, err
+	}
+
+	PrintManifestsDiff(os.Stdout, diffs)
+
+	return len(diffs) != 0, nil
+}
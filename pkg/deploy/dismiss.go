@@ -2,29 +2,32 @@ package deploy
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/flant/kubedog/pkg/kube"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flant/dapp/pkg/logger"
 )
 
 type DismissOptions struct {
-	WithNamespace bool
-	KubeContext   string
+	WithNamespace   bool
+	KubeContext     string
+	KubeLock        bool
+	KubeLockTimeout time.Duration
 }
 
 func RunDismiss(releaseName, namespace, kubeContext string, opts DismissOptions) error {
-	if debug() {
-		fmt.Printf("Dismiss options: %#v\n", opts)
-		fmt.Printf("Namespace: %s\n", namespace)
-	}
+	logger.LogDebugF("deploy", "Dismiss options: %#v\n", opts)
+	logger.LogDebugF("deploy", "Namespace: %s\n", namespace)
 
-	err := PurgeHelmRelease(releaseName, CommonHelmOptions{KubeContext: opts.KubeContext})
+	err := PurgeHelmRelease(releaseName, namespace, CommonHelmOptions{KubeContext: opts.KubeContext, KubeLock: opts.KubeLock, KubeLockTimeout: opts.KubeLockTimeout})
 	if err != nil {
 		return err
 	}
 
 	if opts.WithNamespace {
-		fmt.Printf("# Deleting kubernetes namespace '%s'...\n", namespace)
+		logger.LogF("# Deleting kubernetes namespace '%s'...\n", namespace)
 
 		err := kube.Kubernetes.CoreV1().Namespaces().Delete(namespace, &metav1.DeleteOptions{})
 		if err != nil {
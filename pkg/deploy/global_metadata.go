@@ -0,0 +1,381 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/git_repo"
+)
+
+const (
+	ProjectAnnotation       = "dapp/project"
+	CommitAnnotation        = "dapp/commit"
+	CiPipelineUrlAnnotation = "dapp/ci-pipeline-url"
+	VersionAnnotation       = "dapp/version"
+	DeployedAtAnnotation    = "dapp/deployed-at"
+
+	ProjectLabel = "dapp/project"
+)
+
+var (
+	manifestSeparatorRegexp = regexp.MustCompile("(?:^|\\s*\n)---\\s*")
+	metadataLineRegexp      = regexp.MustCompile("^metadata:\\s*(#.*)?$")
+)
+
+type globalMetadataOptions struct {
+	AddAnnotations []string
+	AddLabels      []string
+
+	// Inject enables injection into rendered manifests of all resources, otherwise metadata is available only for templates with dapp helpers
+	Inject bool
+	// WithoutDeployTime skips deployed-at annotation to keep rendered manifests deterministic
+	WithoutDeployTime bool
+}
+
+// setGlobalMetadata sets annotations and labels to map any resource of the release back to the build that produced it.
+// Metadata is passed into the chart values (.Values.global.dapp.metadata) for dapp_global_annotations and dapp_global_labels helpers
+// and is injected into all resources only with opts.Inject.
+func setGlobalMetadata(chart *DappChart, projectName, projectDir string, opts globalMetadataOptions) error {
+	chart.SetGlobalAnnotation(ProjectAnnotation, projectName)
+	chart.SetGlobalAnnotation(VersionAnnotation, dapp.Version)
+	if !opts.WithoutDeployTime {
+		chart.SetGlobalAnnotation(DeployedAtAnnotation, time.Now().UTC().Format(time.RFC3339))
+	}
+	chart.SetGlobalLabel(ProjectLabel, projectName)
+
+	if _, err := os.Stat(filepath.Join(projectDir, ".git")); err == nil {
+		localGit := &git_repo.Local{Path: projectDir, GitDir: filepath.Join(projectDir, ".git")}
+		if commit := localGit.GetHeadCommit(); commit != "" {
+			chart.SetGlobalAnnotation(CommitAnnotation, commit)
+		}
+	}
+
+	if ciPipelineUrl := os.Getenv("CI_PIPELINE_URL"); ciPipelineUrl != "" {
+		chart.SetGlobalAnnotation(CiPipelineUrlAnnotation, ciPipelineUrl)
+	}
+
+	for _, annotation := range opts.AddAnnotations {
+		name, value, err := parseKeyValue(annotation)
+		if err != nil {
+			return fmt.Errorf("bad annotation '%s': %s", annotation, err)
+		}
+		chart.SetGlobalAnnotation(name, value)
+	}
+
+	for _, label := range opts.AddLabels {
+		name, value, err := parseKeyValue(label)
+		if err != nil {
+			return fmt.Errorf("bad label '%s': %s", label, err)
+		}
+		chart.SetGlobalLabel(name, value)
+	}
+
+	chart.SetInjectGlobalMetadata(opts.Inject)
+
+	return chart.SetValues(map[string]interface{}{
+		"global": map[string]interface{}{
+			"dapp": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": chart.globalAnnotations,
+					"labels":      chart.globalLabels,
+				},
+			},
+		},
+	})
+}
+
+func parseKeyValue(s string) (string, string, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("expected format key=value")
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// splitManifestDocs splits multi-document manifest preserving documents order.
+func splitManifestDocs(manifest string) []string {
+	var res []string
+	for _, doc := range manifestSeparatorRegexp.Split(manifest, -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		res = append(res, doc)
+	}
+
+	return res
+}
+
+// injectGlobalMetadata adds annotations and labels into metadata of each resource of the manifest.
+// Only resource metadata is changed: pod templates and selectors are kept as is.
+func injectGlobalMetadata(manifest string, annotations, labels map[string]string) (string, error) {
+	var docs []string
+
+	for _, doc := range splitManifestDocs(manifest) {
+		newDoc, err := injectDocGlobalMetadata(doc, annotations, labels)
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, newDoc)
+	}
+
+	if len(docs) == 0 {
+		return manifest, nil
+	}
+
+	return "---\n" + strings.Join(docs, "---\n"), nil
+}
+
+func injectDocGlobalMetadata(doc string, annotations, labels map[string]string) (string, error) {
+	var resource struct {
+		Kind     string      `yaml:"kind"`
+		Metadata interface{} `yaml:"metadata"`
+	}
+	if err := yaml.Unmarshal([]byte(doc), &resource); err != nil {
+		return "", fmt.Errorf("cannot parse manifest:\n%s\n%s", doc, err)
+	}
+
+	if resource.Kind == "" || resource.Metadata == nil {
+		return ensureTrailingNewline(doc), nil
+	}
+
+	// Values are inserted as text, because yaml round trip changes unquoted scalars like `on` or `y`
+	lines := strings.Split(strings.TrimRight(doc, "\n"), "\n")
+
+	lines, ok := injectMetadataFieldLines(lines, "annotations", annotations)
+	if ok {
+		lines, ok = injectMetadataFieldLines(lines, "labels", labels)
+	}
+	if !ok {
+		return injectDocGlobalMetadataByYaml(doc, annotations, labels)
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// injectMetadataFieldLines adds values into map field of the top-level metadata of block style yaml document.
+// Returns false if the document has unsupported format.
+func injectMetadataFieldLines(lines []string, field string, values map[string]string) ([]string, bool) {
+	if len(values) == 0 {
+		return lines, true
+	}
+
+	metadataInd := -1
+	for ind, line := range lines {
+		if metadataLineRegexp.MatchString(line) {
+			metadataInd = ind
+			break
+		}
+	}
+	if metadataInd == -1 {
+		return nil, false
+	}
+
+	metadataEnd := blockEnd(lines, metadataInd, 0)
+
+	indent := "  "
+	for _, line := range lines[metadataInd+1 : metadataEnd] {
+		if !isBlankOrCommentLine(line) {
+			indent = line[:lineIndent(line)]
+			break
+		}
+	}
+
+	fieldRegexp := regexp.MustCompile(fmt.Sprintf("^%s%s:(.*)$", indent, field))
+
+	fieldInd := -1
+	for ind := metadataInd + 1; ind < metadataEnd; ind++ {
+		match := fieldRegexp.FindStringSubmatch(lines[ind])
+		if match == nil {
+			continue
+		}
+
+		switch value := strings.TrimSpace(match[1]); {
+		case value == "" || strings.HasPrefix(value, "#"):
+		case value == "{}" || value == "null" || value == "~":
+			lines[ind] = fmt.Sprintf("%s%s:", indent, field)
+		default:
+			return nil, false
+		}
+
+		fieldInd = ind
+		break
+	}
+
+	var res []string
+	if fieldInd == -1 {
+		res = append(res, lines[:metadataEnd]...)
+		res = append(res, fmt.Sprintf("%s%s:", indent, field))
+		res = append(res, metadataValuesLines(indent+indent, values)...)
+		return append(res, lines[metadataEnd:]...), true
+	}
+
+	fieldEnd := blockEnd(lines, fieldInd, len(indent))
+
+	// metadata is a top-level field, so its indent is the indent step of the document
+	valuesIndent := indent + indent
+	for _, line := range lines[fieldInd+1 : fieldEnd] {
+		if !isBlankOrCommentLine(line) {
+			valuesIndent = line[:lineIndent(line)]
+			break
+		}
+	}
+
+	res = append(res, lines[:fieldInd+1]...)
+	for ind := fieldInd + 1; ind < fieldEnd; ind++ {
+		if lineIndent(lines[ind]) == len(valuesIndent) && hasValuesKey(lines[ind][len(valuesIndent):], values) {
+			for ind+1 < fieldEnd && (isBlankOrCommentLine(lines[ind+1]) || lineIndent(lines[ind+1]) > len(valuesIndent)) {
+				ind++
+			}
+			continue
+		}
+		res = append(res, lines[ind])
+	}
+	res = append(res, metadataValuesLines(valuesIndent, values)...)
+
+	return append(res, lines[fieldEnd:]...), true
+}
+
+// blockEnd returns index of the line after the last line of the block started at the line blockInd.
+func blockEnd(lines []string, blockInd int, blockIndent int) int {
+	end := blockInd + 1
+	for ind := blockInd + 1; ind < len(lines); ind++ {
+		if isBlankOrCommentLine(lines[ind]) {
+			continue
+		}
+		if lineIndent(lines[ind]) <= blockIndent {
+			break
+		}
+		end = ind + 1
+	}
+
+	return end
+}
+
+func metadataValuesLines(indent string, values map[string]string) []string {
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var res []string
+	for _, name := range names {
+		data, _ := yaml.Marshal(yaml.MapSlice{{Key: name, Value: values[name]}})
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			res = append(res, indent+line)
+		}
+	}
+
+	return res
+}
+
+func hasValuesKey(line string, values map[string]string) bool {
+	for name := range values {
+		for _, key := range []string{name, fmt.Sprintf("%q", name), fmt.Sprintf("'%s'", name)} {
+			if strings.HasPrefix(line, key+":") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isBlankOrCommentLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+func lineIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// injectDocGlobalMetadataByYaml is used for documents with flow style metadata.
+func injectDocGlobalMetadataByYaml(doc string, annotations, labels map[string]string) (string, error) {
+	var comments []string
+	for _, line := range strings.Split(doc, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		comments = append(comments, line)
+	}
+
+	var obj yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+		return "", fmt.Errorf("cannot parse manifest:\n%s\n%s", doc, err)
+	}
+
+	metadata, _ := mapSliceValue(obj, "metadata").(yaml.MapSlice)
+	metadata = setMapSliceValues(metadata, "annotations", annotations)
+	metadata = setMapSliceValues(metadata, "labels", labels)
+	obj = setMapSliceValue(obj, "metadata", metadata)
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+
+	if len(comments) > 0 {
+		return strings.Join(comments, "\n") + "\n" + string(data), nil
+	}
+
+	return string(data), nil
+}
+
+func setMapSliceValues(obj yaml.MapSlice, key string, values map[string]string) yaml.MapSlice {
+	if len(values) == 0 {
+		return obj
+	}
+
+	current, _ := mapSliceValue(obj, key).(yaml.MapSlice)
+
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		current = setMapSliceValue(current, name, values[name])
+	}
+
+	return setMapSliceValue(obj, key, current)
+}
+
+func mapSliceValue(obj yaml.MapSlice, key string) interface{} {
+	for _, item := range obj {
+		if item.Key == key {
+			return item.Value
+		}
+	}
+
+	return nil
+}
+
+func setMapSliceValue(obj yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	for ind := range obj {
+		if obj[ind].Key == key {
+			obj[ind].Value = value
+			return obj
+		}
+	}
+
+	return append(obj, yaml.MapItem{Key: key, Value: value})
+}
+
+func ensureTrailingNewline(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		return s
+	}
+
+	return s + "\n"
+}
@@ -0,0 +1,189 @@
+package deploy
+
+import "testing"
+
+func TestInjectDocGlobalMetadata(t *testing.T) {
+	annotations := map[string]string{"dapp/project": "test", "dapp/commit": "abc"}
+	labels := map[string]string{"dapp/project": "test"}
+
+	expectations := []struct {
+		name     string
+		doc      string
+		expected string
+	}{
+		{
+			name: "no annotations and labels",
+			doc: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  enabled: on
+`,
+			expected: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  annotations:
+    dapp/commit: abc
+    dapp/project: test
+  labels:
+    dapp/project: test
+data:
+  enabled: on
+`,
+		},
+		{
+			name: "existing annotations are kept and overridden values replaced",
+			doc: `kind: Service
+metadata:
+    name: svc
+    annotations:
+        # comment
+        dapp/project: old
+        other: "value"
+        multiline: |
+          first
+          second
+    labels: {}
+spec:
+    ports: []
+`,
+			expected: `kind: Service
+metadata:
+    name: svc
+    annotations:
+        # comment
+        other: "value"
+        multiline: |
+          first
+          second
+        dapp/commit: abc
+        dapp/project: test
+    labels:
+        dapp/project: test
+spec:
+    ports: []
+`,
+		},
+		{
+			name: "pod template metadata is not changed",
+			doc: `kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    metadata:
+      labels:
+        app: app
+`,
+			expected: `kind: Deployment
+metadata:
+  name: app
+  annotations:
+    dapp/commit: abc
+    dapp/project: test
+  labels:
+    dapp/project: test
+spec:
+  template:
+    metadata:
+      labels:
+        app: app
+`,
+		},
+		{
+			name: "flow style metadata",
+			doc: `# Source: chart/templates/cm.yaml
+kind: ConfigMap
+metadata: {name: cm, labels: {app: cm}}
+`,
+			expected: `# Source: chart/templates/cm.yaml
+kind: ConfigMap
+metadata:
+  name: cm
+  labels:
+    app: cm
+    dapp/project: test
+  annotations:
+    dapp/commit: abc
+    dapp/project: test
+`,
+		},
+		{
+			name:     "not a resource",
+			doc:      "# Source: chart/templates/empty.yaml\nfoo: bar",
+			expected: "# Source: chart/templates/empty.yaml\nfoo: bar\n",
+		},
+	}
+
+	for _, e := range expectations {
+		got, err := injectDocGlobalMetadata(e.doc, annotations, labels)
+		if err != nil {
+			t.Errorf("\n[CASE]: %s\n[ERROR]: %s", e.name, err)
+			continue
+		}
+
+		if got != e.expected {
+			t.Errorf("\n[CASE]: %s\n[EXPECTED]:\n%s\n[GOT]:\n%s", e.name, e.expected, got)
+		}
+	}
+}
+
+func TestInjectGlobalMetadata(t *testing.T) {
+	manifest := `---
+# Source: chart/templates/a.yaml
+kind: ConfigMap
+metadata:
+  name: a
+---
+# Source: chart/templates/b.yaml
+kind: Secret
+metadata:
+  name: b
+`
+
+	expected := `---
+# Source: chart/templates/a.yaml
+kind: ConfigMap
+metadata:
+  name: a
+  labels:
+    dapp/project: test
+---
+# Source: chart/templates/b.yaml
+kind: Secret
+metadata:
+  name: b
+  labels:
+    dapp/project: test
+`
+
+	got, err := injectGlobalMetadata(manifest, nil, map[string]string{"dapp/project": "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != expected {
+		t.Errorf("\n[EXPECTED]:\n%s\n[GOT]:\n%s", expected, got)
+	}
+}
+
+func TestInjectDocGlobalMetadata_negative(t *testing.T) {
+	if _, err := injectDocGlobalMetadata("kind: ConfigMap\nmetadata: [\n", nil, nil); err == nil {
+		t.Errorf("\n[EXPECTED]: error\n[GOT]: no error")
+	}
+}
+
+func TestParseKeyValue(t *testing.T) {
+	name, value, err := parseKeyValue("team=a=b")
+	if err != nil || name != "team" || value != "a=b" {
+		t.Errorf("\n[EXPECTED]: team, a=b\n[GOT]: %s, %s, %v", name, value, err)
+	}
+
+	for _, s := range []string{"team", "=value"} {
+		if _, _, err := parseKeyValue(s); err == nil {
+			t.Errorf("\n[INPUT]: %q\n[EXPECTED]: error\n[GOT]: no error", s)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package deploy
 import (
 	"bufio"
 	"fmt"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -18,7 +19,6 @@ import (
 	"github.com/flant/dapp/pkg/logger"
 	"github.com/flant/kubedog/pkg/kube"
 	"github.com/flant/kubedog/pkg/tracker"
-	"github.com/flant/kubedog/pkg/trackers/rollout"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,11 +31,13 @@ const (
 )
 
 type CommonHelmOptions struct {
-	KubeContext string
+	KubeContext     string
+	KubeLock        bool
+	KubeLockTimeout time.Duration
 }
 
-func PurgeHelmRelease(releaseName string, opts CommonHelmOptions) error {
-	return withLockedHelmRelease(releaseName, func() error {
+func PurgeHelmRelease(releaseName, namespace string, opts CommonHelmOptions) error {
+	return withLockedHelmRelease(releaseName, namespace, opts, func() error {
 		return doPurgeHelmRelease(releaseName, opts)
 	})
 }
@@ -55,7 +57,7 @@ func doPurgeHelmRelease(releaseName string, opts CommonHelmOptions) error {
 		return fmt.Errorf("failed to check release status: %s\n%s\n%s", helmStatusErr, helmStatusStdout, helmStatusStderr)
 	}
 
-	fmt.Printf("# Purging helm release '%s'...\n", releaseName)
+	logger.LogF("# Purging helm release '%s'...\n", releaseName)
 	helmPurgeStdout, helmPurgeStderr, helmPurgeErr := HelmCmd(append([]string{"delete", "--purge", releaseName}, args...)...)
 	if helmPurgeErr != nil {
 		return fmt.Errorf("failed to purge release: %s\n%s\n%s", helmPurgeErr, helmPurgeStdout, helmPurgeStderr)
@@ -64,29 +66,75 @@ func doPurgeHelmRelease(releaseName string, opts CommonHelmOptions) error {
 	return nil
 }
 
+func GetHelmReleaseManifest(releaseName string, opts CommonHelmOptions) (string, error) {
+	return getHelmReleaseRevisionManifest(releaseName, 0, opts)
+}
+
+func getHelmReleaseRevisionManifest(releaseName string, revision int, opts CommonHelmOptions) (string, error) {
+	args := []string{"get", "manifest", releaseName}
+	if revision != 0 {
+		args = append(args, "--revision", strconv.Itoa(revision))
+	}
+	if opts.KubeContext != "" {
+		args = append(args, "--kube-context", opts.KubeContext)
+	}
+
+	stdout, stderr, err := HelmCmd(args...)
+	if err != nil {
+		if strings.HasSuffix(stderr, "not found") {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get release manifest: %s\n%s\n%s", err, stdout, stderr)
+	}
+
+	return stdout, nil
+}
+
 type HelmChartOptions struct {
-	Set       []string
-	SetString []string
-	Values    []string
-	DryRun    bool
-	Debug     bool
-	Timeout   time.Duration
+	Set          []string
+	SetString    []string
+	Values       []string
+	DryRun       bool
+	Debug        bool
+	Timeout      time.Duration
+	AutoRollback bool
 	CommonHelmOptions
 }
 
-func withLockedHelmRelease(releaseName string, f func() error) error {
-	lockName := fmt.Sprintf("helm_release.%s", releaseName)
-	return lock.WithLock(lockName, lock.LockOptions{}, f)
+// helmReleaseLockName returns name of the lock of the release in the cluster of the kube context.
+// Releases with the same name in different clusters are locked independently.
+// The lock stored in the cluster itself should be named without kube context.
+func helmReleaseLockName(releaseName, kubeContext string) string {
+	if kubeContext == "" {
+		return fmt.Sprintf("helm_release.%s", releaseName)
+	}
+
+	return fmt.Sprintf("helm_release.%s.%s", kubeContext, releaseName)
+}
+
+// withLockedHelmRelease serializes operations with the release on the host or between hosts sharing the lock backend.
+// If KubeLock is enabled operations are serialized by the lock in the release namespace instead:
+// the lock is shared by all hosts deploying into the cluster, so no other lock is taken.
+func withLockedHelmRelease(releaseName, namespace string, opts CommonHelmOptions, f func() error) error {
+	if opts.KubeLock {
+		return lock.WithKubernetesLock(helmReleaseLockName(releaseName, ""), lock.KubernetesLockOptions{
+			LockOptions: lock.LockOptions{Timeout: opts.KubeLockTimeout},
+			Namespace:   namespace,
+			Client:      kube.Kubernetes,
+		}, f)
+	}
+
+	return lock.WithLock(helmReleaseLockName(releaseName, opts.KubeContext), lock.LockOptions{}, f)
 }
 
 func DeployHelmChart(chartPath string, releaseName string, namespace string, opts HelmChartOptions) error {
-	return withLockedHelmRelease(releaseName, func() error {
+	return withLockedHelmRelease(releaseName, namespace, opts.CommonHelmOptions, func() error {
 		return doDeployHelmChart(chartPath, releaseName, namespace, opts)
 	})
 }
 
 func doDeployHelmChart(chartPath string, releaseName string, namespace string, opts HelmChartOptions) error {
-	releaseExist, err := isReleaseExist(releaseName)
+	releaseExist, err := isReleaseExist(releaseName, opts.CommonHelmOptions)
 	if err != nil {
 		return fmt.Errorf("checking release failed: %s", err)
 	}
@@ -96,6 +144,15 @@ func doDeployHelmChart(chartPath string, releaseName string, namespace string, o
 		return fmt.Errorf("parsing templates failed: %s", err)
 	}
 
+	for _, template := range *templates {
+		if _, err := getResourceTrackOptions(template, opts.Timeout); err != nil {
+			return err
+		}
+		if _, _, err := getResourceReadiness(template); err != nil {
+			return err
+		}
+	}
+
 	if err := removeOldJobs(templates, namespace); err != nil {
 		return fmt.Errorf("removing old jobs failed: %s", err)
 	}
@@ -110,10 +167,10 @@ func doDeployHelmChart(chartPath string, releaseName string, namespace string, o
 	args := commonHelmCommandArgs(namespace, opts)
 	if releaseExist {
 		args = append([]string{"upgrade", releaseName, chartPath}, args...)
-		fmt.Printf("# Upgrading helm release '%s'...\n", releaseName)
+		logger.LogF("# Upgrading helm release '%s'...\n", releaseName)
 	} else {
 		args = append([]string{"install", chartPath, "--name", releaseName}, args...)
-		fmt.Printf("# Installing helm release '%s'...\n", releaseName)
+		logger.LogF("# Installing helm release '%s'...\n", releaseName)
 	}
 
 	stdout, stderr, err := HelmCmd(args...)
@@ -123,109 +180,161 @@ func doDeployHelmChart(chartPath string, releaseName string, namespace string, o
 			logger.LogWarningF("WARNING: Helm release %s will be removed with `helm delete --purge` on the next run of `dapp kube deploy`", releaseName)
 		}
 
-		if err := createAutoPurgeTriggerFilePath(releaseName); err != nil {
+		if err := createAutoPurgeTriggerFilePath(releaseName, opts.KubeContext); err != nil {
 			return err
 		}
 
 		return fmt.Errorf("%s\n%s", stdout, stderr)
 	}
 
-	if err := deleteAutoPurgeTriggerFilePath(releaseName); err != nil {
+	if err := deleteAutoPurgeTriggerFilePath(releaseName, opts.KubeContext); err != nil {
 		return err
 	}
 
 	<-jobHooksWatcherDone
 
-	fmt.Printf("%s\n%s\n", stdout, stderr)
+	logger.LogF("%s\n%s\n", stdout, stderr)
 
-	if err := watchPods(templates, deployStartTime, namespace, opts); err != nil {
-		return err
-	}
-	if err := watchDeployments(templates, deployStartTime, namespace, opts); err != nil {
-		return err
-	}
-	if err := watchStatefulSets(templates, deployStartTime, namespace, opts); err != nil {
-		return err
-	}
-	if err := watchDaemonSets(templates, deployStartTime, namespace, opts); err != nil {
-		return err
-	}
-	if err := watchJobs(templates, deployStartTime, namespace, opts); err != nil {
+	if err := watchReleaseResources(templates, deployStartTime, namespace, opts); err != nil {
+		if opts.AutoRollback && !opts.DryRun {
+			return autoRollbackHelmRelease(releaseName, releaseExist, namespace, err, opts)
+		}
 		return err
 	}
 
 	return nil
 }
 
-func watchPods(templates *ChartTemplates, deployStartTime time.Time, namespace string, opts HelmChartOptions) error {
-	for _, template := range templates.Pods() {
-		fmt.Printf("# Run watch for pod '%s'\n", template.Metadata.Name)
+// autoRollbackHelmRelease reverts the release after failed tracking: upgraded release is rolled back
+// to the last successful revision, release installed for the first time is deleted.
+func autoRollbackHelmRelease(releaseName string, releaseExist bool, namespace string, trackErr error, opts HelmChartOptions) error {
+	logger.LogWarningF("WARNING: Helm release '%s' resources tracking failed: %s\n", releaseName, trackErr)
 
-		err := rollout.TrackPodTillReady(template.Metadata.Name, template.Namespace(namespace), kube.Kubernetes, tracker.Options{Timeout: time.Second * time.Duration(opts.Timeout), LogsFromTime: deployStartTime})
-		if err != nil {
+	if !releaseExist {
+		logger.LogF("# Auto rollback: deleting helm release '%s' installed for the first time...\n", releaseName)
+
+		if err := doPurgeHelmRelease(releaseName, opts.CommonHelmOptions); err != nil {
+			return fmt.Errorf("%s\nauto rollback failed: cannot delete release '%s': %s", trackErr, releaseName, err)
+		}
+
+		if err := deleteAutoPurgeTriggerFilePath(releaseName, opts.KubeContext); err != nil {
 			return err
 		}
+
+		return fmt.Errorf("%s\nauto rollback succeeded: release '%s' deleted", trackErr, releaseName)
 	}
 
-	return nil
-}
+	revisions, err := GetHelmReleaseHistory(releaseName, 0, opts.CommonHelmOptions)
+	if err != nil {
+		return fmt.Errorf("%s\nauto rollback failed: %s", trackErr, err)
+	}
 
-func watchDeployments(templates *ChartTemplates, deployStartTime time.Time, namespace string, opts HelmChartOptions) error {
-	for _, template := range templates.Deployments() {
-		fmt.Printf("# Run watch for deployment '%s'\n", template.Metadata.Name)
+	revision, err := lastSuccessfulRevision(revisions)
+	if err != nil {
+		return fmt.Errorf("%s\nauto rollback failed: %s", trackErr, err)
+	}
 
-		err := rollout.TrackDeploymentTillReady(template.Metadata.Name, template.Namespace(namespace), kube.Kubernetes, tracker.Options{Timeout: opts.Timeout, LogsFromTime: deployStartTime})
-		if err != nil {
-			return err
-		}
+	logger.LogF("# Auto rollback: rolling back helm release '%s' to the last successful revision %d...\n", releaseName, revision)
+
+	if err := doRollbackHelmRelease(releaseName, revision, namespace, opts); err != nil {
+		return fmt.Errorf("%s\nauto rollback to revision %d failed: %s", trackErr, revision, err)
 	}
 
-	return nil
+	return fmt.Errorf("%s\nauto rollback succeeded: release '%s' rolled back to revision %d", trackErr, releaseName, revision)
 }
 
-func watchStatefulSets(templates *ChartTemplates, deployStartTime time.Time, namespace string, opts HelmChartOptions) error {
-	for _, template := range templates.StatefulSets() {
-		fmt.Printf("# Run watch for statefulset '%s'\n", template.Metadata.Name)
+func RollbackHelmRelease(releaseName string, revision int, namespace string, opts HelmChartOptions) error {
+	return withLockedHelmRelease(releaseName, namespace, opts.CommonHelmOptions, func() error {
+		return doRollbackHelmRelease(releaseName, revision, namespace, opts)
+	})
+}
 
-		err := rollout.TrackStatefulSetTillReady(template.Metadata.Name, template.Namespace(namespace), kube.Kubernetes, tracker.Options{Timeout: time.Second * time.Duration(opts.Timeout), LogsFromTime: deployStartTime})
+func doRollbackHelmRelease(releaseName string, revision int, namespace string, opts HelmChartOptions) error {
+	if revision == 0 {
+		revisions, err := GetHelmReleaseHistory(releaseName, 0, opts.CommonHelmOptions)
 		if err != nil {
 			return err
 		}
-	}
-
-	return nil
-}
 
-func watchDaemonSets(templates *ChartTemplates, deployStartTime time.Time, namespace string, opts HelmChartOptions) error {
-	for _, template := range templates.DaemonSets() {
-		fmt.Printf("# Run watch for daemonset '%s'\n", template.Metadata.Name)
-
-		err := rollout.TrackDaemonSetTillReady(template.Metadata.Name, template.Namespace(namespace), kube.Kubernetes, tracker.Options{Timeout: time.Second * time.Duration(opts.Timeout), LogsFromTime: deployStartTime})
+		revision, err = lastSuccessfulRevision(revisions)
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	manifest, err := getHelmReleaseRevisionManifest(releaseName, revision, opts.CommonHelmOptions)
+	if err != nil {
+		return err
+	}
+	if manifest == "" {
+		return fmt.Errorf("Helm release '%s' doesn't exist", releaseName)
+	}
+
+	templates, err := parseManifestTemplates(manifest)
+	if err != nil {
+		return fmt.Errorf("parsing templates of revision %d failed: %s", revision, err)
+	}
+
+	rollbackStartTime := time.Now()
+
+	args := []string{"rollback", releaseName, strconv.Itoa(revision)}
+	if opts.KubeContext != "" {
+		args = append(args, "--kube-context", opts.KubeContext)
+	}
+	if opts.DryRun {
+		args = append(args, "--dry-run")
+	}
+	if opts.Timeout != 0 {
+		args = append(args, "--timeout", fmt.Sprintf("%v", opts.Timeout.Seconds()))
+	} else {
+		args = append(args, "--timeout", fmt.Sprintf("%v", DefaultHelmTimeout.Seconds()))
+	}
+
+	logger.LogF("# Rolling back helm release '%s' to revision %d...\n", releaseName, revision)
+
+	stdout, stderr, err := HelmCmd(args...)
+	if err != nil {
+		return fmt.Errorf("%s\n%s", stdout, stderr)
+	}
+
+	logger.LogF("%s\n%s\n", stdout, stderr)
+
+	if opts.DryRun {
+		return nil
+	}
+
+	return watchReleaseResources(templates, rollbackStartTime, namespace, opts)
 }
 
-func watchJobs(templates *ChartTemplates, deployStartTime time.Time, namespace string, opts HelmChartOptions) error {
-	for _, template := range templates.Jobs() {
-		// Ignore hooks here
-		if _, ok := template.Metadata.Annotations["helm.sh/hook"]; ok {
+// watchReleaseResources tracks all release resources concurrently until ready or the deadline.
+func watchReleaseResources(templates *ChartTemplates, deployStartTime time.Time, namespace string, opts HelmChartOptions) error {
+	var trackers []resourceTracker
+
+	for _, template := range *templates {
+		template := template
+		resourceNamespace := template.Namespace(namespace)
+
+		// Jobs hooks are watched by watchJobHooks
+		if _, ok := template.Metadata.Annotations["helm.sh/hook"]; ok && strings.ToLower(template.Kind) == "job" {
 			continue
 		}
 
-		fmt.Printf("# Run watch for job '%s'\n", template.Metadata.Name)
-
-		err := rollout.TrackJobTillDone(template.Metadata.Name, template.Namespace(namespace), kube.Kubernetes, tracker.Options{Timeout: time.Second * time.Duration(opts.Timeout), LogsFromTime: deployStartTime})
+		apiVersion, readiness, err := getResourceReadiness(template)
 		if err != nil {
 			return err
 		}
+		if readiness == nil {
+			continue
+		}
 
+		trackFunc := func(trackerOpts tracker.Options) error {
+			return trackResourceReadiness(template, apiVersion, resourceNamespace, readiness, trackerOpts)
+		}
+
+		trackers = append(trackers, resourceTracker{Template: template, TrackFunc: trackFunc})
 	}
 
-	return nil
+	return runResourceTrackers(trackers, namespace, deployStartTime, opts.Timeout)
 }
 
 func watchJobHooks(templates *ChartTemplates, releaseExist bool, deployStartTime time.Time, namespace string, opts HelmChartOptions) (chan bool, error) {
@@ -238,8 +347,6 @@ func watchJobHooks(templates *ChartTemplates, releaseExist bool, deployStartTime
 
 	go func() {
 		for _, template := range jobHooksToWatch {
-			fmt.Printf("# Run watch for job '%s'\n", template.Metadata.Name)
-
 			var jobNamespace string
 			if template.Metadata.Namespace != "" {
 				jobNamespace = template.Metadata.Namespace
@@ -247,9 +354,10 @@ func watchJobHooks(templates *ChartTemplates, releaseExist bool, deployStartTime
 				jobNamespace = namespace
 			}
 
-			err := rollout.TrackJobTillDone(template.Metadata.Name, jobNamespace, kube.Kubernetes, tracker.Options{Timeout: opts.Timeout, LogsFromTime: deployStartTime})
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "ERROR %s\n", err)
+			result := trackResource(template, namespace, deployStartTime, opts.Timeout, func(trackerOpts tracker.Options) error {
+				return trackResourceReadiness(template, template.Version, jobNamespace, jobReadiness, trackerOpts)
+			})
+			if result.Err != nil {
 				break
 			}
 		}
@@ -297,11 +405,16 @@ func commonHelmCommandArgs(namespace string, opts HelmChartOptions) []string {
 	return args
 }
 
-func isReleaseExist(releaseName string) (bool, error) {
+func isReleaseExist(releaseName string, opts CommonHelmOptions) (bool, error) {
 	var releaseStatus string
 	var releaseExist bool
 
-	helmStatusStdout, _, helmStatusErr := HelmCmd("status", releaseName)
+	var args []string
+	if opts.KubeContext != "" {
+		args = append(args, "--kube-context", opts.KubeContext)
+	}
+
+	helmStatusStdout, _, helmStatusErr := HelmCmd(append([]string{"status", releaseName}, args...)...)
 	if helmStatusErr == nil {
 		statusLinePrefix := "STATUS: "
 		scanner := bufio.NewScanner(strings.NewReader(helmStatusStdout))
@@ -316,24 +429,24 @@ func isReleaseExist(releaseName string) (bool, error) {
 
 	if helmStatusErr != nil {
 		releaseExist = false
-		if err := createAutoPurgeTriggerFilePath(releaseName); err != nil {
+		if err := createAutoPurgeTriggerFilePath(releaseName, opts.KubeContext); err != nil {
 			return false, err
 		}
 	} else if releaseStatus != "" && (releaseStatus == "FAILED" || releaseStatus == "PENDING_INSTALL") {
 		releaseExist = true
 
-		if exist, err := file.FileExists(autoPurgeTriggerFilePath(releaseName)); err != nil {
+		if exist, err := file.FileExists(autoPurgeTriggerFilePath(releaseName, opts.KubeContext)); err != nil {
 			return false, err
 		} else if exist {
-			fmt.Printf("# Delete release '%s'\n", releaseName)
-			if _, _, err := HelmCmd("delete", "--purge", releaseName); err != nil {
+			logger.LogF("# Delete release '%s'\n", releaseName)
+			if _, _, err := HelmCmd(append([]string{"delete", "--purge", releaseName}, args...)...); err != nil {
 				return false, err
 			}
 
 			releaseExist = false
 		}
 	} else {
-		if exist, err := file.FileExists(autoPurgeTriggerFilePath(releaseName)); err != nil {
+		if exist, err := file.FileExists(autoPurgeTriggerFilePath(releaseName, opts.KubeContext)); err != nil {
 			return false, err
 		} else if exist {
 			logger.LogWarningF("WARNING: Will not purge helm release '%s': expected FAILED or PENDING_INSTALL release status, got %s\n", releaseName, releaseStatus)
@@ -341,7 +454,7 @@ func isReleaseExist(releaseName string) (bool, error) {
 
 		releaseExist = true
 
-		if err := deleteAutoPurgeTriggerFilePath(releaseName); err != nil {
+		if err := deleteAutoPurgeTriggerFilePath(releaseName, opts.KubeContext); err != nil {
 			return false, err
 		}
 	}
@@ -404,8 +517,6 @@ func (t *Template) Namespace(namespace string) string {
 }
 
 func parseTemplates(chartPath, releaseName string, set, setString []string, values []string) (*ChartTemplates, error) {
-	var templates []*Template
-
 	args := []string{"template", chartPath, "--name", releaseName}
 	for _, s := range set {
 		args = append(args, "--set", s)
@@ -422,7 +533,13 @@ func parseTemplates(chartPath, releaseName string, set, setString []string, valu
 		return nil, fmt.Errorf(stderr)
 	}
 
-	for _, doc := range releaseutil.SplitManifests(stdout) {
+	return parseManifestTemplates(stdout)
+}
+
+func parseManifestTemplates(manifest string) (*ChartTemplates, error) {
+	var templates []*Template
+
+	for _, doc := range releaseutil.SplitManifests(manifest) {
 		var t Template
 		err := yaml.Unmarshal([]byte(doc), &t)
 		if err != nil {
@@ -466,7 +583,7 @@ func removeOldJobs(templates *ChartTemplates, namespace string) error {
 			continue
 		}
 
-		fmt.Printf("# Deleting hook job '%s' (dapp/recreate)...\n", template.Metadata.Name)
+		logger.LogF("# Deleting hook job '%s' (dapp/recreate)...\n", template.Metadata.Name)
 
 		deletePropagation := v1.DeletePropagationForeground
 		deleteOptions := &v1.DeleteOptions{
@@ -545,8 +662,8 @@ func jobHooksToWatch(templates *ChartTemplates, releaseExist bool) ([]*Template,
 	return jobHooksToWatch, nil
 }
 
-func createAutoPurgeTriggerFilePath(releaseName string) error {
-	filePath := autoPurgeTriggerFilePath(releaseName)
+func createAutoPurgeTriggerFilePath(releaseName, kubeContext string) error {
+	filePath := autoPurgeTriggerFilePath(releaseName, kubeContext)
 	dirPath := path.Dir(filePath)
 
 	if fileExist, err := file.FileExists(filePath); err != nil {
@@ -568,8 +685,8 @@ func createAutoPurgeTriggerFilePath(releaseName string) error {
 	return nil
 }
 
-func deleteAutoPurgeTriggerFilePath(releaseName string) error {
-	filePath := autoPurgeTriggerFilePath(releaseName)
+func deleteAutoPurgeTriggerFilePath(releaseName, kubeContext string) error {
+	filePath := autoPurgeTriggerFilePath(releaseName, kubeContext)
 	if fileExist, err := file.FileExists(filePath); err != nil {
 		return err
 	} else if fileExist {
@@ -581,6 +698,12 @@ func deleteAutoPurgeTriggerFilePath(releaseName string) error {
 	return nil
 }
 
-func autoPurgeTriggerFilePath(releaseName string) string {
-	return filepath.Join(dapp.GetHomeDir(), "helm", releaseName, "auto_purge_failed_release_on_next_deploy")
+// autoPurgeTriggerFilePath is keyed by kube context as helmReleaseLockName: releases with the same name in different clusters are independent.
+func autoPurgeTriggerFilePath(releaseName, kubeContext string) string {
+	dir := filepath.Join(dapp.GetHomeDir(), "helm", releaseName)
+	if kubeContext != "" {
+		dir = filepath.Join(dir, "kube_context", url.PathEscape(kubeContext))
+	}
+
+	return filepath.Join(dir, "auto_purge_failed_release_on_next_deploy")
 }
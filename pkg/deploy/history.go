@@ -0,0 +1,165 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/flant/dapp/pkg/logger"
+)
+
+const (
+	ReleaseStatusDeployed   = "DEPLOYED"
+	ReleaseStatusSuperseded = "SUPERSEDED"
+
+	revisionValueUnavailable = "<unavailable>"
+)
+
+type ReleaseRevision struct {
+	Revision    int    `json:"revision"`
+	Updated     string `json:"updated"`
+	Status      string `json:"status"`
+	Chart       string `json:"chart"`
+	Description string `json:"description"`
+
+	DockerTag string `json:"-"`
+	CiRef     string `json:"-"`
+}
+
+type HistoryOptions struct {
+	Max int
+	CommonHelmOptions
+}
+
+func RunHistory(releaseName string, opts HistoryOptions) error {
+	logger.LogDebugF("deploy", "History options: %#v\n", opts)
+
+	revisions, err := GetHelmReleaseHistory(releaseName, opts.Max, opts.CommonHelmOptions)
+	if err != nil {
+		return err
+	}
+
+	for _, revision := range revisions {
+		values, err := GetHelmReleaseValues(releaseName, revision.Revision, opts.CommonHelmOptions)
+		if err != nil {
+			logger.LogWarningF("WARNING: values of revision %d are unavailable: %s\n", revision.Revision, err)
+			revision.DockerTag = revisionValueUnavailable
+			revision.CiRef = revisionValueUnavailable
+			continue
+		}
+
+		revision.DockerTag = valueByPath(values, "global.dapp.docker_tag")
+		revision.CiRef = valueByPath(values, "global.dapp.ci.ref")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "REVISION\tUPDATED\tSTATUS\tCHART\tDOCKER TAG\tCI REF\tDESCRIPTION\n")
+	for _, revision := range revisions {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", revision.Revision, revision.Updated, revision.Status, revision.Chart, revision.DockerTag, revision.CiRef, revision.Description)
+	}
+
+	return w.Flush()
+}
+
+func GetHelmReleaseHistory(releaseName string, max int, opts CommonHelmOptions) ([]*ReleaseRevision, error) {
+	args := []string{"history", releaseName, "--output", "json"}
+	if max > 0 {
+		args = append(args, "--max", strconv.Itoa(max))
+	}
+	if opts.KubeContext != "" {
+		args = append(args, "--kube-context", opts.KubeContext)
+	}
+
+	stdout, stderr, err := HelmCmd(args...)
+	if err != nil {
+		if strings.HasSuffix(strings.TrimSpace(stderr), "not found") {
+			return nil, fmt.Errorf("Helm release '%s' doesn't exist", releaseName)
+		}
+		return nil, fmt.Errorf("failed to get release history: %s\n%s\n%s", err, stdout, stderr)
+	}
+
+	return parseHelmHistory(stdout)
+}
+
+// parseHelmHistory parses json output of helm history (helm >= 2.10):
+// [{"revision":1,"updated":"Mon Oct 15 12:00:00 2018","status":"SUPERSEDED","chart":"app-0.1.0","description":"Install complete"}]
+func parseHelmHistory(output string) ([]*ReleaseRevision, error) {
+	var revisions []*ReleaseRevision
+	if err := json.Unmarshal([]byte(output), &revisions); err != nil {
+		return nil, fmt.Errorf("unexpected helm history output: %s\n%s", err, output)
+	}
+
+	return revisions, nil
+}
+
+func GetHelmReleaseValues(releaseName string, revision int, opts CommonHelmOptions) (map[interface{}]interface{}, error) {
+	args := []string{"get", "values", releaseName, "--revision", strconv.Itoa(revision)}
+	if opts.KubeContext != "" {
+		args = append(args, "--kube-context", opts.KubeContext)
+	}
+
+	stdout, stderr, err := HelmCmd(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release values of revision %d: %s\n%s\n%s", revision, err, stdout, stderr)
+	}
+
+	values := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal([]byte(stdout), &values); err != nil {
+		return nil, fmt.Errorf("cannot parse release values of revision %d: %s", revision, err)
+	}
+
+	return values, nil
+}
+
+// lastSuccessfulRevision returns the newest revision before the current one that was deployed successfully.
+func lastSuccessfulRevision(revisions []*ReleaseRevision) (int, error) {
+	var current int
+	for _, revision := range revisions {
+		if revision.Revision > current {
+			current = revision.Revision
+		}
+	}
+
+	var res int
+	for _, revision := range revisions {
+		if revision.Revision == current || revision.Revision < res {
+			continue
+		}
+
+		if revision.Status == ReleaseStatusSuperseded || revision.Status == ReleaseStatusDeployed {
+			res = revision.Revision
+		}
+	}
+
+	if res == 0 {
+		return 0, fmt.Errorf("no successful revision to rollback to")
+	}
+
+	return res, nil
+}
+
+func valueByPath(values map[interface{}]interface{}, path string) string {
+	var value interface{} = values
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return ""
+		}
+
+		value, ok = m[key]
+		if !ok {
+			return ""
+		}
+	}
+
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", value)
+}
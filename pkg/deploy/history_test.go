@@ -0,0 +1,50 @@
+package deploy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHelmHistory(t *testing.T) {
+	output := `[{"revision":1,"updated":"Mon Oct 15 12:00:00 2018","status":"SUPERSEDED","chart":"app-0.1.0","description":"Install complete"},{"revision":2,"updated":"Tue Oct 16 12:00:00 2018","status":"DEPLOYED","chart":"app-0.1.0","appVersion":"1.0","description":"Upgrade complete"}]`
+
+	revisions, err := parseHelmHistory(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*ReleaseRevision{
+		{Revision: 1, Updated: "Mon Oct 15 12:00:00 2018", Status: ReleaseStatusSuperseded, Chart: "app-0.1.0", Description: "Install complete"},
+		{Revision: 2, Updated: "Tue Oct 16 12:00:00 2018", Status: ReleaseStatusDeployed, Chart: "app-0.1.0", Description: "Upgrade complete"},
+	}
+
+	if !reflect.DeepEqual(revisions, expected) {
+		t.Errorf("\n[EXPECTED]: %+v %+v\n[GOT]: %+v", expected[0], expected[1], revisions)
+	}
+
+	if _, err := parseHelmHistory("REVISION  UPDATED  STATUS  CHART  DESCRIPTION"); err == nil {
+		t.Errorf("\n[EXPECTED]: error\n[GOT]: no error")
+	}
+}
+
+func TestLastSuccessfulRevision(t *testing.T) {
+	revisions := []*ReleaseRevision{
+		{Revision: 1, Status: ReleaseStatusSuperseded},
+		{Revision: 2, Status: ReleaseStatusSuperseded},
+		{Revision: 3, Status: "FAILED"},
+		{Revision: 4, Status: ReleaseStatusDeployed},
+	}
+
+	revision, err := lastSuccessfulRevision(revisions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if revision != 2 {
+		t.Errorf("\n[EXPECTED]: 2\n[GOT]: %d", revision)
+	}
+
+	if _, err := lastSuccessfulRevision([]*ReleaseRevision{{Revision: 1, Status: ReleaseStatusDeployed}}); err == nil {
+		t.Errorf("\n[EXPECTED]: error\n[GOT]: no error")
+	}
+}
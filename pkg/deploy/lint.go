@@ -5,21 +5,34 @@ import (
 	"os"
 
 	"github.com/flant/dapp/pkg/config"
+	"github.com/flant/dapp/pkg/logger"
 )
 
+const DefaultLintKubeVersion = "1.13"
+
 type LintOptions struct {
 	Values       []string
 	SecretValues []string
 	Set          []string
 	SetString    []string
+	KubeVersion  string
+	CrdsDirs     []string
+
+	SecretProviders bool
+
+	// Component chart .helm/COMPONENT is linted instead of the project chart
+	Component string
 }
 
 func RunLint(projectName, projectDir string, dappfile []*config.Dimg, opts LintOptions) error {
-	if debug() {
-		fmt.Printf("Lint options: %#v\n", opts)
+	logger.LogDebugF("deploy", "Lint options: %#v\n", opts)
+
+	_, projectHelmDir, dappfile, err := getComponentChart(projectDir, opts.Component, dappfile)
+	if err != nil {
+		return err
 	}
 
-	m, err := getSafeSecretManager(projectDir, opts.SecretValues)
+	m, err := getSafeSecretManager(projectDir, []string{projectHelmDir}, opts.SecretValues)
 	if err != nil {
 		return fmt.Errorf("cannot get project secret: %s", err)
 	}
@@ -39,14 +52,31 @@ func RunLint(projectName, projectDir string, dappfile []*config.Dimg, opts LintO
 		return fmt.Errorf("error creating service values: %s", err)
 	}
 
-	dappChart, err := getDappChart(projectDir, m, opts.Values, opts.SecretValues, opts.Set, opts.SetString, serviceValues)
+	dappChart, err := getDappChartFromDir(projectHelmDir, projectDir, m, opts.SecretProviders, opts.Values, opts.SecretValues, opts.Set, opts.SetString, serviceValues)
 	if err != nil {
 		return err
 	}
-	if !debug() {
+	if !logger.IsDebug("deploy") {
 		// Do not remove tmp chart in debug
 		defer os.RemoveAll(dappChart.ChartDir)
 	}
 
-	return dappChart.Lint()
+	if err := dappChart.Lint(); err != nil {
+		return err
+	}
+
+	manifest, err := dappChart.Render(namespace)
+	if err != nil {
+		return fmt.Errorf("cannot render chart: %s", err)
+	}
+
+	schemasErr := validateManifestSchemas(dappChart, manifest, opts.KubeVersion, opts.CrdsDirs)
+	// Built-in rules are recommendations for existing charts, image-repo rule is skipped for the placeholder repo
+	policiesErr := CheckPolicies(manifest, projectDir, PolicyOptions{BuiltinSeverity: PolicySeverityWarning})
+
+	if schemasErr != nil {
+		return schemasErr
+	}
+
+	return policiesErr
 }
@@ -0,0 +1,181 @@
+package deploy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/flant/dapp/pkg/deploy/schema"
+	"github.com/flant/dapp/pkg/logger"
+)
+
+var (
+	manifestSourceRegexp = regexp.MustCompile(`(?m)^# Source: (.+)$`)
+	templateDocSeparator = regexp.MustCompile(`^---`)
+	templateActionLine   = regexp.MustCompile(`^\s*{{.*}}\s*$`)
+)
+
+type manifestDoc struct {
+	Source string
+	// Index of the document among documents rendered from the same source template
+	SourceIndex int
+	Object      map[string]interface{}
+}
+
+// validateManifestSchemas validates every rendered resource against kubernetes and CRD schemas.
+// Errors are printed with template file and line guessed by the path of the invalid field.
+func validateManifestSchemas(chart *DappChart, manifest string, kubeVersion string, crdsDirs []string) error {
+	validator, err := schema.NewValidator(kubeVersion)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range crdsDirs {
+		if err := validator.AddCRDsDir(dir); err != nil {
+			return fmt.Errorf("cannot load CRD schemas: %s", err)
+		}
+	}
+
+	var docs []*manifestDoc
+	sourceDocs := make(map[string]int)
+
+	for _, data := range splitManifestDocs(manifest) {
+		doc := &manifestDoc{}
+		if match := manifestSourceRegexp.FindStringSubmatch(data); match != nil {
+			doc.Source = strings.TrimSpace(match[1])
+			doc.SourceIndex = sourceDocs[doc.Source]
+			sourceDocs[doc.Source]++
+		}
+
+		obj, err := schema.DecodeObject([]byte(data))
+		if err != nil {
+			return fmt.Errorf("%s: bad yaml: %s", doc.Source, err)
+		}
+		if obj == nil {
+			continue
+		}
+		doc.Object = obj
+
+		// Chart CRDs are used to validate chart custom resources
+		if obj["kind"] == "CustomResourceDefinition" {
+			if err := validator.AddCRD(obj); err != nil {
+				return fmt.Errorf("%s: %s", doc.Source, err)
+			}
+		}
+
+		docs = append(docs, doc)
+	}
+
+	var errorsCount int
+	for _, doc := range docs {
+		apiVersion, _ := doc.Object["apiVersion"].(string)
+		kind, _ := doc.Object["kind"].(string)
+
+		var name string
+		if metadata, ok := doc.Object["metadata"].(map[string]interface{}); ok {
+			name, _ = metadata["name"].(string)
+		}
+		resource := fmt.Sprintf("%s/%s", strings.ToLower(kind), name)
+
+		if !validator.HasSchema(apiVersion, kind) {
+			logger.LogF("%s: %s: no schema for %s %s, skip validation\n", doc.Source, resource, apiVersion, kind)
+			continue
+		}
+
+		validationErrors := validator.Validate(doc.Object)
+		if len(validationErrors) == 0 {
+			continue
+		}
+
+		templatePath, templateLines := chart.manifestSourceTemplate(doc.Source)
+		for _, validationErr := range validationErrors {
+			location := templatePath
+			if line := findTemplateFieldLine(templateLines, doc.SourceIndex, validationErr.Path); line != 0 {
+				location = fmt.Sprintf("%s:%d", templatePath, line)
+			}
+
+			logger.LogErrorF("%s: %s: %s\n", location, resource, validationErr)
+			errorsCount++
+		}
+	}
+
+	if errorsCount > 0 {
+		return fmt.Errorf("%d schema validation errors found in rendered manifests (kubernetes %s)", errorsCount, kubeVersion)
+	}
+
+	logger.LogF("Rendered manifests are valid (kubernetes %s)\n", kubeVersion)
+
+	return nil
+}
+
+// manifestSourceTemplate returns project path and lines of the template from helm '# Source: CHART/PATH' comment.
+func (chart *DappChart) manifestSourceTemplate(source string) (string, []string) {
+	parts := strings.SplitN(source, "/", 2)
+	if len(parts) != 2 {
+		return source, nil
+	}
+
+	templatePath := filepath.Join(".helm", parts[1])
+
+	data, err := ioutil.ReadFile(filepath.Join(chart.ChartDir, parts[1]))
+	if err != nil {
+		return templatePath, nil
+	}
+
+	return templatePath, strings.Split(string(data), "\n")
+}
+
+// findTemplateFieldLine searches the fields of path one after another in the docIndex document of the template.
+// The line of the deepest found field is returned, zero means nothing found.
+func findTemplateFieldLine(lines []string, docIndex int, path []interface{}) int {
+	var docStarts []int
+	docStart, docHasContent := 0, false
+	for ind, line := range lines {
+		if templateDocSeparator.MatchString(line) {
+			if docHasContent {
+				docStarts = append(docStarts, docStart)
+			}
+			docStart, docHasContent = ind+1, false
+		} else if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "#") && !templateActionLine.MatchString(line) {
+			docHasContent = true
+		}
+	}
+	if docHasContent {
+		docStarts = append(docStarts, docStart)
+	}
+
+	start := 0
+	if docIndex < len(docStarts) {
+		start = docStarts[docIndex]
+	}
+
+	var line int
+	for _, elm := range path {
+		field, ok := elm.(string)
+		if !ok {
+			continue
+		}
+
+		fieldRegexp := regexp.MustCompile(fmt.Sprintf(`^\s*(-\s+)?["']?%s["']?\s*:`, regexp.QuoteMeta(field)))
+
+		found := false
+		for ind := start; ind < len(lines); ind++ {
+			if ind > start && templateDocSeparator.MatchString(lines[ind]) {
+				break
+			}
+
+			if fieldRegexp.MatchString(lines[ind]) {
+				line, start, found = ind+1, ind+1, true
+				break
+			}
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	return line
+}
@@ -0,0 +1,79 @@
+package deploy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/dapp/pkg/logger"
+	"github.com/flant/kubedog/pkg/kube"
+)
+
+// RunLockStatus shows holders of the release lock in the release namespace (--kube-lock)
+// and of the release lock in the lock backend shared between hosts if the backend is set up.
+func RunLockStatus(releaseName, namespace, kubeContext string) error {
+	holders, err := lock.GetKubernetesLockInfo(helmReleaseLockName(releaseName, ""), namespace, kube.Kubernetes)
+	if err != nil {
+		return err
+	}
+	printLockHolders(fmt.Sprintf("Helm release '%s' kubernetes lock in namespace '%s'", releaseName, namespace), holders)
+
+	if store := lock.GetBackendStore(); store != nil {
+		holders, err := lock.GetLockHolders(helmReleaseLockName(releaseName, kubeContext), store)
+		if err != nil {
+			return err
+		}
+		printLockHolders(fmt.Sprintf("Helm release '%s' lock of the lock backend", releaseName), holders)
+	}
+
+	return nil
+}
+
+func printLockHolders(title string, holders []*lock.LockInfo) {
+	if len(holders) == 0 {
+		logger.LogF("%s is not held\n", title)
+		return
+	}
+
+	logger.LogF("%s is held\n", title)
+	for _, info := range holders {
+		logger.LogF("\nHolder: %s\n", info.Holder)
+		if info.CiRunner != "" {
+			logger.LogF("CI runner: %s\n", info.CiRunner)
+		}
+		if info.CiPipeline != "" {
+			logger.LogF("CI pipeline: %s\n", info.CiPipeline)
+		}
+		logger.LogF("Acquired at: %s\n", info.AcquiredAt.Format(time.RFC3339))
+		logger.LogF("Renewed at: %s\n", info.RenewedAt.Format(time.RFC3339))
+
+		if info.IsExpired() {
+			logger.LogF("Lock is stale: not renewed during %s, it will be taken over by the next deploy\n", info.TTL)
+		}
+	}
+	logger.LogF("\n")
+}
+
+// RunLockRelease removes the release lock in the release namespace and in the lock backend if the backend is set up.
+func RunLockRelease(releaseName, namespace, kubeContext string) error {
+	released, err := lock.ReleaseKubernetesLock(helmReleaseLockName(releaseName, ""), namespace, kube.Kubernetes)
+	if err != nil {
+		return err
+	}
+
+	if store := lock.GetBackendStore(); store != nil {
+		backendReleased, err := lock.ReleaseLock(helmReleaseLockName(releaseName, kubeContext), store)
+		if err != nil {
+			return err
+		}
+		released = released || backendReleased
+	}
+
+	if !released {
+		return fmt.Errorf("helm release '%s' is not locked", releaseName)
+	}
+
+	logger.LogF("Helm release '%s' lock released\n", releaseName)
+
+	return nil
+}
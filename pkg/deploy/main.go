@@ -69,7 +69,3 @@ func HelmCmd(args ...string) (stdout string, stderr string, err error) {
 
 	return
 }
-
-func debug() bool {
-	return os.Getenv("DAPP_DEPLOY_DEBUG") == "1"
-}
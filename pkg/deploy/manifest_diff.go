@@ -0,0 +1,320 @@
+package deploy
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v2"
+	"k8s.io/helm/pkg/releaseutil"
+)
+
+const (
+	manifestDiffContextLines = 3
+
+	ResourceDiffAdded   = "added"
+	ResourceDiffRemoved = "removed"
+	ResourceDiffChanged = "changed"
+)
+
+type ResourceDiff struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Status    string
+	Lines     []DiffLine
+}
+
+func (d *ResourceDiff) ResourceId() string {
+	return fmt.Sprintf("%s/%s/%s", d.Namespace, d.Kind, d.Name)
+}
+
+type DiffLine struct {
+	Op   byte
+	Text string
+}
+
+type manifestResource struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Data      string
+}
+
+// DiffManifests compares current and desired multi-document manifests resource by resource.
+// Secret data is masked in both manifests, only checksums keyed with the random key of the diff are shown:
+// changed values have different checksums, but checksums cannot be matched to values or compared between diffs.
+func DiffManifests(currentManifest, desiredManifest, namespace string) ([]*ResourceDiff, error) {
+	maskKey := make([]byte, sha256.Size)
+	if _, err := rand.Read(maskKey); err != nil {
+		return nil, fmt.Errorf("cannot generate secret data mask key: %s", err)
+	}
+
+	currentResources, err := parseManifestResources(currentManifest, namespace, maskKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse current manifests: %s", err)
+	}
+
+	desiredResources, err := parseManifestResources(desiredManifest, namespace, maskKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse desired manifests: %s", err)
+	}
+
+	var ids []string
+	for id := range currentResources {
+		ids = append(ids, id)
+	}
+	for id := range desiredResources {
+		if _, ok := currentResources[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var res []*ResourceDiff
+	for _, id := range ids {
+		current, hasCurrent := currentResources[id]
+		desired, hasDesired := desiredResources[id]
+
+		var resource *manifestResource
+		var status, currentData, desiredData string
+		switch {
+		case hasCurrent && !hasDesired:
+			resource, status, currentData = current, ResourceDiffRemoved, current.Data
+		case !hasCurrent && hasDesired:
+			resource, status, desiredData = desired, ResourceDiffAdded, desired.Data
+		default:
+			if current.Data == desired.Data {
+				continue
+			}
+			resource, status, currentData, desiredData = desired, ResourceDiffChanged, current.Data, desired.Data
+		}
+
+		res = append(res, &ResourceDiff{
+			Kind:      resource.Kind,
+			Namespace: resource.Namespace,
+			Name:      resource.Name,
+			Status:    status,
+			Lines:     diffLines(splitLines(currentData), splitLines(desiredData)),
+		})
+	}
+
+	return res, nil
+}
+
+func PrintManifestsDiff(w io.Writer, diffs []*ResourceDiff) {
+	if len(diffs) == 0 {
+		fmt.Fprintf(w, "# No changes\n")
+		return
+	}
+
+	for _, d := range diffs {
+		fmt.Fprintf(w, "%s\n", color.New(color.Bold).Sprintf("# %s %s (%s)", d.Kind, d.Name, d.Status))
+		fmt.Fprintf(w, "%s\n", color.New(color.Bold).Sprintf("--- %s (current)", d.ResourceId()))
+		fmt.Fprintf(w, "%s\n", color.New(color.Bold).Sprintf("+++ %s (desired)", d.ResourceId()))
+
+		for _, hunk := range unifiedHunks(d.Lines, manifestDiffContextLines) {
+			fmt.Fprintf(w, "%s\n", color.New(color.FgCyan).Sprint(hunk.header))
+			for _, line := range hunk.lines {
+				text := fmt.Sprintf("%c%s", line.Op, line.Text)
+				switch line.Op {
+				case '+':
+					text = color.New(color.FgGreen).Sprint(text)
+				case '-':
+					text = color.New(color.FgRed).Sprint(text)
+				}
+				fmt.Fprintf(w, "%s\n", text)
+			}
+		}
+
+		fmt.Fprintln(w)
+	}
+}
+
+func parseManifestResources(manifest, namespace string, maskKey []byte) (map[string]*manifestResource, error) {
+	res := make(map[string]*manifestResource)
+
+	for _, doc := range releaseutil.SplitManifests(manifest) {
+		var t Template
+		if err := yaml.Unmarshal([]byte(doc), &t); err != nil {
+			return nil, err
+		}
+
+		if t.Metadata == nil || t.Metadata.Name == "" {
+			continue
+		}
+
+		var obj map[interface{}]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, err
+		}
+
+		if t.Kind == "Secret" {
+			maskSecretData(obj, maskKey)
+		}
+
+		// Deploy time is changed on each deploy and is not a difference of the release
+		if metadata, ok := obj["metadata"].(map[interface{}]interface{}); ok {
+			if annotations, ok := metadata["annotations"].(map[interface{}]interface{}); ok {
+				delete(annotations, DeployedAtAnnotation)
+			}
+		}
+
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		resource := &manifestResource{
+			Kind:      t.Kind,
+			Namespace: t.Namespace(namespace),
+			Name:      t.Metadata.Name,
+			Data:      string(data),
+		}
+		res[fmt.Sprintf("%s/%s/%s", resource.Namespace, resource.Kind, resource.Name)] = resource
+	}
+
+	return res, nil
+}
+
+func maskSecretData(obj map[interface{}]interface{}, key []byte) {
+	for _, field := range []string{"data", "stringData"} {
+		values, ok := obj[field].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		for k, v := range values {
+			mac := hmac.New(sha256.New, key)
+			mac.Write([]byte(fmt.Sprintf("%v", v)))
+			values[k] = fmt.Sprintf("*** (checksum %s)", hex.EncodeToString(mac.Sum(nil))[:12])
+		}
+	}
+}
+
+func splitLines(data string) []string {
+	if data == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(data, "\n"), "\n")
+}
+
+// diffLines builds a line diff using the longest common subsequence of a and b.
+func diffLines(a, b []string) []DiffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var res []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			res = append(res, DiffLine{Op: ' ', Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			res = append(res, DiffLine{Op: '-', Text: a[i]})
+			i++
+		default:
+			res = append(res, DiffLine{Op: '+', Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		res = append(res, DiffLine{Op: '-', Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		res = append(res, DiffLine{Op: '+', Text: b[j]})
+	}
+
+	return res
+}
+
+type diffHunk struct {
+	header string
+	lines  []DiffLine
+}
+
+func unifiedHunks(lines []DiffLine, contextLines int) []diffHunk {
+	var hunks []diffHunk
+
+	for start := 0; start < len(lines); {
+		if lines[start].Op == ' ' {
+			start++
+			continue
+		}
+
+		hunkStart := start - contextLines
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+
+		lastChange := start
+		for ind := start + 1; ind < len(lines); ind++ {
+			if lines[ind].Op == ' ' {
+				continue
+			}
+
+			// Hunks are joined if context lines of both changes cover all unchanged lines between them
+			if ind-lastChange-1 > 2*contextLines {
+				break
+			}
+			lastChange = ind
+		}
+
+		hunkEnd := lastChange + 1 + contextLines
+		if hunkEnd > len(lines) {
+			hunkEnd = len(lines)
+		}
+
+		aStart, bStart := 1, 1
+		for _, line := range lines[:hunkStart] {
+			if line.Op != '+' {
+				aStart++
+			}
+			if line.Op != '-' {
+				bStart++
+			}
+		}
+
+		aCount, bCount := 0, 0
+		for _, line := range lines[hunkStart:hunkEnd] {
+			if line.Op != '+' {
+				aCount++
+			}
+			if line.Op != '-' {
+				bCount++
+			}
+		}
+
+		hunks = append(hunks, diffHunk{
+			header: fmt.Sprintf("@@ -%d,%d +%d,%d @@", aStart, aCount, bStart, bCount),
+			lines:  lines[hunkStart:hunkEnd],
+		})
+
+		start = hunkEnd
+	}
+
+	return hunks
+}
@@ -0,0 +1,182 @@
+package deploy
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func formatDiffLines(lines []DiffLine) []string {
+	var res []string
+	for _, line := range lines {
+		res = append(res, fmt.Sprintf("%c%s", line.Op, line.Text))
+	}
+	return res
+}
+
+func TestDiffLines(t *testing.T) {
+	for _, e := range []struct {
+		a, b     []string
+		expected []string
+	}{
+		{nil, nil, nil},
+		{[]string{"a", "b"}, nil, []string{"-a", "-b"}},
+		{nil, []string{"a", "b"}, []string{"+a", "+b"}},
+		{[]string{"a", "b", "c"}, []string{"a", "b", "c"}, []string{" a", " b", " c"}},
+		{[]string{"a", "b", "c"}, []string{"a", "x", "c"}, []string{" a", "-b", "+x", " c"}},
+		{[]string{"a", "b", "c", "d"}, []string{"b", "c", "e"}, []string{"-a", " b", " c", "-d", "+e"}},
+	} {
+		got := formatDiffLines(diffLines(e.a, e.b))
+		if !reflect.DeepEqual(got, e.expected) {
+			t.Errorf("\n[A]: %v\n[B]: %v\n[EXPECTED]: %v\n[GOT]: %v", e.a, e.b, e.expected, got)
+		}
+	}
+}
+
+func TestUnifiedHunks(t *testing.T) {
+	var a, b []string
+	for i := 1; i <= 20; i++ {
+		a = append(a, fmt.Sprintf("line%d", i))
+		switch i {
+		case 2:
+			b = append(b, "changed2")
+		case 15:
+			b = append(b, "line15", "added15")
+		default:
+			b = append(b, fmt.Sprintf("line%d", i))
+		}
+	}
+
+	hunks := unifiedHunks(diffLines(a, b), 3)
+
+	var headers []string
+	for _, hunk := range hunks {
+		headers = append(headers, hunk.header)
+	}
+
+	expected := []string{"@@ -1,5 +1,5 @@", "@@ -13,6 +13,7 @@"}
+	if !reflect.DeepEqual(headers, expected) {
+		t.Fatalf("\n[EXPECTED]: %v\n[GOT]: %v", expected, headers)
+	}
+
+	expectedLines := []string{" line1", "-line2", "+changed2", " line3", " line4", " line5"}
+	if got := formatDiffLines(hunks[0].lines); !reflect.DeepEqual(got, expectedLines) {
+		t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v", expectedLines, got)
+	}
+
+	// Changes separated by two contexts of unchanged lines are joined into one hunk
+	hunks = unifiedHunks(diffLines([]string{"a", "b", "c", "d", "e", "f"}, []string{"x", "b", "c", "d", "e", "y"}), 2)
+	if len(hunks) != 1 || hunks[0].header != "@@ -1,6 +1,6 @@" {
+		t.Errorf("\n[EXPECTED]: single hunk @@ -1,6 +1,6 @@\n[GOT]: %+v", hunks)
+	}
+
+	if hunks := unifiedHunks(diffLines([]string{"a"}, []string{"a"}), 3); len(hunks) != 0 {
+		t.Errorf("\n[EXPECTED]: no hunks\n[GOT]: %+v", hunks)
+	}
+}
+
+func TestDiffManifests(t *testing.T) {
+	current := `---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+data:
+  password: cGFzc3dvcmQ=
+  user: dXNlcg==
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: removed
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unchanged
+  annotations:
+    dapp.io/deployed-at: "1"
+`
+
+	desired := `---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+data:
+  password: bmV3LXBhc3N3b3Jk
+  user: dXNlcg==
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unchanged
+  annotations:
+    dapp.io/deployed-at: "2"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: added
+`
+	current = strings.Replace(current, "dapp.io/deployed-at", DeployedAtAnnotation, -1)
+	desired = strings.Replace(desired, "dapp.io/deployed-at", DeployedAtAnnotation, -1)
+
+	diffs, err := DiffManifests(current, desired, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, d := range diffs {
+		got = append(got, fmt.Sprintf("%s %s", d.ResourceId(), d.Status))
+	}
+
+	expected := []string{
+		"default/ConfigMap/added added",
+		"default/ConfigMap/removed removed",
+		"default/Secret/db changed",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("\n[EXPECTED]: %v\n[GOT]: %v", expected, got)
+	}
+
+	var changed []string
+	for _, line := range diffs[2].Lines {
+		text := fmt.Sprintf("%c%s", line.Op, line.Text)
+		for _, value := range []string{"cGFzc3dvcmQ=", "bmV3LXBhc3N3b3Jk", "dXNlcg=="} {
+			if strings.Contains(text, value) {
+				t.Errorf("\n[EXPECTED]: masked secret data\n[GOT]: %s", text)
+			}
+		}
+
+		if line.Op != ' ' {
+			changed = append(changed, strings.TrimSpace(strings.SplitN(line.Text, ":", 2)[0]))
+		}
+	}
+
+	if !reflect.DeepEqual(changed, []string{"password", "password"}) {
+		t.Errorf("\n[EXPECTED]: only password is changed\n[GOT]: %v", changed)
+	}
+}
+
+func TestMaskSecretData(t *testing.T) {
+	checksum := func(key []byte) interface{} {
+		obj := map[interface{}]interface{}{"data": map[interface{}]interface{}{"password": "cGFzc3dvcmQ="}}
+		maskSecretData(obj, key)
+		return obj["data"].(map[interface{}]interface{})["password"]
+	}
+
+	key := make([]byte, sha256.Size)
+	otherKey := append([]byte{1}, key[1:]...)
+
+	if checksum(key) != checksum(key) {
+		t.Errorf("\n[EXPECTED]: equal checksums of the value with the same key")
+	}
+
+	if checksum(key) == checksum(otherKey) {
+		t.Errorf("\n[EXPECTED]: different checksums of the value with different keys\n[GOT]: %v", checksum(key))
+	}
+}
@@ -0,0 +1,478 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/flant/dapp/pkg/logger"
+)
+
+const (
+	ProjectPoliciesDir = ".helm/policies"
+
+	SkipPoliciesAnnotation = "dapp/skip-policies"
+
+	PolicySeverityError   = "error"
+	PolicySeverityWarning = "warning"
+)
+
+// PolicyRule checks values selected by Path in every resource of the listed Kinds (all kinds by default).
+//
+// Path consists of fields separated by dots, [*] and * select all array items and all object values,
+// [N] selects array item. Path can start with $podSpec (pod spec of any workload kind)
+// or $containers (all containers and init containers of the pod spec).
+//
+// Exactly one check is specified for the rule: Exists checks field presence,
+// other checks are done only for existing fields.
+type PolicyRule struct {
+	Name     string   `json:"name"`
+	Message  string   `json:"message"`
+	Kinds    []string `json:"kinds,omitempty"`
+	Severity string   `json:"severity,omitempty"`
+	Path     string   `json:"path"`
+
+	Exists     *bool         `json:"exists,omitempty"`
+	Equals     interface{}   `json:"equals,omitempty"`
+	NotEquals  interface{}   `json:"notEquals,omitempty"`
+	Matches    string        `json:"matches,omitempty"`
+	NotMatches string        `json:"notMatches,omitempty"`
+	OneOf      []interface{} `json:"oneOf,omitempty"`
+
+	matchesRegexp    *regexp.Regexp
+	notMatchesRegexp *regexp.Regexp
+}
+
+type PoliciesConfig struct {
+	Rules         []*PolicyRule     `json:"rules"`
+	DisabledRules []string          `json:"disabledRules"`
+	Severities    map[string]string `json:"severities"`
+}
+
+type PolicyViolation struct {
+	Rule     *PolicyRule
+	Resource string
+	Path     string
+	Value    interface{}
+	Found    bool
+}
+
+func (v *PolicyViolation) String() string {
+	if !v.Found {
+		return fmt.Sprintf("%s: %s: %s: %s is not set", v.Resource, v.Rule.Name, v.Rule.Message, v.Path)
+	}
+
+	return fmt.Sprintf("%s: %s: %s: %s = %v", v.Resource, v.Rule.Name, v.Rule.Message, v.Path, formatPolicyValue(v.Value))
+}
+
+type PolicyOptions struct {
+	// Repo is a value of global.dapp.repo all images should be pulled from, image-repo rule is not checked without repo
+	Repo string
+	// BuiltinSeverity is a default severity of built-in rules (error by default), project severities override it
+	BuiltinSeverity string
+}
+
+// CheckPolicies checks the rendered manifest against built-in and project policies.
+// All violations are printed, error is returned when there are violations of error severity.
+func CheckPolicies(manifest, projectDir string, opts PolicyOptions) error {
+	rules, err := LoadPolicyRules(projectDir, opts)
+	if err != nil {
+		return err
+	}
+
+	violations, err := checkPolicyRules(manifest, rules)
+	if err != nil {
+		return err
+	}
+
+	var errorsCount int
+	for _, violation := range violations {
+		if violation.Rule.Severity == PolicySeverityWarning {
+			logger.LogWarningF("WARNING %s\n", violation)
+		} else {
+			logger.LogErrorF("ERROR %s\n", violation)
+			errorsCount++
+		}
+	}
+
+	if errorsCount > 0 {
+		return fmt.Errorf("%d policy violations found", errorsCount)
+	}
+
+	logger.LogF("Policies check passed (%d rules)\n", len(rules))
+
+	return nil
+}
+
+// LoadPolicyRules returns built-in rules and rules of project .helm/policies/*.yaml files without disabled rules.
+// Severities of any rules including built-in ones are overridden by severities of the project files.
+func LoadPolicyRules(projectDir string, opts PolicyOptions) ([]*PolicyRule, error) {
+	rules := builtinPolicyRules(opts)
+	disabledRules := make(map[string]bool)
+	severities := make(map[string]string)
+
+	paths, err := filepath.Glob(filepath.Join(projectDir, ProjectPoliciesDir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %s", path, err)
+		}
+
+		var config PoliciesConfig
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("bad policies file %s: %s", path, err)
+		}
+
+		rules = append(rules, config.Rules...)
+		for _, name := range config.DisabledRules {
+			disabledRules[name] = true
+		}
+		for name, severity := range config.Severities {
+			severities[name] = severity
+		}
+	}
+
+	var res []*PolicyRule
+	names := make(map[string]bool)
+	for _, rule := range rules {
+		if severity, ok := severities[rule.Name]; ok {
+			rule.Severity = severity
+		}
+
+		if err := rule.init(); err != nil {
+			return nil, fmt.Errorf("bad policy rule '%s': %s", rule.Name, err)
+		}
+
+		if names[rule.Name] {
+			return nil, fmt.Errorf("policy rule '%s' is defined more than once", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if !disabledRules[rule.Name] {
+			res = append(res, rule)
+		}
+	}
+
+	for name := range severities {
+		if !names[name] {
+			return nil, fmt.Errorf("severity is specified for unknown policy rule '%s'", name)
+		}
+	}
+
+	return res, nil
+}
+
+func (rule *PolicyRule) init() error {
+	if rule.Name == "" {
+		return fmt.Errorf("name required")
+	}
+
+	if rule.Path == "" {
+		return fmt.Errorf("path required")
+	}
+	if _, err := parsePolicyPath(rule.Path); err != nil {
+		return fmt.Errorf("bad path '%s': %s", rule.Path, err)
+	}
+
+	switch rule.Severity {
+	case "":
+		rule.Severity = PolicySeverityError
+	case PolicySeverityError, PolicySeverityWarning:
+	default:
+		return fmt.Errorf("bad severity '%s': expected %s or %s", rule.Severity, PolicySeverityError, PolicySeverityWarning)
+	}
+
+	checks := 0
+	for _, isSet := range []bool{rule.Exists != nil, rule.Equals != nil, rule.NotEquals != nil, rule.Matches != "", rule.NotMatches != "", rule.OneOf != nil} {
+		if isSet {
+			checks++
+		}
+	}
+	if checks != 1 {
+		return fmt.Errorf("exactly one of exists, equals, notEquals, matches, notMatches or oneOf required")
+	}
+
+	var err error
+	if rule.Matches != "" {
+		if rule.matchesRegexp, err = regexp.Compile(rule.Matches); err != nil {
+			return fmt.Errorf("bad matches regexp: %s", err)
+		}
+	}
+	if rule.NotMatches != "" {
+		if rule.notMatchesRegexp, err = regexp.Compile(rule.NotMatches); err != nil {
+			return fmt.Errorf("bad notMatches regexp: %s", err)
+		}
+	}
+
+	if rule.Message == "" {
+		rule.Message = "policy violated"
+	}
+
+	return nil
+}
+
+func (rule *PolicyRule) isApplicable(kind string) bool {
+	if len(rule.Kinds) == 0 {
+		return true
+	}
+
+	for _, ruleKind := range rule.Kinds {
+		if strings.ToLower(ruleKind) == strings.ToLower(kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rule *PolicyRule) isViolatedBy(value *policyPathValue) bool {
+	if rule.Exists != nil {
+		return value.Found != *rule.Exists
+	}
+
+	if !value.Found || value.Value == nil {
+		return false
+	}
+
+	str := formatPolicyValue(value.Value)
+
+	switch {
+	case rule.Equals != nil:
+		return str != formatPolicyValue(rule.Equals)
+	case rule.NotEquals != nil:
+		return str == formatPolicyValue(rule.NotEquals)
+	case rule.matchesRegexp != nil:
+		return !rule.matchesRegexp.MatchString(str)
+	case rule.notMatchesRegexp != nil:
+		return rule.notMatchesRegexp.MatchString(str)
+	case rule.OneOf != nil:
+		for _, allowed := range rule.OneOf {
+			if str == formatPolicyValue(allowed) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+func checkPolicyRules(manifest string, rules []*PolicyRule) ([]*PolicyViolation, error) {
+	var violations []*PolicyViolation
+
+	for _, doc := range splitManifestDocs(manifest) {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, fmt.Errorf("bad manifest yaml: %s\n%s", err, doc)
+		}
+		if obj == nil {
+			continue
+		}
+
+		kind, _ := obj["kind"].(string)
+		name, _ := objectField(obj, "metadata", "name").(string)
+		resource := fmt.Sprintf("%s/%s", strings.ToLower(kind), name)
+
+		skipRules := make(map[string]bool)
+		if value, ok := objectField(obj, "metadata", "annotations", SkipPoliciesAnnotation).(string); ok {
+			for _, name := range strings.Split(value, ",") {
+				skipRules[strings.TrimSpace(name)] = true
+			}
+		}
+
+		for _, rule := range rules {
+			if skipRules[rule.Name] || !rule.isApplicable(kind) {
+				continue
+			}
+
+			for _, path := range expandPolicyPath(rule.Path, kind) {
+				segments, err := parsePolicyPath(path)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, value := range selectPolicyPathValues(obj, segments, nil) {
+					if rule.isViolatedBy(value) {
+						violations = append(violations, &PolicyViolation{
+							Rule:     rule,
+							Resource: resource,
+							Path:     value.Path,
+							Value:    value.Value,
+							Found:    value.Found,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return violations, nil
+}
+
+// expandPolicyPath replaces $podSpec and $containers with paths of the kind.
+// No paths are returned if the kind has no pod spec.
+func expandPolicyPath(path, kind string) []string {
+	if !strings.HasPrefix(path, "$") {
+		return []string{path}
+	}
+
+	var podSpecPath string
+	switch strings.ToLower(kind) {
+	case "pod":
+		podSpecPath = "spec"
+	case "deployment", "statefulset", "daemonset", "replicaset", "replicationcontroller", "job":
+		podSpecPath = "spec.template.spec"
+	case "cronjob":
+		podSpecPath = "spec.jobTemplate.spec.template.spec"
+	default:
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(path, "$podSpec"):
+		return []string{podSpecPath + strings.TrimPrefix(path, "$podSpec")}
+	case strings.HasPrefix(path, "$containers"):
+		rest := strings.TrimPrefix(path, "$containers")
+		return []string{
+			podSpecPath + ".containers[*]" + rest,
+			podSpecPath + ".initContainers[*]" + rest,
+		}
+	}
+
+	return []string{path}
+}
+
+const (
+	policyPathAnyItem  = "[*]"
+	policyPathAnyValue = "*"
+)
+
+var policyPathSegmentRegexp = regexp.MustCompile(`^([^.\[\]]+|\[\*\]|\[\d+\])`)
+
+func parsePolicyPath(path string) ([]string, error) {
+	if strings.HasPrefix(path, "$") {
+		for _, alias := range []string{"$podSpec", "$containers"} {
+			if path == alias || strings.HasPrefix(path, alias+".") {
+				return parsePolicyPath(strings.TrimPrefix(strings.TrimPrefix(path, alias), "."))
+			}
+		}
+		return nil, fmt.Errorf("unknown alias, expected $podSpec or $containers")
+	}
+
+	var segments []string
+	for rest := path; rest != ""; {
+		segment := policyPathSegmentRegexp.FindString(rest)
+		if segment == "" {
+			return nil, fmt.Errorf("unexpected '%s'", rest)
+		}
+		segments = append(segments, segment)
+
+		rest = strings.TrimPrefix(rest[len(segment):], ".")
+	}
+
+	return segments, nil
+}
+
+type policyPathValue struct {
+	Path  string
+	Value interface{}
+	Found bool
+}
+
+func selectPolicyPathValues(value interface{}, segments []string, path []string) []*policyPathValue {
+	if len(segments) == 0 {
+		return []*policyPathValue{{Path: formatPolicyPath(path), Value: value, Found: true}}
+	}
+
+	segment := segments[0]
+
+	switch {
+	case segment == policyPathAnyItem:
+		arr, _ := value.([]interface{})
+
+		var res []*policyPathValue
+		for ind, item := range arr {
+			res = append(res, selectPolicyPathValues(item, segments[1:], append(path, fmt.Sprintf("[%d]", ind)))...)
+		}
+		return res
+	case strings.HasPrefix(segment, "["):
+		arr, _ := value.([]interface{})
+
+		ind, _ := strconv.Atoi(strings.Trim(segment, "[]"))
+		if ind >= len(arr) {
+			return nil
+		}
+		return selectPolicyPathValues(arr[ind], segments[1:], append(path, segment))
+	case segment == policyPathAnyValue:
+		obj, _ := value.(map[string]interface{})
+
+		var keys []string
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var res []*policyPathValue
+		for _, key := range keys {
+			res = append(res, selectPolicyPathValues(obj[key], segments[1:], append(path, key))...)
+		}
+		return res
+	default:
+		obj, _ := value.(map[string]interface{})
+
+		fieldValue, ok := obj[segment]
+		if !ok {
+			// Missing field is reported with the full path of the rule,
+			// there is nothing to report if the path selects items of missing array or object
+			missingPath := append(append([]string{}, path...), segment)
+			for _, s := range segments[1:] {
+				if s == policyPathAnyValue || strings.HasPrefix(s, "[") {
+					return nil
+				}
+				missingPath = append(missingPath, s)
+			}
+			return []*policyPathValue{{Path: formatPolicyPath(missingPath)}}
+		}
+
+		return selectPolicyPathValues(fieldValue, segments[1:], append(path, segment))
+	}
+}
+
+func formatPolicyPath(path []string) string {
+	var res string
+	for _, segment := range path {
+		if res != "" && !strings.HasPrefix(segment, "[") {
+			res += "."
+		}
+		res += segment
+	}
+
+	return res
+}
+
+func formatPolicyValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	if data, err := json.Marshal(value); err == nil {
+		return string(data)
+	}
+
+	return fmt.Sprintf("%v", value)
+}
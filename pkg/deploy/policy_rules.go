@@ -0,0 +1,63 @@
+package deploy
+
+import (
+	"regexp"
+)
+
+func builtinPolicyRules(opts PolicyOptions) []*PolicyRule {
+	exists, notExists := true, false
+
+	rules := []*PolicyRule{
+		{
+			Name:    "container-cpu-limit",
+			Message: "container must have cpu limit",
+			Path:    "$containers.resources.limits.cpu",
+			Exists:  &exists,
+		},
+		{
+			Name:    "container-memory-limit",
+			Message: "container must have memory limit",
+			Path:    "$containers.resources.limits.memory",
+			Exists:  &exists,
+		},
+		{
+			Name:       "image-tag",
+			Message:    "image must have tag other than latest or digest",
+			Path:       "$containers.image",
+			NotMatches: `^(.*/)?[^/:@]+(:latest)?$`,
+		},
+		{
+			Name:    "no-host-path",
+			Message: "hostPath volumes are not allowed",
+			Path:    "$podSpec.volumes[*].hostPath",
+			Exists:  &notExists,
+		},
+		{
+			Name:      "no-host-network",
+			Message:   "host network is not allowed",
+			Path:      "$podSpec.hostNetwork",
+			NotEquals: true,
+		},
+		{
+			Name:      "no-privileged",
+			Message:   "privileged containers are not allowed",
+			Path:      "$containers.securityContext.privileged",
+			NotEquals: true,
+		},
+	}
+
+	if opts.Repo != "" {
+		rules = append(rules, &PolicyRule{
+			Name:    "image-repo",
+			Message: "image must be pulled from global.dapp.repo",
+			Path:    "$containers.image",
+			Matches: "^" + regexp.QuoteMeta(opts.Repo) + "[/:@]",
+		})
+	}
+
+	for _, rule := range rules {
+		rule.Severity = opts.BuiltinSeverity
+	}
+
+	return rules
+}
@@ -0,0 +1,257 @@
+package deploy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const policyTestManifest = `---
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      hostNetwork: true
+      volumes:
+      - name: data
+        hostPath:
+          path: /data
+      initContainers:
+      - name: init
+        image: registry.example.com/app:init
+        resources:
+          limits:
+            cpu: 100m
+            memory: 64Mi
+      containers:
+      - name: app
+        image: nginx
+        resources:
+          limits:
+            memory: 128Mi
+---
+kind: CronJob
+metadata:
+  name: cron
+  annotations:
+    dapp/skip-policies: container-memory-limit
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: job
+            image: registry.example.com/app@sha256:abc
+            resources:
+              limits:
+                cpu: 1
+---
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  replicas: "3"
+`
+
+func TestCheckPolicyRules(t *testing.T) {
+	exists, notExists := true, false
+
+	expectations := []struct {
+		rule       *PolicyRule
+		violations []string
+	}{
+		{
+			rule: &PolicyRule{Name: "cpu", Path: "$containers.resources.limits.cpu", Exists: &exists},
+			violations: []string{
+				"deployment/app spec.template.spec.containers[0].resources.limits.cpu",
+			},
+		},
+		{
+			rule: &PolicyRule{Name: "container-memory-limit", Path: "$containers.resources.limits.memory", Exists: &exists},
+		},
+		{
+			rule: &PolicyRule{Name: "host-path", Path: "$podSpec.volumes[*].hostPath", Exists: &notExists},
+			violations: []string{
+				"deployment/app spec.template.spec.volumes[0].hostPath",
+			},
+		},
+		{
+			rule: &PolicyRule{Name: "host-network", Path: "$podSpec.hostNetwork", NotEquals: true},
+			violations: []string{
+				"deployment/app spec.template.spec.hostNetwork",
+			},
+		},
+		{
+			rule: &PolicyRule{Name: "repo", Path: "$containers.image", Matches: "^registry.example.com/"},
+			violations: []string{
+				"deployment/app spec.template.spec.containers[0].image",
+			},
+		},
+		{
+			rule: &PolicyRule{Name: "tag", Path: "$containers.image", NotMatches: `^(.*/)?[^/:@]+(:latest)?$`},
+			violations: []string{
+				"deployment/app spec.template.spec.containers[0].image",
+			},
+		},
+		{
+			rule: &PolicyRule{Name: "replicas", Kinds: []string{"configmap"}, Path: "data.*", OneOf: []interface{}{"1", "2"}},
+			violations: []string{
+				"configmap/cm data.replicas",
+			},
+		},
+		{
+			rule: &PolicyRule{Name: "replicas-equals", Kinds: []string{"ConfigMap"}, Path: "data.replicas", Equals: 3},
+		},
+		{
+			rule: &PolicyRule{Name: "other-kind", Kinds: []string{"Service"}, Path: "spec.type", Exists: &exists},
+		},
+	}
+
+	for _, e := range expectations {
+		if err := e.rule.init(); err != nil {
+			t.Fatalf("\n[RULE]: %s\n[ERROR]: %s", e.rule.Name, err)
+		}
+
+		violations, err := checkPolicyRules(policyTestManifest, []*PolicyRule{e.rule})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, violation := range violations {
+			got = append(got, violation.Resource+" "+violation.Path)
+		}
+
+		if strings.Join(got, "\n") != strings.Join(e.violations, "\n") {
+			t.Errorf("\n[RULE]: %s\n[EXPECTED]: %v\n[GOT]: %v", e.rule.Name, e.violations, got)
+		}
+	}
+}
+
+func TestPolicyRuleInit_negative(t *testing.T) {
+	exists := true
+
+	for _, rule := range []*PolicyRule{
+		{Path: "spec", Exists: &exists},
+		{Name: "no-path", Exists: &exists},
+		{Name: "no-check", Path: "spec"},
+		{Name: "two-checks", Path: "spec", Exists: &exists, Matches: "a"},
+		{Name: "bad-alias", Path: "$pod.spec", Exists: &exists},
+		{Name: "bad-path", Path: "spec..type", Exists: &exists},
+		{Name: "bad-regexp", Path: "spec", Matches: "("},
+		{Name: "bad-severity", Path: "spec", Exists: &exists, Severity: "fatal"},
+	} {
+		if err := rule.init(); err == nil {
+			t.Errorf("\n[RULE]: %#v\n[EXPECTED]: error\n[GOT]: no error", rule)
+		}
+	}
+}
+
+func TestLoadPolicyRules(t *testing.T) {
+	projectDir, err := ioutil.TempDir("", "dapp-policy-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(projectDir)
+
+	writePolicies := func(data string) {
+		if err := os.MkdirAll(filepath.Join(projectDir, ProjectPoliciesDir), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(projectDir, ProjectPoliciesDir, "policies.yaml"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writePolicies(`
+rules:
+- name: service-type
+  kinds: [Service]
+  path: spec.type
+  oneOf: [ClusterIP]
+- name: no-latest
+  severity: warning
+  path: $containers.image
+  notMatches: ":latest$"
+disabledRules:
+- no-host-path
+severities:
+  container-cpu-limit: error
+  no-latest: error
+`)
+
+	expectations := []struct {
+		opts       PolicyOptions
+		severities map[string]string
+	}{
+		{
+			opts: PolicyOptions{},
+			severities: map[string]string{
+				"container-cpu-limit":    PolicySeverityError,
+				"container-memory-limit": PolicySeverityError,
+				"image-tag":              PolicySeverityError,
+				"no-host-network":        PolicySeverityError,
+				"no-privileged":          PolicySeverityError,
+				"service-type":           PolicySeverityError,
+				"no-latest":              PolicySeverityError,
+			},
+		},
+		{
+			opts: PolicyOptions{Repo: "registry.example.com/app", BuiltinSeverity: PolicySeverityWarning},
+			severities: map[string]string{
+				"container-cpu-limit":    PolicySeverityError,
+				"container-memory-limit": PolicySeverityWarning,
+				"image-tag":              PolicySeverityWarning,
+				"no-host-network":        PolicySeverityWarning,
+				"no-privileged":          PolicySeverityWarning,
+				"image-repo":             PolicySeverityWarning,
+				"service-type":           PolicySeverityError,
+				"no-latest":              PolicySeverityError,
+			},
+		},
+	}
+
+	for _, e := range expectations {
+		rules, err := LoadPolicyRules(projectDir, e.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := make(map[string]string)
+		for _, rule := range rules {
+			got[rule.Name] = rule.Severity
+		}
+
+		if formatSeverities(got) != formatSeverities(e.severities) {
+			t.Errorf("\n[OPTIONS]: %#v\n[EXPECTED]: %s\n[GOT]: %s", e.opts, formatSeverities(e.severities), formatSeverities(got))
+		}
+	}
+
+	for _, data := range []string{
+		"severities:\n  unknown-rule: warning\n",
+		"severities:\n  image-tag: fatal\n",
+		"rules:\n- name: image-tag\n  path: spec\n  exists: true\n",
+	} {
+		writePolicies(data)
+
+		if _, err := LoadPolicyRules(projectDir, PolicyOptions{}); err == nil {
+			t.Errorf("\n[POLICIES]:\n%s\n[EXPECTED]: error\n[GOT]: no error", data)
+		}
+	}
+}
+
+func formatSeverities(severities map[string]string) string {
+	var res []string
+	for name, severity := range severities {
+		res = append(res, name+"="+severity)
+	}
+	sort.Strings(res)
+
+	return strings.Join(res, ", ")
+}
@@ -0,0 +1,486 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flant/kubedog/pkg/kube"
+	"github.com/flant/kubedog/pkg/tracker"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flant/dapp/pkg/logger"
+)
+
+const (
+	ReadyConditionAnnotation    = "dapp/ready-condition"
+	TrackLoadBalancerAnnotation = "dapp/track-load-balancer"
+
+	readinessPollPeriod = 2 * time.Second
+	// CRD of the custom resource may be created by the same release and registered by the api server with a delay
+	apiResourceDiscoveryTimeout = 2 * time.Minute
+)
+
+// errApiResourceNotFound is returned when the api server does not serve the kind of the resource (yet).
+type errApiResourceNotFound struct {
+	msg string
+}
+
+func (err *errApiResourceNotFound) Error() string {
+	return err.msg
+}
+
+// readinessFunc checks the resource object and returns readiness and a short human readable state.
+type readinessFunc func(obj map[string]interface{}) (bool, string)
+
+// getResourceReadiness returns api version to get the resource with and readiness check of the resource template.
+// Nil readinessFunc is returned for resources without readiness tracking.
+func getResourceReadiness(template *Template) (string, readinessFunc, error) {
+	if value, ok := template.Metadata.Annotations[ReadyConditionAnnotation]; ok {
+		readiness, err := conditionReadiness(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s/%s: bad %s annotation value '%s': %s", strings.ToLower(template.Kind), template.Metadata.Name, ReadyConditionAnnotation, value, err)
+		}
+		return template.Version, readiness, nil
+	}
+
+	switch strings.ToLower(template.Kind) {
+	case "ingress", "service":
+		// Many ingress controllers and bare-metal clusters never publish load balancer status, so waiting for it is opt-in
+		trackLoadBalancer, err := isLoadBalancerTracked(template)
+		if err != nil || !trackLoadBalancer {
+			return "", nil, err
+		}
+
+		if strings.ToLower(template.Kind) == "ingress" {
+			return template.Version, loadBalancerReadiness, nil
+		}
+		return template.Version, serviceReadiness, nil
+	case "pod":
+		return template.Version, podReadiness, nil
+	case "deployment":
+		return template.Version, deploymentReadiness, nil
+	case "statefulset":
+		return template.Version, statefulSetReadiness, nil
+	case "daemonset":
+		return template.Version, daemonSetReadiness, nil
+	case "job":
+		return template.Version, jobReadiness, nil
+	case "persistentvolumeclaim":
+		return template.Version, pvcReadiness, nil
+	case "horizontalpodautoscaler":
+		// Declared api version is served by the cluster, conditions of autoscaling/v1 representation are read from the annotation
+		return template.Version, hpaReadiness, nil
+	}
+
+	return "", nil, nil
+}
+
+func isLoadBalancerTracked(template *Template) (bool, error) {
+	value, ok := template.Metadata.Annotations[TrackLoadBalancerAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	track, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s/%s: bad %s annotation value '%s': %s", strings.ToLower(template.Kind), template.Metadata.Name, TrackLoadBalancerAnnotation, value, err)
+	}
+
+	return track, nil
+}
+
+// conditionReadiness parses condition like Ready=True (status True is used by default).
+func conditionReadiness(value string) (readinessFunc, error) {
+	parts := strings.SplitN(value, "=", 2)
+
+	conditionType := strings.TrimSpace(parts[0])
+	if conditionType == "" {
+		return nil, fmt.Errorf("expected format TYPE[=STATUS]")
+	}
+
+	conditionStatus := "True"
+	if len(parts) == 2 {
+		conditionStatus = strings.TrimSpace(parts[1])
+	}
+
+	return func(obj map[string]interface{}) (bool, string) {
+		status, found := objectConditionStatus(obj, conditionType)
+		if !found {
+			return false, fmt.Sprintf("condition %s not found", conditionType)
+		}
+
+		return status == conditionStatus, fmt.Sprintf("condition %s=%s", conditionType, status)
+	}, nil
+}
+
+func loadBalancerReadiness(obj map[string]interface{}) (bool, string) {
+	ingress, _ := objectField(obj, "status", "loadBalancer", "ingress").([]interface{})
+	if len(ingress) == 0 {
+		return false, "waiting for load balancer address"
+	}
+
+	var addresses []string
+	for _, item := range ingress {
+		if m, ok := item.(map[string]interface{}); ok {
+			for _, field := range []string{"ip", "hostname"} {
+				if address, ok := m[field].(string); ok && address != "" {
+					addresses = append(addresses, address)
+				}
+			}
+		}
+	}
+
+	return true, fmt.Sprintf("load balancer address %s", strings.Join(addresses, ", "))
+}
+
+func serviceReadiness(obj map[string]interface{}) (bool, string) {
+	if serviceType, _ := objectField(obj, "spec", "type").(string); serviceType != "LoadBalancer" {
+		return true, ""
+	}
+
+	return loadBalancerReadiness(obj)
+}
+
+func pvcReadiness(obj map[string]interface{}) (bool, string) {
+	phase, _ := objectField(obj, "status", "phase").(string)
+	return phase == "Bound", fmt.Sprintf("phase %s", phase)
+}
+
+func podReadiness(obj map[string]interface{}) (bool, string) {
+	phase, _ := objectField(obj, "status", "phase").(string)
+	if phase == "Succeeded" {
+		return true, fmt.Sprintf("phase %s", phase)
+	}
+
+	status, _ := objectConditionStatus(obj, "Ready")
+	return status == "True", fmt.Sprintf("phase %s, condition Ready=%s", phase, status)
+}
+
+// deploymentReadiness checks rollout the same way as kubectl rollout status.
+func deploymentReadiness(obj map[string]interface{}) (bool, string) {
+	if !isGenerationObserved(obj) {
+		return false, "waiting for rollout to be observed"
+	}
+
+	replicas := objectReplicas(obj)
+	updated := objectInt(obj, "status", "updatedReplicas")
+	total := objectInt(obj, "status", "replicas")
+	available := objectInt(obj, "status", "availableReplicas")
+
+	state := fmt.Sprintf("%d of %d replicas updated, %d available", updated, replicas, available)
+	if total > updated {
+		state += fmt.Sprintf(", %d old replicas pending termination", total-updated)
+	}
+
+	return updated >= replicas && total <= updated && available >= updated, state
+}
+
+func statefulSetReadiness(obj map[string]interface{}) (bool, string) {
+	if !isGenerationObserved(obj) {
+		return false, "waiting for rollout to be observed"
+	}
+
+	replicas := objectReplicas(obj)
+	ready := objectInt(obj, "status", "readyReplicas")
+	updated := objectInt(obj, "status", "updatedReplicas")
+
+	state := fmt.Sprintf("%d of %d replicas ready, %d updated", ready, replicas, updated)
+	if ready < replicas {
+		return false, state
+	}
+
+	if strategy, _ := objectField(obj, "spec", "updateStrategy", "type").(string); strategy == "OnDelete" {
+		return true, state
+	}
+
+	if partition := objectInt(obj, "spec", "updateStrategy", "rollingUpdate", "partition"); partition > 0 {
+		return updated >= replicas-partition, state
+	}
+
+	currentRevision, _ := objectField(obj, "status", "currentRevision").(string)
+	updateRevision, _ := objectField(obj, "status", "updateRevision").(string)
+
+	return currentRevision == updateRevision, state
+}
+
+func daemonSetReadiness(obj map[string]interface{}) (bool, string) {
+	if !isGenerationObserved(obj) {
+		return false, "waiting for rollout to be observed"
+	}
+
+	desired := objectInt(obj, "status", "desiredNumberScheduled")
+	updated := objectInt(obj, "status", "updatedNumberScheduled")
+	available := objectInt(obj, "status", "numberAvailable")
+
+	return updated >= desired && available >= desired, fmt.Sprintf("%d of %d pods updated, %d available", updated, desired, available)
+}
+
+func jobReadiness(obj map[string]interface{}) (bool, string) {
+	if status, _ := objectConditionStatus(obj, "Complete"); status == "True" {
+		return true, "complete"
+	}
+
+	return false, fmt.Sprintf("%d active, %d succeeded, %d failed pods", objectInt(obj, "status", "active"), objectInt(obj, "status", "succeeded"), objectInt(obj, "status", "failed"))
+}
+
+const hpaConditionsAnnotation = "autoscaling.alpha.kubernetes.io/conditions"
+
+func hpaReadiness(obj map[string]interface{}) (bool, string) {
+	status, found := objectConditionStatus(obj, "AbleToScale")
+	if !found {
+		status, found = hpaAnnotationConditionStatus(obj, "AbleToScale")
+	}
+	if !found {
+		return false, "waiting for scale target"
+	}
+
+	return status == "True", fmt.Sprintf("condition AbleToScale=%s", status)
+}
+
+// hpaAnnotationConditionStatus returns condition status of autoscaling/v1 object, which keeps conditions as json in the annotation.
+func hpaAnnotationConditionStatus(obj map[string]interface{}, conditionType string) (string, bool) {
+	value, _ := objectField(obj, "metadata", "annotations", hpaConditionsAnnotation).(string)
+	if value == "" {
+		return "", false
+	}
+
+	var conditions []interface{}
+	if err := json.Unmarshal([]byte(value), &conditions); err != nil {
+		return "", false
+	}
+
+	return objectConditionStatus(map[string]interface{}{"status": map[string]interface{}{"conditions": conditions}}, conditionType)
+}
+
+// containerFailureReasons are reasons of waiting containers which are not fixed without changes of the release.
+var containerFailureReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CrashLoopBackOff":           true,
+	"CreateContainerConfigError": true,
+}
+
+// resourceFailure returns error if the resource object or its pods are failed.
+// Tracking is retried after a failure according to dapp/failures-allowed annotation.
+func resourceFailure(kind string, obj map[string]interface{}, pods []corev1.Pod) error {
+	switch strings.ToLower(kind) {
+	case "pod":
+		if phase, _ := objectField(obj, "status", "phase").(string); phase == "Failed" {
+			reason, _ := objectField(obj, "status", "reason").(string)
+			return fmt.Errorf("pod failed: %s", reason)
+		}
+	case "deployment":
+		if status, _ := objectConditionStatus(obj, "Progressing"); status == "False" {
+			return fmt.Errorf("deployment is not progressing: %s", objectConditionMessage(obj, "Progressing"))
+		}
+	case "job":
+		if status, _ := objectConditionStatus(obj, "Failed"); status == "True" {
+			return fmt.Errorf("job failed: %s", objectConditionMessage(obj, "Failed"))
+		}
+	}
+
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Waiting != nil && containerFailureReasons[status.State.Waiting.Reason] {
+				return fmt.Errorf("po/%s container/%s: %s: %s", pod.Name, status.Name, status.State.Waiting.Reason, status.State.Waiting.Message)
+			}
+		}
+	}
+
+	return nil
+}
+
+func trackResourceReadiness(template *Template, apiVersion, namespace string, readiness readinessFunc, opts tracker.Options) error {
+	resourceName := fmt.Sprintf("%s/%s", strings.ToLower(template.Kind), template.Metadata.Name)
+
+	var deadline <-chan time.Time
+	if opts.Timeout != 0 {
+		deadline = time.After(opts.Timeout)
+	}
+
+	discoveryDeadline := time.Now().Add(apiResourceDiscoveryTimeout)
+
+	var lastState string
+	for {
+		obj, err := getResourceObject(apiVersion, template.Kind, namespace, template.Metadata.Name)
+		if _, ok := err.(*errApiResourceNotFound); ok {
+			if time.Now().After(discoveryDeadline) {
+				return fmt.Errorf("%s: %s", resourceName, err)
+			}
+
+			if state := err.Error(); state != lastState {
+				logger.LogF("# %s: %s, retrying\n", resourceName, state)
+				lastState = state
+			}
+		} else if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("%s: %s", resourceName, err)
+		}
+
+		if obj != nil {
+			var pods []corev1.Pod
+			if hasPods(template) {
+				// Pods errors are not fatal for tracking, the resource state is checked anyway
+				pods, _ = resourcePods(template, namespace)
+			}
+
+			if err := resourceFailure(template.Kind, obj, pods); err != nil {
+				return fmt.Errorf("%s: %s", resourceName, err)
+			}
+
+			ready, state := readiness(obj)
+			if state != "" && state != lastState {
+				logger.LogF("# %s: %s\n", resourceName, state)
+				lastState = state
+			}
+
+			if ready {
+				return nil
+			}
+		}
+
+		select {
+		case <-deadline:
+			if lastState == "" {
+				lastState = "resource not found"
+			}
+			return fmt.Errorf("%s: timed out waiting for readiness: %s", resourceName, lastState)
+		case <-time.After(readinessPollPeriod):
+		}
+	}
+}
+
+var (
+	apiResources    = make(map[string]*metav1.APIResourceList)
+	apiResourcesMux sync.Mutex
+)
+
+// getResourceObject gets any resource, including custom resources, as unstructured object.
+func getResourceObject(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+	apiResourcesMux.Lock()
+	resourceList, ok := apiResources[apiVersion]
+	if !ok {
+		var err error
+		resourceList, err = kube.Kubernetes.Discovery().ServerResourcesForGroupVersion(apiVersion)
+		if apierrors.IsNotFound(err) {
+			apiResourcesMux.Unlock()
+			return nil, &errApiResourceNotFound{msg: fmt.Sprintf("api %s not found", apiVersion)}
+		} else if err != nil {
+			apiResourcesMux.Unlock()
+			return nil, fmt.Errorf("cannot get api resources of %s: %s", apiVersion, err)
+		}
+		apiResources[apiVersion] = resourceList
+	}
+	apiResourcesMux.Unlock()
+
+	var resource *metav1.APIResource
+	for ind := range resourceList.APIResources {
+		if resourceList.APIResources[ind].Kind == kind && !strings.Contains(resourceList.APIResources[ind].Name, "/") {
+			resource = &resourceList.APIResources[ind]
+			break
+		}
+	}
+	if resource == nil {
+		// Api resources are requested again on the next call
+		apiResourcesMux.Lock()
+		delete(apiResources, apiVersion)
+		apiResourcesMux.Unlock()
+
+		return nil, &errApiResourceNotFound{msg: fmt.Sprintf("kind %s not found in %s", kind, apiVersion)}
+	}
+
+	path := "/apis/" + apiVersion
+	if apiVersion == "v1" {
+		path = "/api/v1"
+	}
+	if resource.Namespaced {
+		path += "/namespaces/" + namespace
+	}
+	path += fmt.Sprintf("/%s/%s", resource.Name, name)
+
+	data, err := kube.Kubernetes.Discovery().RESTClient().Get().AbsPath(path).DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func objectField(obj map[string]interface{}, fields ...string) interface{} {
+	var value interface{} = obj
+	for _, field := range fields {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[field]
+	}
+
+	return value
+}
+
+// objectInt returns integer field of the object decoded from json, zero is returned if the field is not set.
+func objectInt(obj map[string]interface{}, fields ...string) int64 {
+	value, _ := objectField(obj, fields...).(float64)
+	return int64(value)
+}
+
+// objectReplicas returns spec.replicas of the object, defaulted to 1 as by the api server.
+func objectReplicas(obj map[string]interface{}) int64 {
+	if _, ok := objectField(obj, "spec", "replicas").(float64); !ok {
+		return 1
+	}
+	return objectInt(obj, "spec", "replicas")
+}
+
+func isGenerationObserved(obj map[string]interface{}) bool {
+	return objectInt(obj, "status", "observedGeneration") >= objectInt(obj, "metadata", "generation")
+}
+
+func objectConditionStatus(obj map[string]interface{}, conditionType string) (string, bool) {
+	conditions, _ := objectField(obj, "status", "conditions").([]interface{})
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if condition["type"] == conditionType {
+			status, _ := condition["status"].(string)
+			return status, true
+		}
+	}
+
+	return "", false
+}
+
+func objectConditionMessage(obj map[string]interface{}, conditionType string) string {
+	conditions, _ := objectField(obj, "status", "conditions").([]interface{})
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+
+		reason, _ := condition["reason"].(string)
+		message, _ := condition["message"].(string)
+		if message == "" {
+			return reason
+		}
+		return fmt.Sprintf("%s: %s", reason, message)
+	}
+
+	return ""
+}
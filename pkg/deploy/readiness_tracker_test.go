@@ -0,0 +1,186 @@
+package deploy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func parseTestTemplate(t *testing.T, manifest string) *Template {
+	template := &Template{}
+	if err := yaml.Unmarshal([]byte(manifest), template); err != nil {
+		t.Fatal(err)
+	}
+
+	return template
+}
+
+func TestGetResourceReadiness(t *testing.T) {
+	expectations := map[string]bool{
+		"apiVersion: extensions/v1beta1\nkind: Ingress\nmetadata:\n  name: a\n":                                                         false,
+		"apiVersion: extensions/v1beta1\nkind: Ingress\nmetadata:\n  name: a\n  annotations:\n    dapp/track-load-balancer: \"true\"\n": true,
+		"apiVersion: v1\nkind: Service\nmetadata:\n  name: a\n":                                                                         false,
+		"apiVersion: v1\nkind: Service\nmetadata:\n  name: a\n  annotations:\n    dapp/track-load-balancer: \"false\"\n":                false,
+		"apiVersion: v1\nkind: Service\nmetadata:\n  name: a\n  annotations:\n    dapp/track-load-balancer: \"true\"\n":                 true,
+		"apiVersion: v1\nkind: PersistentVolumeClaim\nmetadata:\n  name: a\n":                                                           true,
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n":                                                                       false,
+		"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: a\n":                                                                 true,
+		"apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: a\n":                                                                       true,
+		"apiVersion: example.com/v1\nkind: Certificate\nmetadata:\n  name: a\n  annotations:\n    dapp/ready-condition: Ready\n":        true,
+	}
+
+	for manifest, expected := range expectations {
+		_, readiness, err := getResourceReadiness(parseTestTemplate(t, manifest))
+		if err != nil {
+			t.Errorf("\n[MANIFEST]:\n%s\n[ERROR]: %s", manifest, err)
+			continue
+		}
+
+		if got := readiness != nil; got != expected {
+			t.Errorf("\n[MANIFEST]:\n%s\n[EXPECTED TRACKED]: %v\n[GOT]: %v", manifest, expected, got)
+		}
+	}
+}
+
+func TestGetResourceReadiness_negative(t *testing.T) {
+	for _, manifest := range []string{
+		"apiVersion: v1\nkind: Service\nmetadata:\n  name: a\n  annotations:\n    dapp/track-load-balancer: \"yes please\"\n",
+		"apiVersion: v1\nkind: Service\nmetadata:\n  name: a\n  annotations:\n    dapp/ready-condition: \"=True\"\n",
+	} {
+		if _, _, err := getResourceReadiness(parseTestTemplate(t, manifest)); err == nil {
+			t.Errorf("\n[MANIFEST]:\n%s\n[EXPECTED]: error\n[GOT]: no error", manifest)
+		}
+	}
+}
+
+func TestConditionReadiness(t *testing.T) {
+	obj := map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False"},
+				map[string]interface{}{"type": "Synced", "status": "True"},
+			},
+		},
+	}
+
+	expectations := map[string]bool{
+		"Ready":       false,
+		"Ready=False": true,
+		"Synced":      true,
+		"Missing":     false,
+	}
+
+	for value, expected := range expectations {
+		readiness, err := conditionReadiness(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, state := readiness(obj); got != expected {
+			t.Errorf("\n[CONDITION]: %s\n[EXPECTED]: %v\n[GOT]: %v (%s)", value, expected, got, state)
+		}
+	}
+}
+
+func parseTestObject(t *testing.T, data string) map[string]interface{} {
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &obj); err != nil {
+		t.Fatal(err)
+	}
+
+	return obj
+}
+
+func TestWorkloadReadiness(t *testing.T) {
+	for _, e := range []struct {
+		readiness readinessFunc
+		obj       string
+		expected  bool
+	}{
+		{deploymentReadiness, `{"metadata": {"generation": 2}, "spec": {"replicas": 2}, "status": {"observedGeneration": 1, "replicas": 2, "updatedReplicas": 2, "availableReplicas": 2}}`, false},
+		{deploymentReadiness, `{"metadata": {"generation": 2}, "spec": {"replicas": 2}, "status": {"observedGeneration": 2, "replicas": 3, "updatedReplicas": 2, "availableReplicas": 2}}`, false},
+		{deploymentReadiness, `{"metadata": {"generation": 2}, "spec": {"replicas": 2}, "status": {"observedGeneration": 2, "replicas": 2, "updatedReplicas": 2, "availableReplicas": 1}}`, false},
+		{deploymentReadiness, `{"metadata": {"generation": 2}, "spec": {"replicas": 2}, "status": {"observedGeneration": 2, "replicas": 2, "updatedReplicas": 2, "availableReplicas": 2}}`, true},
+		{deploymentReadiness, `{"metadata": {"generation": 1}, "spec": {}, "status": {"observedGeneration": 1}}`, false},
+		{deploymentReadiness, `{"metadata": {"generation": 1}, "spec": {"replicas": 0}, "status": {"observedGeneration": 1}}`, true},
+		{statefulSetReadiness, `{"metadata": {"generation": 1}, "spec": {"replicas": 2}, "status": {"observedGeneration": 1, "readyReplicas": 2, "currentRevision": "a", "updateRevision": "b"}}`, false},
+		{statefulSetReadiness, `{"metadata": {"generation": 1}, "spec": {"replicas": 2}, "status": {"observedGeneration": 1, "readyReplicas": 2, "currentRevision": "b", "updateRevision": "b"}}`, true},
+		{statefulSetReadiness, `{"metadata": {"generation": 1}, "spec": {"replicas": 3, "updateStrategy": {"type": "RollingUpdate", "rollingUpdate": {"partition": 2}}}, "status": {"observedGeneration": 1, "readyReplicas": 3, "updatedReplicas": 1}}`, true},
+		{statefulSetReadiness, `{"metadata": {"generation": 1}, "spec": {"replicas": 2, "updateStrategy": {"type": "OnDelete"}}, "status": {"observedGeneration": 1, "readyReplicas": 1}}`, false},
+		{daemonSetReadiness, `{"metadata": {"generation": 1}, "status": {"observedGeneration": 1, "desiredNumberScheduled": 3, "updatedNumberScheduled": 3, "numberAvailable": 2}}`, false},
+		{daemonSetReadiness, `{"metadata": {"generation": 1}, "status": {"observedGeneration": 1, "desiredNumberScheduled": 3, "updatedNumberScheduled": 3, "numberAvailable": 3}}`, true},
+		{jobReadiness, `{"status": {"active": 1}}`, false},
+		{jobReadiness, `{"status": {"succeeded": 1, "conditions": [{"type": "Complete", "status": "True"}]}}`, true},
+		{podReadiness, `{"status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "False"}]}}`, false},
+		{podReadiness, `{"status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}]}}`, true},
+		{podReadiness, `{"status": {"phase": "Succeeded"}}`, true},
+	} {
+		if got, state := e.readiness(parseTestObject(t, e.obj)); got != e.expected {
+			t.Errorf("\n[OBJECT]: %s\n[EXPECTED]: %v\n[GOT]: %v (%s)", e.obj, e.expected, got, state)
+		}
+	}
+}
+
+func TestResourceFailure(t *testing.T) {
+	for _, e := range []struct {
+		kind     string
+		obj      string
+		expected bool
+	}{
+		{"Job", `{"status": {"conditions": [{"type": "Failed", "status": "True", "reason": "BackoffLimitExceeded"}]}}`, true},
+		{"Job", `{"status": {"active": 1}}`, false},
+		{"Pod", `{"status": {"phase": "Failed"}}`, true},
+		{"Pod", `{"status": {"phase": "Pending"}}`, false},
+		{"Deployment", `{"status": {"conditions": [{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"}]}}`, true},
+		{"Deployment", `{"status": {"conditions": [{"type": "Progressing", "status": "True"}]}}`, false},
+	} {
+		if got := resourceFailure(e.kind, parseTestObject(t, e.obj), nil) != nil; got != e.expected {
+			t.Errorf("\n[%s]: %s\n[EXPECTED FAILURE]: %v\n[GOT]: %v", e.kind, e.obj, e.expected, got)
+		}
+	}
+
+	waitingPod := func(reason string) []corev1.Pod {
+		pod := corev1.Pod{}
+		pod.Name = "app-1"
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}}}
+		return []corev1.Pod{pod}
+	}
+
+	if err := resourceFailure("Deployment", map[string]interface{}{}, waitingPod("CrashLoopBackOff")); err == nil {
+		t.Errorf("\n[EXPECTED]: CrashLoopBackOff failure\n[GOT]: no error")
+	}
+
+	if err := resourceFailure("Deployment", map[string]interface{}{}, waitingPod("ContainerCreating")); err != nil {
+		t.Errorf("\n[EXPECTED]: no error\n[GOT]: %s", err)
+	}
+}
+
+func TestHpaReadiness(t *testing.T) {
+	for _, e := range []struct {
+		obj      string
+		expected bool
+	}{
+		{`{"status": {"conditions": [{"type": "AbleToScale", "status": "True"}]}}`, true},
+		{`{"status": {"conditions": [{"type": "AbleToScale", "status": "False"}]}}`, false},
+		{`{"metadata": {"annotations": {"autoscaling.alpha.kubernetes.io/conditions": "[{\"type\":\"AbleToScale\",\"status\":\"True\"}]"}}, "status": {}}`, true},
+		{`{"metadata": {"annotations": {"autoscaling.alpha.kubernetes.io/conditions": "[{\"type\":\"AbleToScale\",\"status\":\"False\"}]"}}, "status": {}}`, false},
+		{`{"status": {}}`, false},
+	} {
+		if got, state := hpaReadiness(parseTestObject(t, e.obj)); got != e.expected {
+			t.Errorf("\n[OBJECT]: %s\n[EXPECTED]: %v\n[GOT]: %v (%s)", e.obj, e.expected, got, state)
+		}
+	}
+
+	for _, version := range []string{"autoscaling/v1", "autoscaling/v2beta2"} {
+		apiVersion, _, err := getResourceReadiness(parseTestTemplate(t, "apiVersion: "+version+"\nkind: HorizontalPodAutoscaler\nmetadata:\n  name: a\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if apiVersion != version {
+			t.Errorf("\n[EXPECTED]: %s\n[GOT]: %s", version, apiVersion)
+		}
+	}
+}
@@ -5,21 +5,33 @@ import (
 	"os"
 
 	"github.com/flant/dapp/pkg/config"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 type RenderOptions struct {
-	Values       []string
-	SecretValues []string
-	Set          []string
-	SetString    []string
+	Values         []string
+	SecretValues   []string
+	Set            []string
+	SetString      []string
+	AddAnnotations []string
+	AddLabels      []string
+
+	InjectGlobalMetadata bool
+	SecretProviders      bool
+
+	// Component chart .helm/COMPONENT is rendered instead of the project chart
+	Component string
 }
 
 func RunRender(projectName, projectDir string, dappfile []*config.Dimg, opts RenderOptions) error {
-	if debug() {
-		fmt.Printf("Render options: %#v\n", opts)
+	logger.LogDebugF("deploy", "Render options: %#v\n", opts)
+
+	_, projectHelmDir, dappfile, err := getComponentChart(projectDir, opts.Component, dappfile)
+	if err != nil {
+		return err
 	}
 
-	m, err := getSafeSecretManager(projectDir, opts.SecretValues)
+	m, err := getSafeSecretManager(projectDir, []string{projectHelmDir}, opts.SecretValues)
 	if err != nil {
 		return fmt.Errorf("cannot get project secret: %s", err)
 	}
@@ -36,15 +48,19 @@ func RunRender(projectName, projectDir string, dappfile []*config.Dimg, opts Ren
 
 	serviceValues, err := GetServiceValues(projectName, repo, namespace, tag, nil, images, ServiceValuesOptions{ForceBranch: "GIT_BRANCH"})
 
-	dappChart, err := getDappChart(projectDir, m, opts.Values, opts.SecretValues, opts.Set, opts.SetString, serviceValues)
+	dappChart, err := getDappChartFromDir(projectHelmDir, projectDir, m, opts.SecretProviders, opts.Values, opts.SecretValues, opts.Set, opts.SetString, serviceValues)
 	if err != nil {
 		return err
 	}
-	if !debug() {
+	if !logger.IsDebug("deploy") {
 		// Do not remove tmp chart in debug
 		defer os.RemoveAll(dappChart.ChartDir)
 	}
 
+	if err := setGlobalMetadata(dappChart, projectName, projectDir, globalMetadataOptions{AddAnnotations: opts.AddAnnotations, AddLabels: opts.AddLabels, Inject: opts.InjectGlobalMetadata, WithoutDeployTime: true}); err != nil {
+		return err
+	}
+
 	data, err := dappChart.Render(namespace)
 	if err != nil {
 		return err
@@ -0,0 +1,26 @@
+package deploy
+
+import (
+	"time"
+
+	"github.com/flant/dapp/pkg/logger"
+)
+
+type RollbackOptions struct {
+	Timeout         time.Duration
+	KubeContext     string
+	KubeLock        bool
+	KubeLockTimeout time.Duration
+}
+
+// RunRollback rolls the release back to the revision and watches its resources until ready.
+// The last successful revision before the current one is used when revision is 0.
+func RunRollback(releaseName string, revision int, namespace string, opts RollbackOptions) error {
+	logger.LogDebugF("deploy", "Rollback options: %#v\n", opts)
+	logger.LogDebugF("deploy", "Namespace: %s\n", namespace)
+
+	return RollbackHelmRelease(releaseName, revision, namespace, HelmChartOptions{
+		CommonHelmOptions: CommonHelmOptions{KubeContext: opts.KubeContext, KubeLock: opts.KubeLock, KubeLockTimeout: opts.KubeLockTimeout},
+		Timeout:           opts.Timeout,
+	})
+}
@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+var yamlDocSeparatorRegexp = regexp.MustCompile(`(?m)^---\s*$`)
+
+// AddCRDsDir adds schemas of all CustomResourceDefinitions from yaml and json files of the dir.
+func (v *Validator) AddCRDsDir(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, doc := range yamlDocSeparatorRegexp.Split(string(data), -1) {
+			if strings.TrimSpace(doc) == "" {
+				continue
+			}
+
+			obj, err := DecodeObject([]byte(doc))
+			if err != nil {
+				return fmt.Errorf("bad file %s: %s", path, err)
+			}
+
+			if obj == nil || obj["kind"] != "CustomResourceDefinition" {
+				continue
+			}
+
+			if err := v.AddCRD(obj); err != nil {
+				return fmt.Errorf("bad file %s: %s", path, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// DecodeObject decodes yaml or json document into the form expected by Validator.Validate.
+func DecodeObject(data []byte) (map[string]interface{}, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+
+	var obj map[string]interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
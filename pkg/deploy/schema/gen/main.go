@@ -0,0 +1,142 @@
+//go:build ignore
+// +build ignore
+
+// Generates bundled kubernetes schema from kubernetes api/openapi-spec/swagger.json:
+//
+//	go run gen/main.go 1.13 $GOPATH/src/k8s.io/kubernetes/api/openapi-spec/swagger.json
+//
+// Only fields used for validation are kept to reduce binary size.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+var keepFields = map[string]bool{
+	"type":                            true,
+	"format":                          true,
+	"$ref":                            true,
+	"properties":                      true,
+	"additionalProperties":            true,
+	"items":                           true,
+	"required":                        true,
+	"enum":                            true,
+	"x-kubernetes-group-version-kind": true,
+}
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintf(os.Stderr, "Usage: %s KUBE_VERSION SWAGGER_JSON\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	if err := generate(os.Args[1], os.Args[2]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(kubeVersion, swaggerPath string) error {
+	data, err := ioutil.ReadFile(swaggerPath)
+	if err != nil {
+		return err
+	}
+
+	var swagger struct {
+		Definitions map[string]interface{} `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &swagger); err != nil {
+		return fmt.Errorf("bad swagger %s: %s", swaggerPath, err)
+	}
+
+	for name, definition := range swagger.Definitions {
+		swagger.Definitions[name] = strip(definition)
+	}
+
+	stripped, err := json.Marshal(swagger)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(stripped); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	var lines []string
+	for len(encoded) > 0 {
+		n := 100
+		if len(encoded) < n {
+			n = len(encoded)
+		}
+		lines = append(lines, encoded[:n])
+		encoded = encoded[n:]
+	}
+
+	id := strings.Replace(kubeVersion, ".", "_", -1)
+	fileName := fmt.Sprintf("kubernetes_%s.go", id)
+
+	content := fmt.Sprintf(`// Code generated by gen/main.go from kubernetes %s api/openapi-spec/swagger.json. DO NOT EDIT.
+
+package schema
+
+func init() {
+	kubernetesSchemas[%q] = kubernetes%sSchema
+}
+
+const kubernetes%sSchema = "" +
+	"%s"
+`, kubeVersion, kubeVersion, id, id, strings.Join(lines, "\" +\n\t\""))
+
+	return ioutil.WriteFile(fileName, []byte(content), 0644)
+}
+
+func strip(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{})
+		for key, field := range v {
+			if !keepFields[key] {
+				continue
+			}
+
+			switch key {
+			case "properties":
+				properties := make(map[string]interface{})
+				for name, property := range field.(map[string]interface{}) {
+					properties[name] = strip(property)
+				}
+				res[key] = properties
+			case "items", "additionalProperties":
+				res[key] = strip(field)
+			default:
+				res[key] = field
+			}
+		}
+		return res
+	case []interface{}:
+		var res []interface{}
+		for _, item := range v {
+			res = append(res, strip(item))
+		}
+		return res
+	}
+
+	return value
+}
@@ -0,0 +1,482 @@
+package schema
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+const (
+	quantityDefinition     = "io.k8s.apimachinery.pkg.api.resource.Quantity"
+	rawExtensionDefinition = "io.k8s.apimachinery.pkg.runtime.RawExtension"
+	objectMetaDefinition   = "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"
+
+	intOrStringFormat = "int-or-string"
+	quantityFormat    = "quantity"
+)
+
+// kubernetesSchemas contains bundled gzipped and base64 encoded swagger definitions by kubernetes version.
+var kubernetesSchemas = map[string]string{}
+
+func SupportedKubeVersions() []string {
+	var versions []string
+	for version := range kubernetesSchemas {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	return versions
+}
+
+type GroupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+func (gvk GroupVersionKind) ApiVersion() string {
+	if gvk.Group == "" {
+		return gvk.Version
+	}
+
+	return fmt.Sprintf("%s/%s", gvk.Group, gvk.Version)
+}
+
+func NewGroupVersionKind(apiVersion, kind string) GroupVersionKind {
+	gvk := GroupVersionKind{Version: apiVersion, Kind: kind}
+
+	if parts := strings.SplitN(apiVersion, "/", 2); len(parts) == 2 {
+		gvk.Group, gvk.Version = parts[0], parts[1]
+	}
+
+	return gvk
+}
+
+// Schema is a subset of swagger and CRD openAPIV3Schema fields used for validation.
+type Schema struct {
+	Type                  string             `json:"type"`
+	Format                string             `json:"format"`
+	Ref                   string             `json:"$ref"`
+	Properties            map[string]*Schema `json:"properties"`
+	Required              []string           `json:"required"`
+	Enum                  []interface{}      `json:"enum"`
+	IntOrString           bool               `json:"x-kubernetes-int-or-string"`
+	PreserveUnknownFields bool               `json:"x-kubernetes-preserve-unknown-fields"`
+	GroupVersionKinds     []GroupVersionKind `json:"x-kubernetes-group-version-kind"`
+
+	Items *Schema `json:"-"`
+
+	// AdditionalProperties is a schema of values or nil when it is specified as boolean
+	AdditionalProperties *Schema `json:"-"`
+	// NoAdditionalProperties is set when additionalProperties is false
+	NoAdditionalProperties bool `json:"-"`
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type schema Schema
+	var raw struct {
+		*schema
+		Items                json.RawMessage `json:"items"`
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}
+	raw.schema = (*schema)(s)
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	// Array of items schemas (tuple validation) is not supported
+	if len(raw.Items) > 0 && raw.Items[0] == '{' {
+		if err := json.Unmarshal(raw.Items, &s.Items); err != nil {
+			return err
+		}
+	}
+
+	switch string(raw.AdditionalProperties) {
+	case "", "null", "true":
+	case "false":
+		s.NoAdditionalProperties = true
+	default:
+		if err := json.Unmarshal(raw.AdditionalProperties, &s.AdditionalProperties); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type kindSchema struct {
+	Schema *Schema
+	// Unknown fields are errors for kubernetes resources, custom resources are not pruned by default
+	Strict bool
+	// Custom resource schemas do not describe apiVersion, kind and metadata
+	Custom bool
+}
+
+type Validator struct {
+	definitions map[string]*Schema
+	kinds       map[GroupVersionKind]*kindSchema
+}
+
+func NewValidator(kubeVersion string) (*Validator, error) {
+	data, ok := kubernetesSchemas[kubeVersion]
+	if !ok {
+		return nil, fmt.Errorf("no schemas for kubernetes version '%s' (supported: %s)", kubeVersion, strings.Join(SupportedKubeVersions(), ", "))
+	}
+
+	definitions, err := loadDefinitions(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load kubernetes %s schemas: %s", kubeVersion, err)
+	}
+
+	v := &Validator{definitions: definitions, kinds: make(map[GroupVersionKind]*kindSchema)}
+
+	for _, definition := range definitions {
+		for _, gvk := range definition.GroupVersionKinds {
+			v.kinds[gvk] = &kindSchema{Schema: definition, Strict: true}
+		}
+	}
+
+	return v, nil
+}
+
+func loadDefinitions(data string) (map[string]*Schema, error) {
+	gzipped, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(gzipped))
+	if err != nil {
+		return nil, err
+	}
+
+	swagger, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Definitions map[string]*Schema `json:"definitions"`
+	}
+	if err := json.Unmarshal(swagger, &res); err != nil {
+		return nil, err
+	}
+
+	// Quantity is described as string but numbers are allowed too,
+	// RawExtension is described by its go structure but any object is allowed.
+	if quantity, ok := res.Definitions[quantityDefinition]; ok {
+		quantity.Format = quantityFormat
+	}
+	res.Definitions[rawExtensionDefinition] = &Schema{}
+
+	return res.Definitions, nil
+}
+
+func (v *Validator) HasSchema(apiVersion, kind string) bool {
+	_, ok := v.kinds[NewGroupVersionKind(apiVersion, kind)]
+	return ok
+}
+
+// AddCRD adds schemas of all versions of CustomResourceDefinition object.
+// CRD without validation schema allows any custom resource.
+func (v *Validator) AddCRD(obj map[string]interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	var crd struct {
+		Spec struct {
+			Group string `json:"group"`
+			Names struct {
+				Kind string `json:"kind"`
+			} `json:"names"`
+			Version    string `json:"version"`
+			Validation *struct {
+				OpenAPIV3Schema *Schema `json:"openAPIV3Schema"`
+			} `json:"validation"`
+			Versions []struct {
+				Name   string `json:"name"`
+				Schema *struct {
+					OpenAPIV3Schema *Schema `json:"openAPIV3Schema"`
+				} `json:"schema"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(data, &crd); err != nil {
+		return fmt.Errorf("bad CustomResourceDefinition: %s", err)
+	}
+
+	if crd.Spec.Group == "" || crd.Spec.Names.Kind == "" {
+		return fmt.Errorf("bad CustomResourceDefinition: spec.group and spec.names.kind required")
+	}
+
+	commonSchema := &Schema{}
+	if crd.Spec.Validation != nil && crd.Spec.Validation.OpenAPIV3Schema != nil {
+		commonSchema = crd.Spec.Validation.OpenAPIV3Schema
+	}
+
+	add := func(version string, s *Schema) {
+		gvk := GroupVersionKind{Group: crd.Spec.Group, Version: version, Kind: crd.Spec.Names.Kind}
+		v.kinds[gvk] = &kindSchema{Schema: s, Custom: true}
+	}
+
+	if crd.Spec.Version != "" {
+		add(crd.Spec.Version, commonSchema)
+	}
+
+	for _, version := range crd.Spec.Versions {
+		if version.Schema != nil && version.Schema.OpenAPIV3Schema != nil {
+			add(version.Name, version.Schema.OpenAPIV3Schema)
+		} else {
+			add(version.Name, commonSchema)
+		}
+	}
+
+	return nil
+}
+
+type ValidationError struct {
+	// Path consists of string fields and int array indexes
+	Path    []interface{}
+	Message string
+}
+
+func (err *ValidationError) PathString() string {
+	var res string
+	for _, elm := range err.Path {
+		switch e := elm.(type) {
+		case int:
+			res += fmt.Sprintf("[%d]", e)
+		default:
+			if res != "" {
+				res += "."
+			}
+			res += fmt.Sprintf("%s", e)
+		}
+	}
+
+	return res
+}
+
+func (err *ValidationError) Error() string {
+	if len(err.Path) == 0 {
+		return err.Message
+	}
+
+	return fmt.Sprintf("%s: %s", err.PathString(), err.Message)
+}
+
+// Validate validates object decoded from json with json.Decoder.UseNumber.
+// Objects without schema are not validated.
+func (v *Validator) Validate(obj map[string]interface{}) []*ValidationError {
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+
+	ks, ok := v.kinds[NewGroupVersionKind(apiVersion, kind)]
+	if !ok {
+		return nil
+	}
+
+	ctx := &validation{validator: v, strict: ks.Strict}
+
+	if ks.Custom {
+		value := make(map[string]interface{})
+		for field, fieldValue := range obj {
+			switch field {
+			case "apiVersion", "kind", "status":
+			case "metadata":
+				if metadata, ok := v.definitions[objectMetaDefinition]; ok {
+					metadataCtx := &validation{validator: v, strict: true}
+					metadataCtx.validate(metadata, fieldValue, []interface{}{field})
+					ctx.errors = append(ctx.errors, metadataCtx.errors...)
+				}
+			default:
+				value[field] = fieldValue
+			}
+		}
+		ctx.validate(ks.Schema, value, nil)
+	} else {
+		ctx.validate(ks.Schema, obj, nil)
+	}
+
+	return ctx.errors
+}
+
+type validation struct {
+	validator *Validator
+	strict    bool
+	errors    []*ValidationError
+}
+
+func (ctx *validation) addError(path []interface{}, format string, a ...interface{}) {
+	ctx.errors = append(ctx.errors, &ValidationError{
+		Path:    append([]interface{}{}, path...),
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (ctx *validation) resolve(s *Schema) *Schema {
+	for i := 0; s != nil && s.Ref != "" && i < 100; i++ {
+		s = ctx.validator.definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]
+	}
+
+	return s
+}
+
+func (ctx *validation) validate(s *Schema, value interface{}, path []interface{}) {
+	s = ctx.resolve(s)
+	if s == nil || value == nil {
+		return
+	}
+
+	if len(s.Enum) > 0 && !isEnumValue(s.Enum, value) {
+		ctx.addError(path, "value %s is not one of %s", formatValue(value), formatValue(s.Enum))
+		return
+	}
+
+	schemaType := s.Type
+	if schemaType == "" && len(s.Properties) > 0 {
+		schemaType = "object"
+	}
+
+	switch schemaType {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			ctx.addError(path, "expected object, got %s", valueType(value))
+			return
+		}
+		ctx.validateObject(s, obj, path)
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			ctx.addError(path, "expected array, got %s", valueType(value))
+			return
+		}
+		for ind, item := range arr {
+			ctx.validate(s.Items, item, append(path, ind))
+		}
+	case "string":
+		switch value.(type) {
+		case string:
+		case json.Number:
+			if s.Format == quantityFormat || (s.Format == intOrStringFormat && isInteger(value)) {
+				return
+			}
+			ctx.addError(path, "expected string, got %s", valueType(value))
+		default:
+			ctx.addError(path, "expected string, got %s", valueType(value))
+		}
+	case "integer":
+		if s.IntOrString {
+			if _, ok := value.(string); ok {
+				return
+			}
+		}
+		if !isInteger(value) {
+			ctx.addError(path, "expected integer, got %s", valueType(value))
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			ctx.addError(path, "expected number, got %s", valueType(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			ctx.addError(path, "expected boolean, got %s", valueType(value))
+		}
+	default:
+		if s.IntOrString {
+			switch value.(type) {
+			case string:
+			case json.Number:
+				if !isInteger(value) {
+					ctx.addError(path, "expected integer or string, got %s", valueType(value))
+				}
+			default:
+				ctx.addError(path, "expected integer or string, got %s", valueType(value))
+			}
+		}
+	}
+}
+
+func (ctx *validation) validateObject(s *Schema, obj map[string]interface{}, path []interface{}) {
+	for _, field := range s.Required {
+		if _, ok := obj[field]; !ok {
+			ctx.addError(path, "missing required field \"%s\"", field)
+		}
+	}
+
+	var fields []string
+	for field := range obj {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		fieldPath := append(path, field)
+
+		if fieldSchema, ok := s.Properties[field]; ok {
+			ctx.validate(fieldSchema, obj[field], fieldPath)
+		} else if s.AdditionalProperties != nil {
+			ctx.validate(s.AdditionalProperties, obj[field], fieldPath)
+		} else if s.NoAdditionalProperties || (ctx.strict && len(s.Properties) > 0 && !s.PreserveUnknownFields) {
+			ctx.addError(fieldPath, "unknown field")
+		}
+	}
+}
+
+func isEnumValue(enum []interface{}, value interface{}) bool {
+	for _, enumValue := range enum {
+		if formatValue(enumValue) == formatValue(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isInteger(value interface{}) bool {
+	number, ok := value.(json.Number)
+	if !ok {
+		return false
+	}
+
+	_, err := number.Int64()
+	return err == nil
+}
+
+func valueType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if isInteger(value) {
+			return "integer"
+		}
+		return "number"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,166 @@
+package schema
+
+import (
+	"testing"
+)
+
+const testDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: app
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: app
+  template:
+    metadata:
+      labels:
+        app: app
+    spec:
+      containers:
+      - name: app
+        image: REPO:DOCKER_TAG
+        ports:
+        - containerPort: 80
+        resources:
+          limits:
+            cpu: 1
+            memory: 128Mi
+        livenessProbe:
+          httpGet:
+            path: /
+            port: http
+`
+
+const testCRD = `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.example.com
+spec:
+  group: example.com
+  version: v1
+  names:
+    kind: CronTab
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          required: [cronSpec]
+          properties:
+            cronSpec:
+              type: string
+            replicas:
+              type: integer
+`
+
+func TestValidator_Validate(t *testing.T) {
+	v, err := NewValidator("1.13")
+	if err != nil {
+		t.Fatalf("cannot create validator: %s", err)
+	}
+
+	crd, err := DecodeObject([]byte(testCRD))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.AddCRD(crd); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+		errors   []string
+	}{
+		{
+			name:     "valid",
+			manifest: testDeployment,
+		},
+		{
+			name:     "unknown_field",
+			manifest: testDeployment + "  replica: 1\n",
+			errors:   []string{"spec.replica: unknown field"},
+		},
+		{
+			name: "wrong_type",
+			manifest: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  annotations:
+    enabled: true
+data:
+  port: 80
+`,
+			errors: []string{
+				"data.port: expected string, got integer",
+				"metadata.annotations.enabled: expected string, got boolean",
+			},
+		},
+		{
+			name: "missing_required",
+			manifest: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+spec:
+  containers:
+  - image: nginx
+    ports:
+    - containerPort: "80"
+`,
+			errors: []string{
+				"spec.containers[0]: missing required field \"name\"",
+				"spec.containers[0].ports[0].containerPort: expected integer, got string",
+			},
+		},
+		{
+			name: "custom_resource",
+			manifest: `
+apiVersion: example.com/v1
+kind: CronTab
+metadata:
+  name: crontab
+  label: wrong
+spec:
+  replicas: one
+  image: nginx
+`,
+			errors: []string{
+				"metadata.label: unknown field",
+				"spec: missing required field \"cronSpec\"",
+				"spec.replicas: expected integer, got string",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, err := DecodeObject([]byte(tt.manifest))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var errors []string
+			for _, err := range v.Validate(obj) {
+				errors = append(errors, err.Error())
+			}
+
+			if len(errors) != len(tt.errors) {
+				t.Fatalf("expected errors %q, got %q", tt.errors, errors)
+			}
+			for ind := range errors {
+				if errors[ind] != tt.errors[ind] {
+					t.Errorf("expected errors %q, got %q", tt.errors, errors)
+				}
+			}
+		})
+	}
+}
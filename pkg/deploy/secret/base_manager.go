@@ -8,8 +8,9 @@ import (
 )
 
 type BaseManager struct {
-	generateFunc func([]byte) ([]byte, error)
-	extractFunc  func([]byte) ([]byte, error)
+	generateFunc  func([]byte) ([]byte, error)
+	extractFunc   func([]byte) ([]byte, error)
+	isCurrentFunc func([]byte) bool
 }
 
 func newBaseManager(ss secret.Secret) (Manager, error) {
@@ -23,6 +24,12 @@ func newBaseManager(ss secret.Secret) (Manager, error) {
 		s.extractFunc = doNothing
 	}
 
+	if cs, ok := ss.(interface{ IsCurrentData([]byte) bool }); ok {
+		s.isCurrentFunc = cs.IsCurrentData
+	} else {
+		s.isCurrentFunc = secret.IsVersionedData
+	}
+
 	return s, nil
 }
 
@@ -72,6 +79,45 @@ func (s *BaseManager) ExtractYamlData(data []byte) ([]byte, error) {
 	return resultData, nil
 }
 
+func (s *BaseManager) Migrate(data []byte) ([]byte, error) {
+	resultData, err := s.migrate(data)
+	if err != nil {
+		if secret.IsExtractDataError(err) {
+			return nil, fmt.Errorf("migration failed: check data `%s`: %s", string(data), err)
+		}
+
+		return nil, fmt.Errorf("migration failed: check encryption key and data: %s", err)
+	}
+
+	return resultData, nil
+}
+
+func (s *BaseManager) MigrateYamlData(data []byte) ([]byte, error) {
+	resultData, err := doYamlData(s.migrate, data)
+	if err != nil {
+		if secret.IsExtractDataError(err) {
+			return nil, fmt.Errorf("migration failed: check data `%s`: %s", string(data), err)
+		}
+
+		return nil, fmt.Errorf("migration failed: check encryption key and data: %s", err)
+	}
+
+	return resultData, nil
+}
+
+func (s *BaseManager) migrate(data []byte) ([]byte, error) {
+	if len(data) == 0 || s.isCurrentFunc(data) {
+		return data, nil
+	}
+
+	decodedData, err := s.extractFunc(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.generateFunc(decodedData)
+}
+
 func doYamlData(doFunc func([]byte) ([]byte, error), data []byte) ([]byte, error) {
 	config := make(yaml.MapSlice, 0)
 	err := yaml.Unmarshal(data, &config)
@@ -0,0 +1,158 @@
+package secret
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ValueAdded   = "+"
+	ValueRemoved = "-"
+	ValueChanged = "~"
+
+	maskedValue = "***"
+)
+
+type ValueChange struct {
+	Path     string
+	Type     string
+	OldValue string
+	NewValue string
+}
+
+type flatValue struct {
+	Path  string
+	Value string
+}
+
+// ValuesDiff returns key level changes between decoded secret values data, order of new data is kept.
+func ValuesDiff(oldData, newData []byte) ([]*ValueChange, error) {
+	oldValues, err := flattenYamlValues(oldData)
+	if err != nil {
+		return nil, err
+	}
+
+	newValues, err := flattenYamlValues(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	oldIndex := make(map[string]string)
+	for _, v := range oldValues {
+		oldIndex[v.Path] = v.Value
+	}
+
+	newIndex := make(map[string]bool)
+
+	var changes []*ValueChange
+	for _, v := range newValues {
+		newIndex[v.Path] = true
+
+		oldValue, exist := oldIndex[v.Path]
+		if !exist {
+			changes = append(changes, &ValueChange{Path: v.Path, Type: ValueAdded, NewValue: v.Value})
+		} else if oldValue != v.Value {
+			changes = append(changes, &ValueChange{Path: v.Path, Type: ValueChanged, OldValue: oldValue, NewValue: v.Value})
+		}
+	}
+
+	for _, v := range oldValues {
+		if !newIndex[v.Path] {
+			changes = append(changes, &ValueChange{Path: v.Path, Type: ValueRemoved, OldValue: v.Value})
+		}
+	}
+
+	return changes, nil
+}
+
+// FileDiff returns change of decoded secret file data.
+func FileDiff(oldData, newData []byte, oldExist, newExist bool) []*ValueChange {
+	switch {
+	case !oldExist && newExist:
+		return []*ValueChange{{Type: ValueAdded, NewValue: string(newData)}}
+	case oldExist && !newExist:
+		return []*ValueChange{{Type: ValueRemoved, OldValue: string(oldData)}}
+	case string(oldData) != string(newData):
+		return []*ValueChange{{Type: ValueChanged, OldValue: string(oldData), NewValue: string(newData)}}
+	}
+
+	return nil
+}
+
+// PrintValuesDiff prints changes of the file, values are masked unless plain is specified.
+func PrintValuesDiff(out io.Writer, filePath string, changes []*ValueChange, plain bool) {
+	if len(changes) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "# %s\n", filePath)
+
+	formatValue := func(value string) string {
+		if !plain {
+			return maskedValue
+		}
+
+		if strings.Contains(value, "\n") {
+			return "|\n    " + strings.Replace(strings.TrimRight(value, "\n"), "\n", "\n    ", -1)
+		}
+
+		return value
+	}
+
+	for _, change := range changes {
+		path := change.Path
+		if path == "" {
+			path = "<file>"
+		}
+
+		switch change.Type {
+		case ValueAdded:
+			fmt.Fprintf(out, "%s %s: %s\n", change.Type, path, formatValue(change.NewValue))
+		case ValueRemoved:
+			fmt.Fprintf(out, "%s %s: %s\n", change.Type, path, formatValue(change.OldValue))
+		case ValueChanged:
+			if plain {
+				fmt.Fprintf(out, "%s %s: %s -> %s\n", change.Type, path, formatValue(change.OldValue), formatValue(change.NewValue))
+			} else {
+				fmt.Fprintf(out, "%s %s: %s\n", change.Type, path, maskedValue)
+			}
+		}
+	}
+
+	fmt.Fprintln(out)
+}
+
+func flattenYamlValues(data []byte) ([]*flatValue, error) {
+	config := make(yaml.MapSlice, 0)
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	var res []*flatValue
+	flattenYamlValue(config, "", &res)
+
+	return res, nil
+}
+
+func flattenYamlValue(data interface{}, path string, res *[]*flatValue) {
+	switch value := data.(type) {
+	case yaml.MapSlice:
+		for _, elm := range value {
+			elmPath := fmt.Sprintf("%v", elm.Key)
+			if path != "" {
+				elmPath = path + "." + elmPath
+			}
+
+			flattenYamlValue(elm.Value, elmPath, res)
+		}
+	case []interface{}:
+		for ind, elm := range value {
+			flattenYamlValue(elm, fmt.Sprintf("%s[%d]", path, ind), res)
+		}
+	default:
+		*res = append(*res, &flatValue{Path: path, Value: fmt.Sprintf("%v", value)})
+	}
+}
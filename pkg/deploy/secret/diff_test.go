@@ -0,0 +1,52 @@
+package secret
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestValuesDiff(t *testing.T) {
+	oldData := []byte(`db:
+  user: app
+  password: qwerty
+hosts:
+- a
+- b
+token: old
+`)
+
+	newData := []byte(`db:
+  user: app
+  password: asdfgh
+hosts:
+- a
+- c
+- d
+`)
+
+	changes, err := ValuesDiff(oldData, newData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*ValueChange{
+		{Path: "db.password", Type: ValueChanged, OldValue: "qwerty", NewValue: "asdfgh"},
+		{Path: "hosts[1]", Type: ValueChanged, OldValue: "b", NewValue: "c"},
+		{Path: "hosts[2]", Type: ValueAdded, NewValue: "d"},
+		{Path: "token", Type: ValueRemoved, OldValue: "old"},
+	}
+
+	if !reflect.DeepEqual(changes, expected) {
+		for _, change := range changes {
+			t.Logf("%#v", change)
+		}
+		t.Fatalf("unexpected changes")
+	}
+
+	buf := bytes.NewBuffer(nil)
+	PrintValuesDiff(buf, ".helm/secret-values.yaml", changes, false)
+	if bytes.Contains(buf.Bytes(), []byte("qwerty")) || bytes.Contains(buf.Bytes(), []byte("asdfgh")) {
+		t.Errorf("values are not masked:\n%s", buf.String())
+	}
+}
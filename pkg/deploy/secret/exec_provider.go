@@ -0,0 +1,45 @@
+package secret
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/ghodss/yaml"
+)
+
+// ExecProvider runs shell command which prints secret values yaml to stdout.
+type ExecProvider struct {
+	name    string
+	command string
+	workDir string
+}
+
+func NewExecProvider(name, command, workDir string) *ExecProvider {
+	return &ExecProvider{name: name, command: command, workDir: workDir}
+}
+
+func (p *ExecProvider) Name() string {
+	return p.name
+}
+
+func (p *ExecProvider) GetValues() (map[string]interface{}, error) {
+	var stdout bytes.Buffer
+
+	cmd := exec.Command("sh", "-c", p.command)
+	cmd.Dir = p.workDir
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("command `%s` failed: %s", p.command, err)
+	}
+
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(stdout.Bytes(), &values); err != nil {
+		return nil, fmt.Errorf("command `%s` output is not values yaml: %s", p.command, err)
+	}
+
+	return values, nil
+}
@@ -19,6 +19,10 @@ type Manager interface {
 
 	GenerateYamlData(data []byte) ([]byte, error)
 	ExtractYamlData(encodedData []byte) ([]byte, error)
+
+	// Migrate re-encrypts legacy data into the versioned format, versioned data is kept as is
+	Migrate(encodedData []byte) ([]byte, error)
+	MigrateYamlData(encodedData []byte) ([]byte, error)
 }
 
 func GenerateSecretKey() ([]byte, error) {
@@ -26,100 +30,221 @@ func GenerateSecretKey() ([]byte, error) {
 }
 
 func GetManager(projectDir string) (Manager, error) {
-	var m Manager
-	var key []byte
-	var err error
+	return GetManagerWithOptions(projectDir, NewManagerOptions{})
+}
 
-	key, err = GetSecretKey(projectDir)
-	if err != nil {
+func GetManagerWithOptions(projectDir string, options NewManagerOptions) (Manager, error) {
+	if isRecipientsMode, err := IsRecipientsMode(projectDir); err != nil {
 		return nil, err
+	} else if isRecipientsMode {
+		recipients, err := GetRecipients(projectDir)
+		if err != nil {
+			return nil, err
+		}
+
+		return newRecipientsManager(projectDir, recipients, options)
 	}
 
-	m, err = NewManager(key, NewManagerOptions{})
+	keys, err := GetSecretKeys(projectDir)
 	if err != nil {
 		return nil, err
 	}
 
-	return m, nil
+	return NewKeyRingManager(keys, options)
 }
 
+// GetSecretKey returns the primary key of the key ring.
 func GetSecretKey(projectDir string) ([]byte, error) {
-	var secretKey []byte
-	var dappSecretKeyPaths []string
+	keys, err := GetSecretKeys(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys[0], nil
+}
+
+// GetSecretKeys returns key ring from $DAPP_SECRET_KEYS, single key from $DAPP_SECRET_KEY,
+// key ring from keyring files or single key from .dapp_secret_key files: environment variables take precedence over files.
+// The first key of the key ring is primary: data is encrypted with the primary key, other keys are used to decrypt data.
+func GetSecretKeys(projectDir string) ([][]byte, error) {
 	var notFoundIn []string
 
-	secretKey = []byte(os.Getenv("DAPP_SECRET_KEY"))
-	if len(secretKey) == 0 {
-		notFoundIn = append(notFoundIn, "$DAPP_SECRET_KEY")
+	if value := os.Getenv("DAPP_SECRET_KEYS"); value != "" {
+		return parseSecretKeys(value), nil
+	}
+	notFoundIn = append(notFoundIn, "$DAPP_SECRET_KEYS")
+
+	if value := os.Getenv("DAPP_SECRET_KEY"); value != "" {
+		return [][]byte{[]byte(value)}, nil
+	}
+	notFoundIn = append(notFoundIn, "$DAPP_SECRET_KEY")
 
-		var dappSecretKeyPath string
+	var keyRingPaths []string
+	if path := os.Getenv("DAPP_SECRET_KEYRING_FILE"); path != "" {
+		keyRingPaths = append(keyRingPaths, path)
+	} else {
+		notFoundIn = append(notFoundIn, "$DAPP_SECRET_KEYRING_FILE")
+	}
+
+	projectKeyRingPath, err := filepath.Abs(filepath.Join(projectDir, ".dapp_secret_keyring"))
+	if err != nil {
+		return nil, err
+	}
+	keyRingPaths = append(keyRingPaths, projectKeyRingPath, filepath.Join(dapp.GetHomeDir(), "secret_keyring"))
 
-		projectDappSecretKeyPath, err := filepath.Abs(filepath.Join(projectDir, ".dapp_secret_key"))
+	for _, path := range keyRingPaths {
+		exist, err := file.FileExists(path)
 		if err != nil {
 			return nil, err
 		}
 
-		homeDappSecretKeyPath := filepath.Join(dapp.GetHomeDir(), ".dapp_secret_key")
-
-		dappSecretKeyPaths = []string{
-			projectDappSecretKeyPath,
-			homeDappSecretKeyPath,
+		if !exist {
+			notFoundIn = append(notFoundIn, path)
+			continue
 		}
 
-		for _, path := range dappSecretKeyPaths {
-			exist, err := file.FileExists(path)
-			if err != nil {
-				return nil, err
-			}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
 
-			if exist {
-				dappSecretKeyPath = path
-				break
-			} else {
-				notFoundIn = append(notFoundIn, fmt.Sprintf("%s", path))
-			}
+		keys := parseSecretKeys(string(data))
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("no keys in key ring file %s", path)
 		}
 
-		if dappSecretKeyPath != "" {
-			data, err := ioutil.ReadFile(dappSecretKeyPath)
-			if err != nil {
-				return nil, err
-			}
+		return keys, nil
+	}
 
-			secretKey = []byte(strings.TrimSpace(string(data)))
-		}
+	secretKey, keyNotFoundIn, err := getSecretKeyFromFiles(projectDir)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(secretKey) == 0 {
+		notFoundIn = append(notFoundIn, keyNotFoundIn...)
 		return nil, fmt.Errorf("encryption key not found in: '%s'", strings.Join(notFoundIn, "', '"))
 	}
 
-	return secretKey, nil
+	return [][]byte{secretKey}, nil
+}
+
+// parseSecretKeys parses keys separated by commas, spaces or new lines, lines starting with # are comments.
+func parseSecretKeys(value string) [][]byte {
+	var keys [][]byte
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for _, key := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			keys = append(keys, []byte(key))
+		}
+	}
+
+	return keys
+}
+
+func getSecretKeyFromFiles(projectDir string) ([]byte, []string, error) {
+	var notFoundIn []string
+
+	projectDappSecretKeyPath, err := filepath.Abs(filepath.Join(projectDir, ".dapp_secret_key"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	homeDappSecretKeyPath := filepath.Join(dapp.GetHomeDir(), ".dapp_secret_key")
+
+	for _, path := range []string{projectDappSecretKeyPath, homeDappSecretKeyPath} {
+		exist, err := file.FileExists(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !exist {
+			notFoundIn = append(notFoundIn, path)
+			continue
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return []byte(strings.TrimSpace(string(data))), nil, nil
+	}
+
+	return nil, notFoundIn, nil
 }
 
 type NewManagerOptions struct {
 	IgnoreWarning bool
+	// LegacyKey is used to decrypt legacy data without key id instead of the primary key
+	LegacyKey []byte
 }
 
 func NewManager(key []byte, options NewManagerOptions) (Manager, error) {
-	ss, err := secret.NewSecret(key)
+	return NewKeyRingManager([][]byte{key}, options)
+}
+
+func NewKeyRingManager(keys [][]byte, options NewManagerOptions) (Manager, error) {
+	ss, err := newKeyRingSecret(keys, options)
 	if err != nil {
-		if strings.HasPrefix(err.Error(), "encoding/hex:") {
-			if !options.IgnoreWarning {
-				logger.LogWarning(`
+		return nil, err
+	}
+
+	return newBaseManager(ss)
+}
+
+func newKeyRingSecret(keys [][]byte, options NewManagerOptions) (*secret.KeyRingSecret, error) {
+	var preparedKeys [][]byte
+	for _, key := range keys {
+		preparedKey, err := prepareSecretKey(key, options)
+		if err != nil {
+			return nil, err
+		}
+
+		preparedKeys = append(preparedKeys, preparedKey)
+	}
+
+	ss, err := secret.NewKeyRingSecret(preparedKeys)
+	if err != nil {
+		return nil, fmt.Errorf("check encryption key: %s", err)
+	}
+
+	if len(options.LegacyKey) != 0 {
+		legacyKey, err := prepareSecretKey(options.LegacyKey, options)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := ss.SetLegacyKey(legacyKey); err != nil {
+			return nil, fmt.Errorf("check legacy encryption key: %s", err)
+		}
+	}
+
+	return ss, nil
+}
+
+func prepareSecretKey(key []byte, options NewManagerOptions) ([]byte, error) {
+	if _, err := secret.NewAesSecret(key); err != nil {
+		if !strings.HasPrefix(err.Error(), "encoding/hex:") {
+			return nil, fmt.Errorf("check encryption key: %s", err)
+		}
+
+		if !options.IgnoreWarning {
+			logger.LogWarning(`
 ###################################################################################################
 ###                       WARNING invalid encryption key, do regenerate!                        ###
 ### https://flant.github.io/dapp/reference/deploy/secrets.html#regeneration-of-existing-secrets ###
 ###################################################################################################`)
-			}
-
-			return NewManager(ruby2GoSecretKey(key), options)
 		}
 
-		return nil, fmt.Errorf("check encryption key: %s", err)
+		return ruby2GoSecretKey(key), nil
 	}
 
-	return newBaseManager(ss)
+	return key, nil
 }
 
 func ruby2GoSecretKey(key []byte) []byte {
@@ -0,0 +1,77 @@
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flant/dapp/pkg/dapp"
+)
+
+func TestGetSecretKeys(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "dapp-secret-keys-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	homeDir := filepath.Join(tmpDir, "home")
+	projectDir := filepath.Join(tmpDir, "project")
+	for _, dir := range []string{homeDir, projectDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := dapp.Init(tmpDir, homeDir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"DAPP_SECRET_KEYS", "DAPP_SECRET_KEY", "DAPP_SECRET_KEYRING_FILE"} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Unsetenv(name)
+	}
+
+	if _, err := GetSecretKeys(projectDir); err == nil {
+		t.Errorf("\n[EXPECTED]: key not found error\n[GOT]: no error")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(projectDir, ".dapp_secret_key"), []byte("key-file\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectKeys(t, projectDir, "key-file")
+
+	if err := ioutil.WriteFile(filepath.Join(projectDir, ".dapp_secret_keyring"), []byte("# rotation\nkeyring-new\nkeyring-old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectKeys(t, projectDir, "keyring-new", "keyring-old")
+
+	os.Setenv("DAPP_SECRET_KEY", "key-env")
+	expectKeys(t, projectDir, "key-env")
+
+	os.Setenv("DAPP_SECRET_KEYS", "keys-env-new,keys-env-old")
+	expectKeys(t, projectDir, "keys-env-new", "keys-env-old")
+}
+
+func expectKeys(t *testing.T, projectDir string, expected ...string) {
+	keys, err := GetSecretKeys(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, key := range keys {
+		got = append(got, string(key))
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("\n[EXPECTED]: %v\n[GOT]: %v", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v", expected, got)
+			break
+		}
+	}
+}
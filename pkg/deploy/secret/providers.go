@@ -0,0 +1,117 @@
+package secret
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+const ProvidersConfigFileName = "secret-providers.yaml"
+
+// SecretValuesProvider gives secret values of the chart from an external secret store at deploy time.
+type SecretValuesProvider interface {
+	Name() string
+	GetValues() (map[string]interface{}, error)
+}
+
+type ProviderConfig struct {
+	Name string `json:"name"`
+	// ValuesKey is a dot separated path values of provider are placed under, values are placed at top level by default
+	ValuesKey string `json:"valuesKey,omitempty"`
+
+	Exec  string               `json:"exec,omitempty"`
+	Vault *VaultProviderConfig `json:"vault,omitempty"`
+}
+
+// ProvidersConfig declares providers, values of later providers override values of earlier ones.
+type ProvidersConfig struct {
+	Providers []*ProviderConfig `json:"providers"`
+}
+
+// GetProviders returns providers declared in secret-providers.yaml of the chart dir in the declared order.
+func GetProviders(chartDir, workDir string) ([]SecretValuesProvider, error) {
+	path := filepath.Join(chartDir, ProvidersConfigFileName)
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading %s: %s", path, err)
+	}
+
+	var cfg ProvidersConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("bad secret providers config %s: %s", path, err)
+	}
+
+	var providers []SecretValuesProvider
+	names := make(map[string]bool)
+	for ind, providerCfg := range cfg.Providers {
+		if providerCfg.Name == "" {
+			providerCfg.Name = fmt.Sprintf("provider-%d", ind)
+		}
+
+		if names[providerCfg.Name] {
+			return nil, fmt.Errorf("bad secret providers config %s: provider '%s' is declared more than once", path, providerCfg.Name)
+		}
+		names[providerCfg.Name] = true
+
+		provider, err := newProvider(providerCfg, workDir)
+		if err != nil {
+			return nil, fmt.Errorf("bad secret providers config %s: provider '%s': %s", path, providerCfg.Name, err)
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
+
+func newProvider(cfg *ProviderConfig, workDir string) (SecretValuesProvider, error) {
+	var provider SecretValuesProvider
+
+	switch {
+	case cfg.Exec != "" && cfg.Vault != nil:
+		return nil, fmt.Errorf("only one of exec or vault can be specified")
+	case cfg.Exec != "":
+		provider = NewExecProvider(cfg.Name, strings.TrimPrefix(cfg.Exec, "exec:"), workDir)
+	case cfg.Vault != nil:
+		vaultProvider, err := NewVaultProvider(cfg.Name, *cfg.Vault)
+		if err != nil {
+			return nil, err
+		}
+		provider = vaultProvider
+	default:
+		return nil, fmt.Errorf("exec or vault should be specified")
+	}
+
+	if cfg.ValuesKey != "" {
+		return &valuesKeyProvider{SecretValuesProvider: provider, valuesKey: cfg.ValuesKey}, nil
+	}
+
+	return provider, nil
+}
+
+type valuesKeyProvider struct {
+	SecretValuesProvider
+
+	valuesKey string
+}
+
+func (p *valuesKeyProvider) GetValues() (map[string]interface{}, error) {
+	values, err := p.SecretValuesProvider.GetValues()
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(p.valuesKey, ".")
+	for ind := len(parts) - 1; ind >= 0; ind-- {
+		values = map[string]interface{}{parts[ind]: values}
+	}
+
+	return values, nil
+}
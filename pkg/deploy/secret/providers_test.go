@@ -0,0 +1,157 @@
+package secret
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newVaultStub(token string, secrets map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		response, ok := secrets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+
+		w.Write([]byte(response))
+	}))
+}
+
+func TestVaultProvider_GetValues(t *testing.T) {
+	server := newVaultStub("s.token", map[string]string{
+		"/v1/secret/data/myapp/production": `{"data":{"data":{"password":"qwerty","db":{"user":"app"}},"metadata":{"version":3}}}`,
+		"/v1/kv/myapp":                     `{"data":{"password":"asdfgh"}}`,
+	})
+	defer server.Close()
+
+	os.Setenv("DAPP_TEST_VAULT_TOKEN", "s.token")
+	defer os.Unsetenv("DAPP_TEST_VAULT_TOKEN")
+
+	tests := []struct {
+		name     string
+		config   VaultProviderConfig
+		expected map[string]interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "kv2",
+			config:   VaultProviderConfig{Path: "myapp/production"},
+			expected: map[string]interface{}{"password": "qwerty", "db": map[string]interface{}{"user": "app"}},
+		},
+		{
+			name:     "kv1",
+			config:   VaultProviderConfig{Mount: "kv", Path: "myapp", KVVersion: 1},
+			expected: map[string]interface{}{"password": "asdfgh"},
+		},
+		{
+			name:    "not_found",
+			config:  VaultProviderConfig{Path: "myapp/staging"},
+			wantErr: true,
+		},
+		{
+			name:    "permission_denied",
+			config:  VaultProviderConfig{Path: "myapp/production", TokenEnv: "DAPP_TEST_VAULT_BAD_TOKEN"},
+			wantErr: true,
+		},
+	}
+
+	os.Setenv("DAPP_TEST_VAULT_BAD_TOKEN", "s.bad")
+	defer os.Unsetenv("DAPP_TEST_VAULT_BAD_TOKEN")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := test.config
+			config.Address = server.URL
+			if config.TokenEnv == "" {
+				config.TokenEnv = "DAPP_TEST_VAULT_TOKEN"
+			}
+
+			provider, err := NewVaultProvider(test.name, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			values, err := provider.GetValues()
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got values %#v", values)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(values, test.expected) {
+				t.Errorf("\n[EXPECTED]: %#v\n[GOT]: %#v", test.expected, values)
+			}
+		})
+	}
+}
+
+func TestGetProviders(t *testing.T) {
+	server := newVaultStub("s.token", map[string]string{
+		"/v1/secret/data/myapp": `{"data":{"data":{"password":"qwerty"}}}`,
+	})
+	defer server.Close()
+
+	os.Setenv("DAPP_TEST_VAULT_TOKEN", "s.token")
+	defer os.Unsetenv("DAPP_TEST_VAULT_TOKEN")
+
+	dir, err := ioutil.TempDir("", "dapp-secret-providers-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := `providers:
+- name: app
+  exec: "exec:printf 'app:\n  key: value\n'"
+- name: vault
+  valuesKey: global.db
+  vault:
+    address: ` + server.URL + `
+    path: myapp
+    tokenEnv: DAPP_TEST_VAULT_TOKEN
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, ProvidersConfigFileName), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	providers, err := GetProviders(dir, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []map[string]interface{}{
+		{"app": map[string]interface{}{"key": "value"}},
+		{"global": map[string]interface{}{"db": map[string]interface{}{"password": "qwerty"}}},
+	}
+
+	if len(providers) != len(expected) {
+		t.Fatalf("expected %d providers, got %d", len(expected), len(providers))
+	}
+
+	for ind, provider := range providers {
+		values, err := provider.GetValues()
+		if err != nil {
+			t.Fatalf("provider %s: %s", provider.Name(), err)
+		}
+
+		if !reflect.DeepEqual(values, expected[ind]) {
+			t.Errorf("provider %s:\n[EXPECTED]: %#v\n[GOT]: %#v", provider.Name(), expected[ind], values)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package secret
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/secret"
+)
+
+const (
+	// ProjectRecipientsFile lists public keys secrets are encrypted to, one "PUBLIC_KEY [NAME]" per line
+	ProjectRecipientsFile = ".dapp_secret_recipients"
+
+	projectIdentityFile = ".dapp_secret_identity"
+	homeIdentityFile    = "secret_identity"
+)
+
+type Recipient struct {
+	*secret.X25519Recipient
+	Name string
+}
+
+func GenerateIdentity() (*secret.X25519Identity, error) {
+	return secret.GenerateX25519Identity()
+}
+
+func IsRecipientsMode(projectDir string) (bool, error) {
+	_, err := os.Stat(filepath.Join(projectDir, ProjectRecipientsFile))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func GetRecipients(projectDir string) ([]*Recipient, error) {
+	path := filepath.Join(projectDir, ProjectRecipientsFile)
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading %s: %s", path, err)
+	}
+
+	var recipients []*Recipient
+	for ind, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+
+		r, err := secret.ParseX25519Recipient(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", path, ind+1, err)
+		}
+
+		recipients = append(recipients, &Recipient{X25519Recipient: r, Name: strings.Join(fields[1:], " ")})
+	}
+
+	return recipients, nil
+}
+
+func SaveRecipients(projectDir string, recipients []*Recipient) error {
+	buf := bytes.NewBufferString("# Public keys dapp secrets are encrypted to (dapp secret recipient add/remove)\n")
+	for _, r := range recipients {
+		if r.Name != "" {
+			fmt.Fprintf(buf, "%s %s\n", r.X25519Recipient, r.Name)
+		} else {
+			fmt.Fprintf(buf, "%s\n", r.X25519Recipient)
+		}
+	}
+
+	path := filepath.Join(projectDir, ProjectRecipientsFile)
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %s", path, err)
+	}
+
+	return nil
+}
+
+// GetIdentities returns private keys from $DAPP_SECRET_IDENTITY, $DAPP_SECRET_IDENTITY_FILE,
+// PROJECT_DIR/.dapp_secret_identity or ~/.dapp/secret_identity.
+func GetIdentities(projectDir string) ([]*secret.X25519Identity, error) {
+	if value := os.Getenv("DAPP_SECRET_IDENTITY"); value != "" {
+		return secret.ParseX25519Identities([]byte(value))
+	}
+
+	notFoundIn := []string{"$DAPP_SECRET_IDENTITY"}
+
+	var paths []string
+	if path := os.Getenv("DAPP_SECRET_IDENTITY_FILE"); path != "" {
+		paths = append(paths, path)
+	} else {
+		notFoundIn = append(notFoundIn, "$DAPP_SECRET_IDENTITY_FILE")
+	}
+
+	projectIdentityPath, err := filepath.Abs(filepath.Join(projectDir, projectIdentityFile))
+	if err != nil {
+		return nil, err
+	}
+	paths = append(paths, projectIdentityPath, filepath.Join(dapp.GetHomeDir(), homeIdentityFile))
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			notFoundIn = append(notFoundIn, path)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		identities, err := secret.ParseX25519Identities(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		return identities, nil
+	}
+
+	return nil, fmt.Errorf("decryption identity not found in: '%s'", strings.Join(notFoundIn, "', '"))
+}
+
+// NewRecipientsManager returns manager which encrypts data to recipients.
+// Data encrypted to recipients is decrypted with identities, data encrypted with project secret key is decrypted with the key if available.
+func NewRecipientsManager(projectDir string, recipients []*Recipient) (Manager, error) {
+	return newRecipientsManager(projectDir, recipients, NewManagerOptions{})
+}
+
+func newRecipientsManager(projectDir string, recipients []*Recipient, options NewManagerOptions) (Manager, error) {
+	ss := &secret.RecipientsSecret{}
+	for _, r := range recipients {
+		ss.Recipients = append(ss.Recipients, r.X25519Recipient)
+	}
+
+	identities, err := GetIdentities(projectDir)
+	if err != nil {
+		if !strings.HasPrefix(err.Error(), "decryption identity not found in") {
+			return nil, err
+		}
+		ss.IdentityNotFoundErr = err
+	}
+	ss.Identities = identities
+
+	if keys, err := GetSecretKeys(projectDir); err == nil {
+		fallback, err := newKeyRingSecret(keys, options)
+		if err != nil {
+			return nil, err
+		}
+		ss.Fallback = fallback
+	}
+
+	return newBaseManager(ss)
+}
@@ -0,0 +1,62 @@
+package secret
+
+import (
+	"github.com/flant/dapp/pkg/secret"
+)
+
+const (
+	LegacyDataKeyId     = "legacy"
+	RecipientsDataKeyId = "recipients"
+)
+
+// ValueKeyId is a key id of the secret value, path is empty for secret file data.
+type ValueKeyId struct {
+	Path  string
+	KeyId string
+}
+
+// GetPrimaryKeyId returns key id of the primary key of the project key ring.
+func GetPrimaryKeyId(projectDir string) (string, error) {
+	keys, err := GetSecretKeys(projectDir)
+	if err != nil {
+		return "", err
+	}
+
+	ss, err := newKeyRingSecret(keys, NewManagerOptions{IgnoreWarning: true})
+	if err != nil {
+		return "", err
+	}
+
+	return ss.PrimaryKeyId(), nil
+}
+
+// DataKeyId returns key id of encrypted data, legacy data without key id and data encrypted to recipients are labeled.
+func DataKeyId(data []byte) string {
+	switch {
+	case secret.IsRecipientsData(data):
+		return RecipientsDataKeyId
+	case secret.IsVersionedData(data):
+		return secret.DataKeyId(data)
+	default:
+		return LegacyDataKeyId
+	}
+}
+
+// YamlDataKeyIds returns key ids of all values of secret values data.
+func YamlDataKeyIds(data []byte) ([]*ValueKeyId, error) {
+	values, err := flattenYamlValues(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*ValueKeyId
+	for _, v := range values {
+		if v.Value == "" {
+			continue
+		}
+
+		res = append(res, &ValueKeyId{Path: v.Path, KeyId: DataKeyId([]byte(v.Value))})
+	}
+
+	return res, nil
+}
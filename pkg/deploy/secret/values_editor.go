@@ -0,0 +1,323 @@
+package secret
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Secret values file is edited line by line to keep comments, key order and ciphertexts of other values.
+// Only nested maps are supported: values in block style with a key per line.
+
+// ParseValuePath splits dot separated path, dot in the key is escaped with backslash.
+func ParseValuePath(s string) ([]string, error) {
+	var path []string
+	var key []rune
+	var escaped bool
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			key = append(key, r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '.':
+			path = append(path, string(key))
+			key = nil
+		default:
+			key = append(key, r)
+		}
+	}
+	path = append(path, string(key))
+
+	for _, key := range path {
+		if key == "" {
+			return nil, fmt.Errorf("bad value path '%s': empty key", s)
+		}
+	}
+
+	return path, nil
+}
+
+// GetValue returns decoded value, map value is returned as decoded yaml.
+func GetValue(m Manager, data []byte, path []string) ([]byte, error) {
+	config := make(yaml.MapSlice, 0)
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	var value interface{} = config
+	for ind, key := range path {
+		mapSlice, ok := value.(yaml.MapSlice)
+		if !ok {
+			return nil, fmt.Errorf("value '%s' is not a map", strings.Join(path[:ind], "."))
+		}
+
+		found := false
+		for _, item := range mapSlice {
+			if fmt.Sprintf("%v", item.Key) == key {
+				value, found = item.Value, true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("value '%s' not found", strings.Join(path[:ind+1], "."))
+		}
+	}
+
+	switch value.(type) {
+	case yaml.MapSlice, []interface{}:
+		valueData, err := yaml.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return m.ExtractYamlData(valueData)
+	default:
+		return m.Extract([]byte(fmt.Sprintf("%v", value)))
+	}
+}
+
+// SetValue encodes value and sets it by path creating missing maps, other lines of data are kept as is.
+func SetValue(m Manager, data []byte, path []string, value []byte) ([]byte, error) {
+	encodedValue, err := m.Generate(value)
+	if err != nil {
+		return nil, err
+	}
+
+	valueText, err := yamlScalar(string(encodedValue))
+	if err != nil {
+		return nil, err
+	}
+
+	lines := valuesLines(data)
+
+	loc, err := locateValue(lines, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if loc.Found {
+		keyLine := lines[loc.Line]
+		match := valueKeyRegexp(path[len(path)-1]).FindStringSubmatchIndex(keyLine)
+		keyPart := keyLine[:match[1]]
+
+		var comment string
+		if ind := strings.Index(keyLine[match[1]:], " #"); ind != -1 {
+			comment = keyLine[match[1]+ind:]
+		}
+
+		newLine := strings.TrimRight(keyPart, " ") + " " + valueText + comment
+		lines = replaceLines(lines, loc.Line, loc.End, []string{newLine})
+	} else {
+		var newLines []string
+		for ind, key := range path[loc.Depth:] {
+			prefix := strings.Repeat(" ", loc.Indent+2*ind)
+			keyText, err := yamlScalar(key)
+			if err != nil {
+				return nil, err
+			}
+
+			if ind == len(path[loc.Depth:])-1 {
+				newLines = append(newLines, fmt.Sprintf("%s%s: %s", prefix, keyText, valueText))
+			} else {
+				newLines = append(newLines, fmt.Sprintf("%s%s:", prefix, keyText))
+			}
+		}
+
+		lines = replaceLines(lines, loc.InsertAt, loc.InsertAt, newLines)
+	}
+
+	return joinValuesLines(lines), nil
+}
+
+// UnsetValue removes value by path, maps left empty are removed too.
+func UnsetValue(data []byte, path []string) ([]byte, error) {
+	lines := valuesLines(data)
+
+	for depth := len(path); depth > 0; depth-- {
+		loc, err := locateValue(lines, path[:depth])
+		if err != nil {
+			return nil, err
+		}
+
+		if !loc.Found {
+			if depth == len(path) {
+				return nil, fmt.Errorf("value '%s' not found", strings.Join(path, "."))
+			}
+			break
+		}
+
+		if depth < len(path) && loc.End > loc.Line+1 {
+			// Parent map is not empty
+			break
+		}
+
+		lines = replaceLines(lines, loc.Line, loc.End, nil)
+	}
+
+	return joinValuesLines(lines), nil
+}
+
+type valueLocation struct {
+	Found bool
+	// Line of the key and the end of the key value lines
+	Line, End int
+	// Depth of the first not found key, InsertAt and Indent for the key
+	Depth    int
+	InsertAt int
+	Indent   int
+}
+
+func locateValue(lines []string, path []string) (*valueLocation, error) {
+	start, end, parentIndent := 0, len(lines), -2
+
+	for depth, key := range path {
+		line, blockIndent := findValueKey(lines, start, end, key)
+		if line == -1 {
+			insertAt := start
+			for ind := start; ind < end; ind++ {
+				if isValuesContentLine(lines[ind]) {
+					insertAt = ind + 1
+				}
+			}
+
+			indent := blockIndent
+			if indent == -1 {
+				indent = parentIndent + 2
+			}
+
+			return &valueLocation{Depth: depth, InsertAt: insertAt, Indent: indent}, nil
+		}
+
+		valueEnd := findValueEnd(lines, line, end)
+
+		if depth == len(path)-1 {
+			return &valueLocation{Found: true, Line: line, End: valueEnd}, nil
+		}
+
+		match := valueKeyRegexp(key).FindStringSubmatchIndex(lines[line])
+		inlineValue := strings.TrimSpace(lines[line][match[1]:])
+		if ind := strings.Index(inlineValue, "#"); ind == 0 {
+			inlineValue = ""
+		}
+
+		switch inlineValue {
+		case "":
+		case "{}":
+			lines[line] = strings.TrimRight(lines[line][:match[1]], " ")
+		default:
+			return nil, fmt.Errorf("value '%s' is not a map", strings.Join(path[:depth+1], "."))
+		}
+
+		start, end, parentIndent = line+1, valueEnd, lineIndent(lines[line])
+	}
+
+	return nil, fmt.Errorf("empty value path")
+}
+
+// findValueKey returns line of the key in the block of lines with the same indent and the block indent.
+func findValueKey(lines []string, start, end int, key string) (int, int) {
+	keyRegexp := valueKeyRegexp(key)
+
+	blockIndent := -1
+	for ind := start; ind < end; ind++ {
+		if !isValuesContentLine(lines[ind]) {
+			continue
+		}
+
+		indent := lineIndent(lines[ind])
+		if blockIndent == -1 {
+			blockIndent = indent
+		}
+
+		if indent < blockIndent {
+			break
+		}
+
+		if indent == blockIndent && keyRegexp.MatchString(lines[ind]) {
+			return ind, blockIndent
+		}
+	}
+
+	return -1, blockIndent
+}
+
+// findValueEnd returns the line after the last line of the key value.
+func findValueEnd(lines []string, line, end int) int {
+	keyIndent := lineIndent(lines[line])
+
+	last := line
+	for ind := line + 1; ind < end; ind++ {
+		if !isValuesContentLine(lines[ind]) {
+			continue
+		}
+
+		indent := lineIndent(lines[ind])
+		isSameIndentListItem := indent == keyIndent && strings.HasPrefix(strings.TrimSpace(lines[ind]), "-")
+		if indent <= keyIndent && !isSameIndentListItem {
+			break
+		}
+
+		last = ind
+	}
+
+	return last + 1
+}
+
+func valueKeyRegexp(key string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`^\s*(?:%s|"%s"|'%s')\s*:(?:\s|$)`, regexp.QuoteMeta(key), regexp.QuoteMeta(key), regexp.QuoteMeta(key)))
+}
+
+func isValuesContentLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed != "" && !strings.HasPrefix(trimmed, "#") && trimmed != "---"
+}
+
+func lineIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func yamlScalar(value string) (string, error) {
+	if strings.Contains(value, "\n") {
+		// Block scalar indentation depends on the key indent, so double-quoted style is used
+		return strconv.Quote(value), nil
+	}
+
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+func valuesLines(data []byte) []string {
+	text := strings.TrimRight(string(data), "\n")
+	if strings.TrimSpace(text) == "{}" || strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
+
+func joinValuesLines(lines []string) []byte {
+	if len(lines) == 0 {
+		return []byte("{}\n")
+	}
+
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+func replaceLines(lines []string, start, end int, newLines []string) []string {
+	var res []string
+	res = append(res, lines[:start]...)
+	res = append(res, newLines...)
+	res = append(res, lines[end:]...)
+	return res
+}
@@ -0,0 +1,143 @@
+package secret
+
+import (
+	"testing"
+)
+
+const editorValuesData = `# Database
+db:
+  user: app # application user
+  password: qwerty
+token: abc
+`
+
+func TestSetValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		path     string
+		value    string
+		expected string
+	}{
+		{
+			name:  "existing_leaf",
+			data:  editorValuesData,
+			path:  "db.user",
+			value: "admin",
+			expected: `# Database
+db:
+  user: admin # application user
+  password: qwerty
+token: abc
+`,
+		},
+		{
+			name:  "new_nested",
+			data:  editorValuesData,
+			path:  "db.replica.host",
+			value: "10.0.0.1",
+			expected: `# Database
+db:
+  user: app # application user
+  password: qwerty
+  replica:
+    host: 10.0.0.1
+token: abc
+`,
+		},
+		{
+			name:  "new_top_level",
+			data:  editorValuesData,
+			path:  "cert",
+			value: "-----BEGIN-----\nMII\n-----END-----\n",
+			expected: `# Database
+db:
+  user: app # application user
+  password: qwerty
+token: abc
+cert: "-----BEGIN-----\nMII\n-----END-----\n"
+`,
+		},
+		{
+			name:     "empty_file",
+			data:     "{}\n",
+			path:     "a.b",
+			value:    "1",
+			expected: "a:\n  b: \"1\"\n",
+		},
+		{
+			name:  "map_replaced_with_leaf",
+			data:  editorValuesData,
+			path:  "db",
+			value: "none",
+			expected: `# Database
+db: none
+token: abc
+`,
+		},
+	}
+
+	m, err := NewSafeManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, err := ParseValuePath(test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := SetValue(m, []byte(test.data), path, []byte(test.value))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(result) != test.expected {
+				t.Errorf("\n[EXPECTED]:\n%s\n[GOT]:\n%s", test.expected, result)
+			}
+
+			value, err := GetValue(m, result, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(value) != test.value {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.value, value)
+			}
+		})
+	}
+}
+
+func TestUnsetValue(t *testing.T) {
+	data, err := UnsetValue([]byte(editorValuesData), []string{"db", "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# Database
+db:
+  password: qwerty
+token: abc
+`
+	if string(data) != expected {
+		t.Errorf("\n[EXPECTED]:\n%s\n[GOT]:\n%s", expected, data)
+	}
+
+	data, err = UnsetValue(data, []string{"db", "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `# Database
+token: abc
+`
+	if string(data) != expected {
+		t.Errorf("\n[EXPECTED]:\n%s\n[GOT]:\n%s", expected, data)
+	}
+
+	if _, err := UnsetValue(data, []string{"db", "password"}); err == nil {
+		t.Errorf("expected not found error")
+	}
+}
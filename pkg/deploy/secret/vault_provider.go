@@ -0,0 +1,167 @@
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultVaultMount     = "secret"
+	DefaultVaultKVVersion = 2
+	DefaultVaultTokenEnv  = "VAULT_TOKEN"
+)
+
+type VaultProviderConfig struct {
+	// Address of vault server, $VAULT_ADDR by default
+	Address string `json:"address,omitempty"`
+	// Namespace is vault enterprise namespace, $VAULT_NAMESPACE by default
+	Namespace string `json:"namespace,omitempty"`
+	Mount     string `json:"mount,omitempty"`
+	Path      string `json:"path"`
+	KVVersion int    `json:"kvVersion,omitempty"`
+	// TokenEnv is an environment variable with vault token, token from ~/.vault-token is used if variable is not set
+	TokenEnv string `json:"tokenEnv,omitempty"`
+}
+
+// VaultProvider reads secret values from HashiCorp Vault KV secrets engine.
+type VaultProvider struct {
+	name   string
+	config VaultProviderConfig
+	client *http.Client
+}
+
+func NewVaultProvider(name string, config VaultProviderConfig) (*VaultProvider, error) {
+	if config.Address == "" {
+		config.Address = os.Getenv("VAULT_ADDR")
+	}
+	if config.Address == "" {
+		return nil, fmt.Errorf("vault address should be specified by address or $VAULT_ADDR")
+	}
+	config.Address = strings.TrimRight(config.Address, "/")
+
+	if config.Namespace == "" {
+		config.Namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+
+	if config.Mount == "" {
+		config.Mount = DefaultVaultMount
+	}
+	config.Mount = strings.Trim(config.Mount, "/")
+
+	config.Path = strings.Trim(config.Path, "/")
+	if config.Path == "" {
+		return nil, fmt.Errorf("vault secret path should be specified")
+	}
+
+	switch config.KVVersion {
+	case 0:
+		config.KVVersion = DefaultVaultKVVersion
+	case 1, 2:
+	default:
+		return nil, fmt.Errorf("bad vault kvVersion %d: expected 1 or 2", config.KVVersion)
+	}
+
+	if config.TokenEnv == "" {
+		config.TokenEnv = DefaultVaultTokenEnv
+	}
+
+	return &VaultProvider{
+		name:   name,
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (p *VaultProvider) Name() string {
+	return p.name
+}
+
+func (p *VaultProvider) secretUrl() string {
+	if p.config.KVVersion == 1 {
+		return fmt.Sprintf("%s/v1/%s/%s", p.config.Address, p.config.Mount, p.config.Path)
+	}
+
+	return fmt.Sprintf("%s/v1/%s/data/%s", p.config.Address, p.config.Mount, p.config.Path)
+}
+
+func (p *VaultProvider) token() (string, error) {
+	if token := os.Getenv(p.config.TokenEnv); token != "" {
+		return token, nil
+	}
+
+	tokenPath := filepath.Join(os.Getenv("HOME"), ".vault-token")
+	data, err := ioutil.ReadFile(tokenPath)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("vault token not found in: '$%s', '%s'", p.config.TokenEnv, tokenPath)
+	} else if err != nil {
+		return "", fmt.Errorf("error reading vault token %s: %s", tokenPath, err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (p *VaultProvider) GetValues() (map[string]interface{}, error) {
+	token, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+
+	u := p.secretUrl()
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if p.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.config.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request %s failed: %s", u, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading vault response %s: %s", u, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResponse struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(body, &errResponse)
+
+		if len(errResponse.Errors) > 0 {
+			return nil, fmt.Errorf("vault request %s failed: %s: %s", u, resp.Status, strings.Join(errResponse.Errors, "; "))
+		}
+		return nil, fmt.Errorf("vault request %s failed: %s", u, resp.Status)
+	}
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("bad vault response %s: %s", u, err)
+	}
+
+	if p.config.KVVersion == 1 {
+		return response.Data, nil
+	}
+
+	// KV v2 wraps secret data with metadata
+	values, ok := response.Data["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("bad vault response %s: no secret data (secret is deleted or destroyed?)", u)
+	}
+
+	return values, nil
+}
@@ -3,7 +3,12 @@ package deploy
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/git_repo"
+	"github.com/flant/dapp/pkg/logger"
 	"github.com/ghodss/yaml"
 )
 
@@ -17,6 +22,7 @@ type GitInfoGetter interface {
 	IsTagState() bool
 	GetCurrentTagName() string
 	GetHeadCommit() string
+	HeadCommitInfo() (*git_repo.CommitInfo, error)
 }
 
 type DimgInfoGetter interface {
@@ -24,6 +30,8 @@ type DimgInfoGetter interface {
 	GetName() string
 	GetImageName() string
 	GetImageId() (string, error)
+	GetImageDigest() (string, error)
+	GetStageSignature() (string, error)
 }
 
 type ServiceValuesOptions struct {
@@ -32,9 +40,7 @@ type ServiceValuesOptions struct {
 }
 
 func GetServiceValues(projectName, repo, namespace, dockerTag string, localGit GitInfoGetter, images []DimgInfoGetter, opts ServiceValuesOptions) (map[string]interface{}, error) {
-	if debug() {
-		fmt.Printf("GetServiceValues %s %s %s %s %#v\n", projectName, repo, namespace, dockerTag, opts)
-	}
+	logger.LogDebugF("deploy", "GetServiceValues %s %s %s %s %#v\n", projectName, repo, namespace, dockerTag, opts)
 
 	res := make(map[string]interface{})
 
@@ -46,11 +52,23 @@ func GetServiceValues(projectName, repo, namespace, dockerTag string, localGit G
 		"ref":       TemplateEmptyValue,
 	}
 
+	commitInfo := map[string]interface{}{
+		"sha":       TemplateEmptyValue,
+		"timestamp": TemplateEmptyValue,
+		"author": map[string]interface{}{
+			"name":  TemplateEmptyValue,
+			"email": TemplateEmptyValue,
+		},
+		"subject": TemplateEmptyValue,
+	}
+
 	dappInfo := map[string]interface{}{
 		"name":       projectName,
 		"repo":       repo,
 		"docker_tag": dockerTag,
+		"version":    dapp.Version,
 		"ci":         ciInfo,
+		"commit":     commitInfo,
 	}
 
 	res["global"] = map[string]interface{}{
@@ -99,6 +117,20 @@ func GetServiceValues(projectName, repo, namespace, dockerTag string, localGit G
 		}
 	}
 
+	if localGit != nil {
+		if info, err := localGit.HeadCommitInfo(); err != nil {
+			logger.LogErrorF("ERROR getting local git repo head commit info: %s\n", err)
+		} else {
+			commitInfo["sha"] = info.Commit
+			commitInfo["timestamp"] = info.Timestamp.UTC().Format(time.RFC3339)
+			commitInfo["author"] = map[string]interface{}{
+				"name":  info.AuthorName,
+				"email": info.AuthorEmail,
+			}
+			commitInfo["subject"] = info.Subject
+		}
+	}
+
 	dimgsInfo := make(map[string]interface{})
 	dappInfo["dimg"] = dimgsInfo
 
@@ -121,9 +153,7 @@ func GetServiceValues(projectName, repo, namespace, dockerTag string, localGit G
 			return nil, err
 		}
 
-		if debug() {
-			fmt.Printf("GetServiceValues got image id of %s: %#v", image.GetImageName(), imageID)
-		}
+		logger.LogDebugF("deploy", "GetServiceValues got image id of %s: %#v", image.GetImageName(), imageID)
 
 		var value string
 		if imageID == "" {
@@ -132,12 +162,43 @@ func GetServiceValues(projectName, repo, namespace, dockerTag string, localGit G
 			value = imageID
 		}
 		imageData["docker_image_id"] = value
+
+		imageDigest, err := image.GetImageDigest()
+		if err != nil {
+			return nil, err
+		}
+
+		imageData["docker_image_digest"] = TemplateEmptyValue
+		imageData["docker_image_with_digest"] = TemplateEmptyValue
+		if imageDigest != "" {
+			imageData["docker_image_digest"] = imageDigest
+			imageData["docker_image_with_digest"] = fmt.Sprintf("%s@%s", imageRepository(image.GetImageName()), imageDigest)
+		}
+
+		stageSignature, err := image.GetStageSignature()
+		if err != nil {
+			return nil, err
+		}
+
+		imageData["stage_signature"] = TemplateEmptyValue
+		if stageSignature != "" {
+			imageData["stage_signature"] = stageSignature
+		}
 	}
 
-	if debug() {
+	if logger.IsDebug("deploy") {
 		data, err := yaml.Marshal(res)
-		fmt.Printf("GetServiceValues result (err=%s):\n%s\n", err, data)
+		logger.LogDebugF("deploy", "GetServiceValues result (err=%s):\n%s\n", err, data)
 	}
 
 	return res, nil
 }
+
+// imageRepository returns image name without tag.
+func imageRepository(imageName string) string {
+	if ind := strings.LastIndex(imageName, ":"); ind > strings.LastIndex(imageName, "/") {
+		return imageName[:ind]
+	}
+
+	return imageName
+}
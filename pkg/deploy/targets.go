@@ -0,0 +1,351 @@
+package deploy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/flant/dapp/pkg/logger"
+)
+
+const (
+	// DeployTargetEnv passes target to dapp deploy process started for the target
+	DeployTargetEnv = "DAPP_DEPLOY_TARGET"
+	// DeployTargetResultFileEnv passes path of the file to write the result of the target deploy process in
+	DeployTargetResultFileEnv = "DAPP_DEPLOY_TARGET_RESULT_FILE"
+
+	TargetsStrategySequential = "sequential"
+	TargetsStrategyParallel   = "parallel"
+
+	TargetStatusDeployed = "DEPLOYED"
+	TargetStatusFailed   = "FAILED"
+	TargetStatusSkipped  = "SKIPPED"
+)
+
+// DeployTarget is a cluster to deploy release into with target specific namespace and values.
+type DeployTarget struct {
+	Name         string   `json:"name"`
+	KubeContext  string   `json:"kubeContext,omitempty"`
+	Namespace    string   `json:"namespace,omitempty"`
+	Values       []string `json:"values,omitempty"`
+	SecretValues []string `json:"secretValues,omitempty"`
+	Set          []string `json:"set,omitempty"`
+	SetString    []string `json:"setString,omitempty"`
+}
+
+type TargetsConfig struct {
+	Targets  []*DeployTarget `json:"targets"`
+	Strategy string          `json:"strategy,omitempty"`
+}
+
+func LoadTargetsConfig(path string) (*TargetsConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading targets file %s: %s", path, err)
+	}
+
+	var cfg TargetsConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("bad targets file %s: %s", path, err)
+	}
+
+	if len(cfg.Targets) == 0 {
+		return nil, fmt.Errorf("bad targets file %s: no targets declared", path)
+	}
+
+	names := make(map[string]bool)
+	for _, target := range cfg.Targets {
+		if target.Name == "" {
+			target.Name = target.KubeContext
+		}
+		if target.Name == "" {
+			return nil, fmt.Errorf("bad targets file %s: name or kubeContext required for each target", path)
+		}
+
+		if names[target.Name] {
+			return nil, fmt.Errorf("bad targets file %s: target '%s' is declared more than once", path, target.Name)
+		}
+		names[target.Name] = true
+	}
+
+	if _, err := getTargetsStrategy(cfg.Strategy); err != nil {
+		return nil, fmt.Errorf("bad targets file %s: %s", path, err)
+	}
+
+	return &cfg, nil
+}
+
+// MergeValues appends target values to the common values: target values override common values.
+func (target *DeployTarget) MergeValues(values, secretValues, set, setString []string) ([]string, []string, []string, []string) {
+	return append(append([]string{}, values...), target.Values...),
+		append(append([]string{}, secretValues...), target.SecretValues...),
+		append(append([]string{}, set...), target.Set...),
+		append(append([]string{}, setString...), target.SetString...)
+}
+
+func getTargetsStrategy(strategy string) (string, error) {
+	switch strategy {
+	case "":
+		return TargetsStrategySequential, nil
+	case TargetsStrategySequential, TargetsStrategyParallel:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("bad targets strategy '%s': expected %s or %s", strategy, TargetsStrategySequential, TargetsStrategyParallel)
+	}
+}
+
+// GetCurrentDeployTarget returns the target when dapp deploy is started for the target by RunTargetsDeploy.
+func GetCurrentDeployTarget() (*DeployTarget, error) {
+	value := os.Getenv(DeployTargetEnv)
+	if value == "" {
+		return nil, nil
+	}
+
+	var target DeployTarget
+	if err := json.Unmarshal([]byte(value), &target); err != nil {
+		return nil, fmt.Errorf("bad %s: %s", DeployTargetEnv, err)
+	}
+
+	return &target, nil
+}
+
+// IsDeployTargetProcess returns true when dapp deploy is started for the target by RunTargetsDeploy.
+func IsDeployTargetProcess() bool {
+	return os.Getenv(DeployTargetEnv) != ""
+}
+
+type deployTargetResultData struct {
+	Changed bool `json:"changed"`
+}
+
+// WriteDeployTargetResult reports the result of successful deploy started for the target to RunTargetsDeploy.
+func WriteDeployTargetResult(changed bool) error {
+	path := os.Getenv(DeployTargetResultFileEnv)
+	if path == "" {
+		return fmt.Errorf("%s is not set", DeployTargetResultFileEnv)
+	}
+
+	data, err := json.Marshal(deployTargetResultData{Changed: changed})
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("cannot write deploy target result: %s", err)
+	}
+
+	return nil
+}
+
+func readDeployTargetResult(path string) (*deployTargetResultData, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("deploy process exited without result")
+	}
+
+	var result deployTargetResultData
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("bad deploy target result: %s", err)
+	}
+
+	return &result, nil
+}
+
+type targetDeployResult struct {
+	Target   *DeployTarget
+	Status   string
+	Duration time.Duration
+	ExitCode int
+	Changed  bool
+	Err      error
+}
+
+// RunTargetsDeploy runs dapp deploy with the same args for each target one by one or simultaneously.
+// Each target is deployed by separate process: kubernetes client is initialized once per process.
+// Sequential deploy is stopped after the first failed target.
+// Returns true when any target release has been changed: changes are reported by target process with WriteDeployTargetResult,
+// any non-zero exit code of target process is a failure.
+func RunTargetsDeploy(targets []*DeployTarget, strategy string, args []string) (bool, error) {
+	strategy, err := getTargetsStrategy(strategy)
+	if err != nil {
+		return false, err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return false, fmt.Errorf("cannot get dapp executable: %s", err)
+	}
+
+	results := make([]*targetDeployResult, len(targets))
+	for ind, target := range targets {
+		results[ind] = &targetDeployResult{Target: target, Status: TargetStatusSkipped}
+	}
+
+	var outputMux sync.Mutex
+
+	if strategy == TargetsStrategyParallel {
+		var wg sync.WaitGroup
+		for _, result := range results {
+			wg.Add(1)
+			go func(result *targetDeployResult) {
+				defer wg.Done()
+				runTargetDeploy(executable, args, result, &outputMux)
+			}(result)
+		}
+		wg.Wait()
+	} else {
+		for _, result := range results {
+			runTargetDeploy(executable, args, result, &outputMux)
+			if result.Err != nil {
+				break
+			}
+		}
+	}
+
+	printTargetDeployResults(logger.GetOutStream(), results)
+
+	var failedTargets []string
+	var hasChanges bool
+	for _, result := range results {
+		if result.Status != TargetStatusDeployed {
+			failedTargets = append(failedTargets, result.Target.Name)
+		}
+		hasChanges = hasChanges || result.Changed
+	}
+	if len(failedTargets) > 0 {
+		return false, fmt.Errorf("deploy into targets %s failed", strings.Join(failedTargets, ", "))
+	}
+
+	return hasChanges, nil
+}
+
+func runTargetDeploy(executable string, args []string, result *targetDeployResult, outputMux *sync.Mutex) {
+	startTime := time.Now()
+	defer func() {
+		result.Duration = time.Since(startTime)
+	}()
+
+	prefix := result.Target.Name + " | "
+
+	targetData, err := json.Marshal(result.Target)
+	if err != nil {
+		result.Status, result.Err = TargetStatusFailed, err
+		return
+	}
+
+	resultFile, err := ioutil.TempFile("", "dapp-deploy-target-result")
+	if err != nil {
+		result.Status, result.Err = TargetStatusFailed, err
+		return
+	}
+	resultFile.Close()
+	defer os.Remove(resultFile.Name())
+
+	cmd := exec.Command(executable, args...)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("%s=%s", DeployTargetEnv, targetData),
+		fmt.Sprintf("%s=%s", DeployTargetResultFileEnv, resultFile.Name()),
+	)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		result.Status, result.Err = TargetStatusFailed, err
+		return
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		result.Status, result.Err = TargetStatusFailed, err
+		return
+	}
+
+	outputMux.Lock()
+	logger.LogF("%s# Deploy into kube context '%s'\n", prefix, valueOrDash(result.Target.KubeContext))
+	outputMux.Unlock()
+
+	if err := cmd.Start(); err != nil {
+		result.Status, result.Err = TargetStatusFailed, err
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, stream := range []struct {
+		r   io.Reader
+		out io.Writer
+	}{{stdout, logger.GetOutStream()}, {stderr, logger.GetErrStream()}} {
+		wg.Add(1)
+		go func(r io.Reader, out io.Writer) {
+			defer wg.Done()
+
+			scanner := bufio.NewScanner(r)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				outputMux.Lock()
+				fmt.Fprintf(out, "%s%s\n", prefix, scanner.Text())
+				outputMux.Unlock()
+			}
+		}(stream.r, stream.out)
+	}
+	wg.Wait()
+
+	err = cmd.Wait()
+	if err != nil {
+		result.Status, result.Err = TargetStatusFailed, err
+		result.ExitCode = -1
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+				result.ExitCode = status.ExitStatus()
+			}
+		}
+		return
+	}
+
+	resultData, err := readDeployTargetResult(resultFile.Name())
+	if err != nil {
+		result.Status, result.Err = TargetStatusFailed, err
+		return
+	}
+
+	result.Status = TargetStatusDeployed
+	result.Changed = resultData.Changed
+}
+
+func printTargetDeployResults(out io.Writer, results []*targetDeployResult) {
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "TARGET\tKUBE CONTEXT\tNAMESPACE\tSTATUS\tDURATION\tEXIT CODE\n")
+	for _, result := range results {
+		exitCode := "-"
+		if result.Status != TargetStatusSkipped {
+			exitCode = fmt.Sprintf("%d", result.ExitCode)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", result.Target.Name, valueOrDash(result.Target.KubeContext), valueOrDash(result.Target.Namespace), result.Status, result.Duration.Round(time.Second), exitCode)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out)
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -0,0 +1,188 @@
+package deploy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/flant/dapp/pkg/dapp"
+)
+
+func writeTestTargetsFile(t *testing.T, data string) string {
+	dir, err := ioutil.TempDir("", "dapp-targets-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "targets.yaml")
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadTargetsConfig(t *testing.T) {
+	path := writeTestTargetsFile(t, `
+strategy: parallel
+targets:
+- name: production-eu
+  kubeContext: eu
+  namespace: app
+  values: [eu.yaml]
+  set: [replicas=3]
+- kubeContext: us
+`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	cfg, err := LoadTargetsConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TargetsConfig{
+		Strategy: TargetsStrategyParallel,
+		Targets: []*DeployTarget{
+			{Name: "production-eu", KubeContext: "eu", Namespace: "app", Values: []string{"eu.yaml"}, Set: []string{"replicas=3"}},
+			{Name: "us", KubeContext: "us"},
+		},
+	}
+
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("\n[EXPECTED]: %#v\n[GOT]: %#v", expected, cfg)
+	}
+}
+
+func TestLoadTargetsConfig_negative(t *testing.T) {
+	for _, data := range []string{
+		`targets: []`,
+		`targets: [{namespace: app}]`,
+		`targets: [{kubeContext: eu}, {name: eu}]`,
+		`{strategy: rolling, targets: [{kubeContext: eu}]}`,
+		`targets: {name: eu}`,
+	} {
+		path := writeTestTargetsFile(t, data)
+
+		if _, err := LoadTargetsConfig(path); err == nil {
+			t.Errorf("\n[DATA]: %s\n[EXPECTED]: error\n[GOT]: no error", data)
+		}
+
+		os.RemoveAll(filepath.Dir(path))
+	}
+}
+
+func TestGetTargetsStrategy(t *testing.T) {
+	for strategy, expected := range map[string]string{
+		"":                        TargetsStrategySequential,
+		TargetsStrategySequential: TargetsStrategySequential,
+		TargetsStrategyParallel:   TargetsStrategyParallel,
+	} {
+		got, err := getTargetsStrategy(strategy)
+		if err != nil {
+			t.Fatalf("\n[STRATEGY]: %q\n[ERROR]: %s", strategy, err)
+		}
+
+		if got != expected {
+			t.Errorf("\n[STRATEGY]: %q\n[EXPECTED]: %s\n[GOT]: %s", strategy, expected, got)
+		}
+	}
+
+	if _, err := getTargetsStrategy("Parallel"); err == nil {
+		t.Errorf("\n[STRATEGY]: Parallel\n[EXPECTED]: error\n[GOT]: no error")
+	}
+}
+
+func TestDeployTarget_MergeValues(t *testing.T) {
+	target := &DeployTarget{
+		Values:    []string{"target.yaml"},
+		Set:       []string{"replicas=3"},
+		SetString: []string{"tier=eu"},
+	}
+
+	commonValues := []string{"common.yaml"}
+	commonSecretValues := []string{"secret.yaml"}
+
+	values, secretValues, set, setString := target.MergeValues(commonValues, commonSecretValues, []string{"replicas=1"}, nil)
+
+	for _, e := range []struct {
+		name     string
+		expected []string
+		got      []string
+	}{
+		{"values", []string{"common.yaml", "target.yaml"}, values},
+		{"secretValues", []string{"secret.yaml"}, secretValues},
+		{"set", []string{"replicas=1", "replicas=3"}, set},
+		{"setString", []string{"tier=eu"}, setString},
+	} {
+		if !reflect.DeepEqual(e.got, e.expected) {
+			t.Errorf("\n[%s EXPECTED]: %v\n[%s GOT]: %v", e.name, e.expected, e.name, e.got)
+		}
+	}
+
+	values[0] = "changed.yaml"
+	if commonValues[0] != "common.yaml" {
+		t.Errorf("\n[EXPECTED]: common values are not changed\n[GOT]: %v", commonValues)
+	}
+}
+
+func TestHelmReleaseLockName(t *testing.T) {
+	if helmReleaseLockName("app", "eu") == helmReleaseLockName("app", "us") {
+		t.Errorf("\n[EXPECTED]: different lock names for kube contexts\n[GOT]: %s", helmReleaseLockName("app", "eu"))
+	}
+
+	if got := helmReleaseLockName("app", ""); got != "helm_release.app" {
+		t.Errorf("\n[EXPECTED]: helm_release.app\n[GOT]: %s", got)
+	}
+}
+
+func TestRunTargetDeploy(t *testing.T) {
+	for _, e := range []struct {
+		script   string
+		status   string
+		changed  bool
+		exitCode int
+	}{
+		{`echo '{"changed": true}' > $DAPP_DEPLOY_TARGET_RESULT_FILE`, TargetStatusDeployed, true, 0},
+		{`echo '{"changed": false}' > $DAPP_DEPLOY_TARGET_RESULT_FILE`, TargetStatusDeployed, false, 0},
+		// Exit code of the runtime panic is the same as exit code of deploy with changes
+		{`echo '{"changed": true}' > $DAPP_DEPLOY_TARGET_RESULT_FILE; exit 2`, TargetStatusFailed, false, 2},
+		{`exit 0`, TargetStatusFailed, false, 0},
+	} {
+		result := &targetDeployResult{Target: &DeployTarget{Name: "eu"}}
+
+		var outputMux sync.Mutex
+		runTargetDeploy("/bin/sh", []string{"-c", e.script}, result, &outputMux)
+
+		if result.Status != e.status || result.Changed != e.changed || result.ExitCode != e.exitCode {
+			t.Errorf("\n[SCRIPT]: %s\n[EXPECTED]: %s changed=%v exit code %d\n[GOT]: %s changed=%v exit code %d (%v)", e.script, e.status, e.changed, e.exitCode, result.Status, result.Changed, result.ExitCode, result.Err)
+		}
+	}
+}
+
+func TestAutoPurgeTriggerFilePath(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "dapp-auto-purge-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := dapp.Init(tmpDir, tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	paths := map[string]bool{}
+	for _, kubeContext := range []string{"", "eu", "us", "arn:aws:eks:eu-central-1:1:cluster/app"} {
+		path := autoPurgeTriggerFilePath("app", kubeContext)
+		if paths[path] {
+			t.Errorf("\n[KUBE CONTEXT]: %q\n[EXPECTED]: unique trigger path\n[GOT]: %s", kubeContext, path)
+		}
+		paths[path] = true
+
+		if filepath.Base(filepath.Dir(path)) == "app" && kubeContext != "" {
+			t.Errorf("\n[KUBE CONTEXT]: %q\n[EXPECTED]: trigger path keyed by kube context\n[GOT]: %s", kubeContext, path)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/flant/dapp/pkg/logger"
+	"github.com/flant/kubedog/pkg/tracker"
+)
+
+const (
+	TrackAnnotation                     = "dapp/track"
+	TrackTimeoutAnnotation              = "dapp/track-timeout"
+	FailModeAnnotation                  = "dapp/fail-mode"
+	ShowLogsOnlyForContainersAnnotation = "dapp/show-logs-only-for-containers"
+	FailuresAllowedAnnotation           = "dapp/failures-allowed"
+
+	FailModeFail   = "fail"
+	FailModeWarn   = "warn"
+	FailModeIgnore = "ignore"
+
+	trackFailureRetryDelay = 5 * time.Second
+)
+
+type resourceTrackOptions struct {
+	Track                     bool
+	Timeout                   time.Duration
+	FailMode                  string
+	FailuresAllowed           int
+	ShowLogsOnlyForContainers []string
+}
+
+func getResourceTrackOptions(template *Template, defaultTimeout time.Duration) (resourceTrackOptions, error) {
+	opts := resourceTrackOptions{
+		Track:    true,
+		Timeout:  defaultTimeout,
+		FailMode: FailModeFail,
+	}
+
+	annotations := template.Metadata.Annotations
+	badValueErr := func(annotation, value string, err error) error {
+		return fmt.Errorf("%s/%s: bad %s annotation value '%s': %s", strings.ToLower(template.Kind), template.Metadata.Name, annotation, value, err)
+	}
+
+	if value, ok := annotations[TrackAnnotation]; ok {
+		track, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, badValueErr(TrackAnnotation, value, err)
+		}
+		opts.Track = track
+	}
+
+	if value, ok := annotations[TrackTimeoutAnnotation]; ok {
+		timeout, err := parseTrackTimeout(value)
+		if err != nil {
+			return opts, badValueErr(TrackTimeoutAnnotation, value, err)
+		}
+		opts.Timeout = timeout
+	}
+
+	if value, ok := annotations[FailModeAnnotation]; ok {
+		switch value {
+		case FailModeFail, FailModeWarn, FailModeIgnore:
+			opts.FailMode = value
+		default:
+			return opts, badValueErr(FailModeAnnotation, value, fmt.Errorf("expected %s, %s or %s", FailModeFail, FailModeWarn, FailModeIgnore))
+		}
+	}
+
+	if value, ok := annotations[FailuresAllowedAnnotation]; ok {
+		failuresAllowed, err := strconv.Atoi(value)
+		if err == nil && failuresAllowed < 0 {
+			err = fmt.Errorf("expected not negative number")
+		}
+		if err != nil {
+			return opts, badValueErr(FailuresAllowedAnnotation, value, err)
+		}
+		opts.FailuresAllowed = failuresAllowed
+	}
+
+	if value, ok := annotations[ShowLogsOnlyForContainersAnnotation]; ok {
+		for _, container := range strings.Split(value, ",") {
+			if container = strings.TrimSpace(container); container != "" {
+				opts.ShowLogsOnlyForContainers = append(opts.ShowLogsOnlyForContainers, container)
+			}
+		}
+	}
+
+	return opts, nil
+}
+
+// parseTrackTimeout accepts number of seconds or duration like 1h30m.
+func parseTrackTimeout(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+type trackResult struct {
+	Resource string
+	Status   string
+	Duration time.Duration
+	Err      error
+}
+
+const (
+	TrackStatusReady   = "READY"
+	TrackStatusFailed  = "FAILED"
+	TrackStatusWarning = "WARNING"
+	TrackStatusIgnored = "IGNORED"
+	TrackStatusSkipped = "SKIPPED"
+)
+
+type resourceTracker struct {
+	Template  *Template
+	TrackFunc func(opts tracker.Options) error
+}
+
+// runResourceTrackers starts all trackers concurrently with the same deadline and waits for them.
+// Deploy fails if any resource fails according to its dapp/fail-mode.
+func runResourceTrackers(trackers []resourceTracker, namespace string, logsFromTime time.Time, timeout time.Duration) error {
+	results := make([]*trackResult, len(trackers))
+
+	var wg sync.WaitGroup
+	for ind := range trackers {
+		wg.Add(1)
+		go func(ind int) {
+			defer wg.Done()
+			results[ind] = trackResource(trackers[ind].Template, namespace, logsFromTime, timeout, trackers[ind].TrackFunc)
+		}(ind)
+	}
+	wg.Wait()
+
+	if len(results) == 0 {
+		return nil
+	}
+
+	printTrackResults(logger.GetOutStream(), results)
+
+	var errs []string
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", result.Resource, result.Err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	return nil
+}
+
+func printTrackResults(out io.Writer, results []*trackResult) {
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "RESOURCE\tSTATUS\tDURATION\tMESSAGE\n")
+	for _, result := range results {
+		var message string
+		if result.Err != nil {
+			message = strings.Replace(result.Err.Error(), "\n", " ", -1)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Resource, result.Status, result.Duration.Round(time.Second), message)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out)
+}
+
+// trackResource runs trackFunc for the resource template according to its dapp/* tracking annotations.
+// Result error is set only when the resource failure should fail the deploy.
+// Trackers do not print containers logs, logs of the resource pods since logsFromTime are followed by followContainersLogs.
+func trackResource(template *Template, namespace string, logsFromTime time.Time, defaultTimeout time.Duration, trackFunc func(opts tracker.Options) error) *trackResult {
+	startTime := time.Now()
+	result := &trackResult{Resource: fmt.Sprintf("%s/%s", strings.ToLower(template.Kind), template.Metadata.Name)}
+	defer func() {
+		result.Duration = time.Since(startTime)
+	}()
+
+	trackOpts, err := getResourceTrackOptions(template, defaultTimeout)
+	if err != nil {
+		result.Status, result.Err = TrackStatusFailed, err
+		return result
+	}
+
+	prefix := result.Resource + " | "
+
+	if !trackOpts.Track {
+		logger.LogF("%s# Skip watch (%s)\n", prefix, TrackAnnotation)
+		result.Status = TrackStatusSkipped
+		return result
+	}
+
+	logger.LogF("%s# Run watch\n", prefix)
+
+	if hasPods(template) {
+		stopLogs := make(chan struct{})
+		defer close(stopLogs)
+
+		go followContainersLogs(template, template.Namespace(namespace), trackOpts.ShowLogsOnlyForContainers, logsFromTime, prefix, stopLogs)
+	}
+
+	var deadline time.Time
+	if trackOpts.Timeout != 0 {
+		deadline = time.Now().Add(trackOpts.Timeout)
+	}
+
+	var failures int
+	for {
+		var opts tracker.Options
+		if !deadline.IsZero() {
+			opts.Timeout = deadline.Sub(time.Now())
+		}
+
+		err = trackFunc(opts)
+		if err == nil {
+			logger.LogF("%s# Ready\n", prefix)
+			result.Status = TrackStatusReady
+			return result
+		}
+
+		failures++
+		if failures > trackOpts.FailuresAllowed || (!deadline.IsZero() && time.Now().Add(trackFailureRetryDelay).After(deadline)) {
+			break
+		}
+
+		logger.LogWarningF("%sWARNING: failed (%d of %d allowed failures): %s\n", prefix, failures, trackOpts.FailuresAllowed, err)
+		time.Sleep(trackFailureRetryDelay)
+	}
+
+	switch trackOpts.FailMode {
+	case FailModeIgnore:
+		logger.LogF("%s# Ignore failure (%s=%s): %s\n", prefix, FailModeAnnotation, FailModeIgnore, err)
+		result.Status = TrackStatusIgnored
+	case FailModeWarn:
+		logger.LogWarningF("%sWARNING: failed: %s\n", prefix, err)
+		result.Status = TrackStatusWarning
+	default:
+		logger.LogErrorF("%sERROR %s\n", prefix, err)
+		result.Status, result.Err = TrackStatusFailed, err
+	}
+
+	return result
+}
+
+func hasPods(template *Template) bool {
+	switch strings.ToLower(template.Kind) {
+	case "pod", "deployment", "statefulset", "daemonset", "job":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package deploy
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetResourceTrackOptions(t *testing.T) {
+	template := parseTestTemplate(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+`)
+
+	opts, err := getResourceTrackOptions(template, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := resourceTrackOptions{Track: true, Timeout: time.Minute, FailMode: FailModeFail}
+	if !reflect.DeepEqual(opts, expected) {
+		t.Errorf("\n[EXPECTED]: %+v\n[GOT]: %+v", expected, opts)
+	}
+
+	template = parseTestTemplate(t, `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrations
+  annotations:
+    dapp/track: "false"
+    dapp/track-timeout: 1h30m
+    dapp/fail-mode: warn
+    dapp/failures-allowed: "2"
+    dapp/show-logs-only-for-containers: "app, migrations,"
+`)
+
+	opts, err = getResourceTrackOptions(template, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = resourceTrackOptions{
+		Track:                     false,
+		Timeout:                   90 * time.Minute,
+		FailMode:                  FailModeWarn,
+		FailuresAllowed:           2,
+		ShowLogsOnlyForContainers: []string{"app", "migrations"},
+	}
+	if !reflect.DeepEqual(opts, expected) {
+		t.Errorf("\n[EXPECTED]: %+v\n[GOT]: %+v", expected, opts)
+	}
+}
+
+func TestGetResourceTrackOptions_negative(t *testing.T) {
+	for _, annotation := range []string{
+		`dapp/track: "maybe"`,
+		`dapp/track-timeout: "soon"`,
+		`dapp/fail-mode: "panic"`,
+		`dapp/failures-allowed: "-1"`,
+		`dapp/failures-allowed: "many"`,
+	} {
+		template := parseTestTemplate(t, "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\n  annotations:\n    "+annotation+"\n")
+
+		if _, err := getResourceTrackOptions(template, time.Minute); err == nil {
+			t.Errorf("\n[ANNOTATION]: %s\n[EXPECTED]: error\n[GOT]: no error", annotation)
+		}
+	}
+}
+
+func TestParseTrackTimeout(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"300":   300 * time.Second,
+		"0":     0,
+		"10m":   10 * time.Minute,
+		"1h30m": 90 * time.Minute,
+	} {
+		got, err := parseTrackTimeout(value)
+		if err != nil {
+			t.Fatalf("\n[VALUE]: %s\n[ERROR]: %s", value, err)
+		}
+
+		if got != expected {
+			t.Errorf("\n[VALUE]: %s\n[EXPECTED]: %s\n[GOT]: %s", value, expected, got)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package deploy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/flant/dapp/pkg/config"
+	"github.com/flant/dapp/pkg/git_repo"
+	"github.com/flant/dapp/pkg/logger"
+	"github.com/ghodss/yaml"
+)
+
+type ValuesOptions struct {
+	WithoutRegistry bool
+}
+
+// RunValues prints service values passed by dapp to the chart or only the value by key like global.dapp.ci.
+func RunValues(projectName, projectDir, namespace, repo, tag, valueKey string, dappfile []*config.Dimg, opts ValuesOptions) error {
+	logger.LogDebugF("deploy", "Values options: %#v\n", opts)
+	logger.LogDebugF("deploy", "Namespace: %s\n", namespace)
+
+	localGit := &git_repo.Local{Path: projectDir, GitDir: filepath.Join(projectDir, ".git")}
+
+	var images []DimgInfoGetter
+	for _, dimg := range dappfile {
+		d := &DimgInfo{Config: dimg, WithoutRegistry: opts.WithoutRegistry, Repo: repo, Tag: tag}
+		images = append(images, d)
+	}
+
+	serviceValues, err := GetServiceValues(projectName, repo, namespace, tag, localGit, images, ServiceValuesOptions{})
+	if err != nil {
+		return fmt.Errorf("error creating service values: %s", err)
+	}
+
+	var value interface{} = serviceValues
+	if valueKey != "" {
+		value = objectField(serviceValues, strings.Split(valueKey, ".")...)
+		if value == nil {
+			return fmt.Errorf("value %s not found", valueKey)
+		}
+	}
+
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("---\n%s", data)
+
+	return nil
+}
@@ -2,11 +2,12 @@ package docker
 
 import (
 	"bytes"
-	"fmt"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/registry"
 	"github.com/docker/cli/cli/flags"
+
+	"github.com/flant/dapp/pkg/logger"
 )
 
 func Login(username, password, repo string) error {
@@ -24,9 +25,7 @@ func Login(username, password, repo string) error {
 	cmd.SetArgs([]string{"--username", username, "--password", password, repo})
 
 	err := cmd.Execute()
-	if Debug() {
-		fmt.Printf("Docker login stdout:\n%s\nDocker login stderr:\n%s\n", outb.String(), errb.String())
-	}
+	logger.LogDebugF("docker", "Docker login stdout:\n%s\nDocker login stderr:\n%s\n", outb.String(), errb.String())
 
 	if err != nil {
 		return err
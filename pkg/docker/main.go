@@ -1,8 +1,6 @@
 package docker
 
 import (
-	"os"
-
 	"github.com/docker/cli/cli/command"
 	cliconfig "github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/flags"
@@ -10,6 +8,8 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/term"
 	"golang.org/x/net/context"
+
+	"github.com/flant/dapp/pkg/logger"
 )
 
 var (
@@ -44,8 +44,8 @@ func ServerVersion() (*types.Version, error) {
 }
 
 func setDockerClient() error {
-	stdIn, stdOut, stdErr := term.StdStreams()
-	cli = command.NewDockerCli(stdIn, stdOut, stdErr, false)
+	stdIn, _, _ := term.StdStreams()
+	cli = command.NewDockerCli(stdIn, logger.GetOutStream(), logger.GetErrStream(), false)
 	opts := flags.NewClientOptions()
 	if err := cli.Initialize(opts); err != nil {
 		return err
@@ -64,7 +64,3 @@ func setDockerApiClient() error {
 
 	return nil
 }
-
-func Debug() bool {
-	return os.Getenv("DAPP_DEBUG_DOCKER") == "1"
-}
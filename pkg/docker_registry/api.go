@@ -0,0 +1,40 @@
+package docker_registry
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+type apiClient struct {
+	httpClient *http.Client
+}
+
+func newApiClient(transport http.RoundTripper) *apiClient {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &apiClient{httpClient: &http.Client{Transport: transport}}
+}
+
+func (c *apiClient) do(req *http.Request, acceptedStatusCodes ...int) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code := range acceptedStatusCodes {
+		if resp.StatusCode == code {
+			return body, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unrecognized status code during %s: %v; %v", req.Method, resp.Status, string(body))
+}
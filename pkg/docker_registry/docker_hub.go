@@ -0,0 +1,103 @@
+package docker_registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const DockerHubApiUrl = "https://hub.docker.com"
+
+type dockerHubOptions struct {
+	ApiUrl    string
+	Username  string
+	Password  string
+	Transport http.RoundTripper
+}
+
+type dockerHub struct {
+	apiUrl   string
+	username string
+	password string
+	token    string
+	client   *apiClient
+}
+
+func newDockerHub(options dockerHubOptions) *dockerHub {
+	return &dockerHub{
+		apiUrl:   strings.TrimRight(options.ApiUrl, "/"),
+		username: options.Username,
+		password: options.Password,
+		client:   newApiClient(options.Transport),
+	}
+}
+
+func (d *dockerHub) String() string {
+	return DockerHubImplementationName
+}
+
+func (d *dockerHub) DeleteRepoImage(repoImage RepoImage) error {
+	token, err := d.getToken()
+	if err != nil {
+		return err
+	}
+
+	repository := repositoryPath(repoImage.Repository)
+	if !strings.Contains(repository, "/") {
+		repository = fmt.Sprintf("library/%s", repository)
+	}
+
+	u := fmt.Sprintf("%s/v2/repositories/%s/tags/%s/", d.apiUrl, repository, repoImage.Tag)
+
+	req, err := http.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("JWT %s", token))
+
+	if _, err := d.client.do(req, http.StatusOK, http.StatusAccepted, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting image %s:%s: %v", repoImage.Repository, repoImage.Tag, err)
+	}
+
+	return nil
+}
+
+func (d *dockerHub) getToken() (string, error) {
+	if d.token != "" {
+		return d.token, nil
+	}
+
+	if d.username == "" {
+		return "", errors.New("docker hub credentials required to delete tags")
+	}
+
+	data, err := json.Marshal(map[string]string{"username": d.username, "password": d.password})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v2/users/login/", d.apiUrl), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	body, err := d.client.do(req, http.StatusOK)
+	if err != nil {
+		return "", fmt.Errorf("docker hub login failed: %v", err)
+	}
+
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("docker hub login failed: %v", err)
+	}
+
+	d.token = resp.Token
+
+	return d.token, nil
+}
@@ -0,0 +1,53 @@
+package docker_registry
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type harborOptions struct {
+	ApiUrl    string
+	Username  string
+	Password  string
+	Transport http.RoundTripper
+}
+
+type harbor struct {
+	apiUrl   string
+	username string
+	password string
+	client   *apiClient
+}
+
+func newHarbor(options harborOptions) *harbor {
+	return &harbor{
+		apiUrl:   strings.TrimRight(options.ApiUrl, "/"),
+		username: options.Username,
+		password: options.Password,
+		client:   newApiClient(options.Transport),
+	}
+}
+
+func (h *harbor) String() string {
+	return HarborImplementationName
+}
+
+func (h *harbor) DeleteRepoImage(repoImage RepoImage) error {
+	u := fmt.Sprintf("%s/api/repositories/%s/tags/%s", h.apiUrl, repositoryPath(repoImage.Repository), repoImage.Tag)
+
+	req, err := http.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+
+	if h.username != "" {
+		req.SetBasicAuth(h.username, h.password)
+	}
+
+	if _, err := h.client.do(req, http.StatusOK); err != nil {
+		return fmt.Errorf("deleting image %s:%s: %v", repoImage.Repository, repoImage.Tag, err)
+	}
+
+	return nil
+}
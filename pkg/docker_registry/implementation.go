@@ -0,0 +1,164 @@
+package docker_registry
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+type RegistryImplementation interface {
+	String() string
+	DeleteRepoImage(repoImage RepoImage) error
+}
+
+func NewRegistryImplementation(repository, registryType string) (RegistryImplementation, error) {
+	if registryType == "" || registryType == AutoImplementationName {
+		var err error
+		registryType, err = DetectRegistryType(repository)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch registryType {
+	case DefaultImplementationName, AcrImplementationName:
+		return &defaultImplementation{}, nil
+	case GitLabImplementationName:
+		return &gitlabImplementation{}, nil
+	case GcrImplementationName:
+		return &gcrImplementation{}, nil
+	case HarborImplementationName:
+		registry, username, password, err := registryCredentials(repository)
+		if err != nil {
+			return nil, err
+		}
+
+		return newHarbor(harborOptions{
+			ApiUrl:    fmt.Sprintf("%s://%s", registry.Scheme(), registry.RegistryStr()),
+			Username:  username,
+			Password:  password,
+			Transport: getHttpTransport(),
+		}), nil
+	case QuayImplementationName:
+		registry, err := name.NewRegistry(repositoryHost(repository), name.WeakValidation)
+		if err != nil {
+			return nil, fmt.Errorf("parsing registry of repo %q: %v", repository, err)
+		}
+
+		return newQuay(quayOptions{
+			ApiUrl:    fmt.Sprintf("%s://%s", registry.Scheme(), registry.RegistryStr()),
+			Token:     os.Getenv("DAPP_QUAY_API_TOKEN"),
+			Transport: getHttpTransport(),
+		}), nil
+	case DockerHubImplementationName:
+		_, username, password, err := registryCredentials(repository)
+		if err != nil {
+			return nil, err
+		}
+
+		return newDockerHub(dockerHubOptions{
+			ApiUrl:    DockerHubApiUrl,
+			Username:  username,
+			Password:  password,
+			Transport: getHttpTransport(),
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown registry type '%s' (expected one of: %s)", registryType, strings.Join(RegistryImplementationNames, ", "))
+	}
+}
+
+func registryCredentials(repository string) (name.Registry, string, string, error) {
+	repo, err := name.NewRepository(repository, name.WeakValidation)
+	if err != nil {
+		return name.Registry{}, "", "", fmt.Errorf("parsing repo %q: %v", repository, err)
+	}
+
+	auth, err := authn.DefaultKeychain.Resolve(repo.Registry)
+	if err != nil {
+		return name.Registry{}, "", "", fmt.Errorf("getting creds for %q: %v", repo, err)
+	}
+
+	authorization, err := auth.Authorization()
+	if err != nil {
+		return name.Registry{}, "", "", fmt.Errorf("getting creds for %q: %v", repo, err)
+	}
+
+	basicPrefix := "Basic "
+	if !strings.HasPrefix(authorization, basicPrefix) {
+		return repo.Registry, "", "", nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(authorization, basicPrefix))
+	if err != nil {
+		return name.Registry{}, "", "", fmt.Errorf("decoding creds for %q: %v", repo, err)
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return repo.Registry, "", "", nil
+	}
+
+	return repo.Registry, parts[0], parts[1], nil
+}
+
+type defaultImplementation struct{}
+
+func (i *defaultImplementation) String() string {
+	return DefaultImplementationName
+}
+
+func (i *defaultImplementation) DeleteRepoImage(repoImage RepoImage) error {
+	digest, err := repoImage.Digest()
+	if err != nil {
+		return err
+	}
+
+	reference := strings.Join([]string{repoImage.Repository, digest.String()}, "@")
+
+	return ImageDelete(reference)
+}
+
+type gitlabImplementation struct{}
+
+func (i *gitlabImplementation) String() string {
+	return GitLabImplementationName
+}
+
+func (i *gitlabImplementation) DeleteRepoImage(repoImage RepoImage) error {
+	digest, err := repoImage.Digest()
+	if err != nil {
+		return err
+	}
+
+	reference := strings.Join([]string{repoImage.Repository, digest.String()}, "@")
+
+	r, err := name.ParseReference(reference, name.WeakValidation)
+	if err != nil {
+		return fmt.Errorf("parsing reference %q: %v", reference, err)
+	}
+
+	auth, err := authn.DefaultKeychain.Resolve(r.Context().Registry)
+	if err != nil {
+		return fmt.Errorf("getting creds for %q: %v", r, err)
+	}
+
+	if err := GitlabRegistryDelete(r, auth, getHttpTransport()); err != nil {
+		return fmt.Errorf("deleting image %q: %v", r, err)
+	}
+
+	return nil
+}
+
+type gcrImplementation struct{}
+
+func (i *gcrImplementation) String() string {
+	return GcrImplementationName
+}
+
+func (i *gcrImplementation) DeleteRepoImage(repoImage RepoImage) error {
+	return ImageDelete(strings.Join([]string{repoImage.Repository, repoImage.Tag}, ":"))
+}
@@ -0,0 +1,94 @@
+package docker_registry
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	AutoImplementationName      = "auto"
+	DefaultImplementationName   = "default"
+	GitLabImplementationName    = "gitlab"
+	GcrImplementationName       = "gcr"
+	AcrImplementationName       = "acr"
+	HarborImplementationName    = "harbor"
+	QuayImplementationName      = "quay"
+	DockerHubImplementationName = "dockerhub"
+)
+
+var RegistryImplementationNames = []string{
+	AutoImplementationName,
+	DefaultImplementationName,
+	GitLabImplementationName,
+	GcrImplementationName,
+	AcrImplementationName,
+	HarborImplementationName,
+	QuayImplementationName,
+	DockerHubImplementationName,
+}
+
+var (
+	GitLabUrlPatterns    = []string{"^registry\\.gitlab\\.com", "^gitlab\\.", "^registry\\.gitlab\\."}
+	AcrUrlPatterns       = []string{"^.*\\.azurecr\\.io"}
+	HarborUrlPatterns    = []string{"^harbor\\.", "^.*\\.harbor\\."}
+	QuayUrlPatterns      = []string{"^quay\\.io"}
+	DockerHubUrlPatterns = []string{"^index\\.docker\\.io", "^registry-1\\.docker\\.io", "^docker\\.io"}
+)
+
+func DetectRegistryType(repository string) (string, error) {
+	host := repositoryHost(repository)
+
+	u, err := url.Parse(fmt.Sprintf("scheme://%s", host))
+	if err != nil {
+		return "", err
+	}
+
+	for _, implementationPatterns := range []struct {
+		name     string
+		patterns []string
+	}{
+		{GcrImplementationName, GCRUrlPatterns},
+		{GitLabImplementationName, GitLabUrlPatterns},
+		{AcrImplementationName, AcrUrlPatterns},
+		{HarborImplementationName, HarborUrlPatterns},
+		{QuayImplementationName, QuayUrlPatterns},
+		{DockerHubImplementationName, DockerHubUrlPatterns},
+	} {
+		for _, pattern := range implementationPatterns.patterns {
+			matched, err := regexp.MatchString(pattern, u.Hostname())
+			if err != nil {
+				return "", err
+			}
+
+			if matched {
+				return implementationPatterns.name, nil
+			}
+		}
+	}
+
+	return DefaultImplementationName, nil
+}
+
+// repositoryHost returns the registry host of the repository reference.
+// Docker Hub is implied when the first path element does not look like a host.
+func repositoryHost(repository string) string {
+	host, _ := splitRepository(repository)
+	return host
+}
+
+// repositoryPath returns the repository reference without the registry host.
+func repositoryPath(repository string) string {
+	_, path := splitRepository(repository)
+	return path
+}
+
+func splitRepository(repository string) (string, string) {
+	parts := strings.SplitN(repository, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0], parts[1]
+	}
+
+	return "index.docker.io", repository
+}
@@ -0,0 +1,151 @@
+package docker_registry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetectRegistryType(t *testing.T) {
+	tests := []struct {
+		repository   string
+		registryType string
+	}{
+		{"registry.gitlab.com/group/project", GitLabImplementationName},
+		{"gcr.io/project/app", GcrImplementationName},
+		{"eu.gcr.io/project/app", GcrImplementationName},
+		{"myregistry.azurecr.io/app", AcrImplementationName},
+		{"harbor.example.com/library/app", HarborImplementationName},
+		{"quay.io/org/app", QuayImplementationName},
+		{"docker.io/org/app", DockerHubImplementationName},
+		{"org/app", DockerHubImplementationName},
+		{"localhost:5000/app", DefaultImplementationName},
+		{"registry.example.com/app", DefaultImplementationName},
+	}
+
+	for _, test := range tests {
+		registryType, err := DetectRegistryType(test.repository)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if registryType != test.registryType {
+			t.Errorf("%s: expected registry type %q, got %q", test.repository, test.registryType, registryType)
+		}
+	}
+}
+
+func TestHarbor_DeleteRepoImage(t *testing.T) {
+	var deletedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		deletedPath = r.URL.Path
+	}))
+	defer server.Close()
+
+	h := newHarbor(harborOptions{ApiUrl: server.URL, Username: "user", Password: "pass"})
+	if err := h.DeleteRepoImage(RepoImage{Repository: "harbor.example.com/project/app", Tag: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "/api/repositories/project/app/tags/v1"; deletedPath != expected {
+		t.Errorf("expected DELETE %s, got %s", expected, deletedPath)
+	}
+
+	h = newHarbor(harborOptions{ApiUrl: server.URL, Username: "user", Password: "wrong"})
+	if err := h.DeleteRepoImage(RepoImage{Repository: "harbor.example.com/project/app", Tag: "v1"}); err == nil {
+		t.Error("expected error on unauthorized request")
+	}
+}
+
+func TestQuay_DeleteRepoImage(t *testing.T) {
+	var deletedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		deletedPath = r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	q := newQuay(quayOptions{ApiUrl: server.URL, Token: "token"})
+	if err := q.DeleteRepoImage(RepoImage{Repository: "quay.io/org/app", Tag: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "/api/v1/repository/org/app/tag/v1"; deletedPath != expected {
+		t.Errorf("expected DELETE %s, got %s", expected, deletedPath)
+	}
+
+	q = newQuay(quayOptions{ApiUrl: server.URL})
+	if err := q.DeleteRepoImage(RepoImage{Repository: "quay.io/org/app", Tag: "v1"}); err == nil {
+		t.Error("expected error without token")
+	}
+}
+
+func TestDockerHub_DeleteRepoImage(t *testing.T) {
+	var loginCount int
+	var deletedPaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/users/login/":
+			var creds map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&creds); err != nil || creds["username"] != "user" || creds["password"] != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			loginCount++
+			json.NewEncoder(w).Encode(map[string]string{"token": "jwt"})
+		case r.Method == http.MethodDelete:
+			if r.Header.Get("Authorization") != "JWT jwt" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			deletedPaths = append(deletedPaths, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	d := newDockerHub(dockerHubOptions{ApiUrl: server.URL, Username: "user", Password: "pass"})
+	for _, repoImage := range []RepoImage{
+		{Repository: "org/app", Tag: "v1"},
+		{Repository: "docker.io/app", Tag: "v2"},
+	} {
+		if err := d.DeleteRepoImage(repoImage); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if loginCount != 1 {
+		t.Errorf("expected single login, got %d", loginCount)
+	}
+
+	expectedPaths := []string{"/v2/repositories/org/app/tags/v1/", "/v2/repositories/library/app/tags/v2/"}
+	if len(deletedPaths) != len(expectedPaths) {
+		t.Fatalf("expected %v, got %v", expectedPaths, deletedPaths)
+	}
+	for ind := range expectedPaths {
+		if deletedPaths[ind] != expectedPaths[ind] {
+			t.Errorf("expected DELETE %s, got %s", expectedPaths[ind], deletedPaths[ind])
+		}
+	}
+}
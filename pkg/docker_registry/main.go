@@ -15,8 +15,13 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+
+	"github.com/flant/dapp/pkg/logger"
 )
 
+// DimgStageSignatureLabel contains signature of the last stage dimg image was built from
+const DimgStageSignatureLabel = "dapp-stage-signature"
+
 type RepoImage struct {
 	Repository string
 	Tag        string
@@ -92,7 +97,7 @@ func ImagesByDappDimgLabel(reference, labelValue string) ([]RepoImage, error) {
 		v1Image, _, err := image(tagReference)
 		if err != nil {
 			if strings.Contains(err.Error(), "BLOB_UNKNOWN") {
-				fmt.Printf("Ignore broken tag '%s': %s\n", tag, err)
+				logger.LogF("Ignore broken tag '%s': %s\n", tag, err)
 				continue
 			}
 			return nil, err
@@ -101,7 +106,7 @@ func ImagesByDappDimgLabel(reference, labelValue string) ([]RepoImage, error) {
 		configFile, err := v1Image.ConfigFile()
 		if err != nil {
 			if strings.Contains(err.Error(), "MANIFEST_UNKNOWN") {
-				fmt.Printf("Ignore broken tag '%s': %s\n", tag, err)
+				logger.LogF("Ignore broken tag '%s': %s\n", tag, err)
 				continue
 			}
 			return nil, err
@@ -0,0 +1,52 @@
+package docker_registry
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type quayOptions struct {
+	ApiUrl    string
+	Token     string
+	Transport http.RoundTripper
+}
+
+type quay struct {
+	apiUrl string
+	token  string
+	client *apiClient
+}
+
+func newQuay(options quayOptions) *quay {
+	return &quay{
+		apiUrl: strings.TrimRight(options.ApiUrl, "/"),
+		token:  options.Token,
+		client: newApiClient(options.Transport),
+	}
+}
+
+func (q *quay) String() string {
+	return QuayImplementationName
+}
+
+func (q *quay) DeleteRepoImage(repoImage RepoImage) error {
+	if q.token == "" {
+		return errors.New("quay API token required to delete tags: set DAPP_QUAY_API_TOKEN")
+	}
+
+	u := fmt.Sprintf("%s/api/v1/repository/%s/tag/%s", q.apiUrl, repositoryPath(repoImage.Repository), repoImage.Tag)
+
+	req, err := http.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", q.token))
+
+	if _, err := q.client.do(req, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("deleting image %s:%s: %v", repoImage.Repository, repoImage.Tag, err)
+	}
+
+	return nil
+}
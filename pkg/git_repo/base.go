@@ -15,6 +15,7 @@ import (
 
 	"github.com/bmatcuk/doublestar"
 	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/dapp/pkg/logger"
 	"github.com/flant/dapp/pkg/true_git"
 	git "gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
@@ -138,6 +139,39 @@ func (repo *Base) getReferenceForRepo(repoPath string) (*plumbing.Reference, err
 	return repository.Head()
 }
 
+type CommitInfo struct {
+	Commit      string
+	Timestamp   time.Time
+	AuthorName  string
+	AuthorEmail string
+	Subject     string
+}
+
+func (repo *Base) getHeadCommitInfo(repoPath string) (*CommitInfo, error) {
+	repository, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open repo `%s`: %s", repoPath, err)
+	}
+
+	ref, err := repository.Head()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get repo `%s` head: %s", repoPath, err)
+	}
+
+	commit, err := repository.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get repo `%s` head commit `%s`: %s", repoPath, ref.Hash(), err)
+	}
+
+	return &CommitInfo{
+		Commit:      commit.Hash.String(),
+		Timestamp:   commit.Committer.When,
+		AuthorName:  commit.Author.Name,
+		AuthorEmail: commit.Author.Email,
+		Subject:     strings.TrimSpace(strings.SplitN(commit.Message, "\n", 2)[0]),
+	}, nil
+}
+
 func (repo *Base) getHeadBranchName(repoPath string) (string, error) {
 	ref, err := repo.getReferenceForRepo(repoPath)
 	if err != nil {
@@ -423,9 +457,7 @@ func (repo *Base) checksum(repoPath, gitDir, workTreeDir string, opts ChecksumOp
 
 			if len(res) == 0 {
 				checksum.NoMatchPaths = append(checksum.NoMatchPaths, pathPattern)
-				if debugChecksum() {
-					fmt.Printf("Ignore checksum path pattern `%s`: no matches found\n", pathPattern)
-				}
+				logger.LogDebugF("git_repo.checksum", "Ignore checksum path pattern `%s`: no matches found\n", pathPattern)
 			}
 
 			paths = append(paths, res...)
@@ -443,9 +475,7 @@ func (repo *Base) checksum(repoPath, gitDir, workTreeDir string, opts ChecksumOp
 			fullPath := filepath.Join(workTreeDir, path)
 
 			if !pathFilter.IsFilePathValid(path) {
-				if debugChecksum() {
-					fmt.Printf("Excluded file `%s` from resulting checksum by path filter %s\n", fullPath, pathFilter.String())
-				}
+				logger.LogDebugF("git_repo.checksum", "Excluded file `%s` from resulting checksum by path filter %s\n", fullPath, pathFilter.String())
 				continue
 			}
 
@@ -481,7 +511,7 @@ func (repo *Base) checksum(repoPath, gitDir, workTreeDir string, opts ChecksumOp
 					return fmt.Errorf("error closing file `%s`: %s", fullPath, err)
 				}
 
-				if debugChecksum() {
+				if logger.IsDebug("git_repo.checksum") {
 					f, err := os.Open(fullPath)
 					if err != nil {
 						return fmt.Errorf("unable to open file `%s`: %s", fullPath, err)
@@ -499,7 +529,7 @@ func (repo *Base) checksum(repoPath, gitDir, workTreeDir string, opts ChecksumOp
 						return fmt.Errorf("error closing file `%s`: %s", fullPath, err)
 					}
 
-					fmt.Printf("Added file `%s` to resulting checksum with content checksum: %s\n", fullPath, contentHash)
+					logger.LogDebugF("git_repo.checksum", "Added file `%s` to resulting checksum with content checksum: %s\n", fullPath, contentHash)
 				}
 			} else if stat.Mode()&os.ModeSymlink != 0 {
 				linkname, err := os.Readlink(fullPath)
@@ -512,9 +542,7 @@ func (repo *Base) checksum(repoPath, gitDir, workTreeDir string, opts ChecksumOp
 					return fmt.Errorf("error calculating checksum of symlink `%s`: %s", fullPath, err)
 				}
 
-				if debugChecksum() {
-					fmt.Printf("Added symlink `%s` -> `%s` to resulting checksum\n", fullPath, linkname)
-				}
+				logger.LogDebugF("git_repo.checksum", "Added symlink `%s` -> `%s` to resulting checksum\n", fullPath, linkname)
 			}
 		}
 
@@ -525,9 +553,7 @@ func (repo *Base) checksum(repoPath, gitDir, workTreeDir string, opts ChecksumOp
 		return nil, err
 	}
 
-	if debugChecksum() {
-		fmt.Printf("Calculated checksum %s\n", checksum.String())
-	}
+	logger.LogDebugF("git_repo.checksum", "Calculated checksum %s\n", checksum.String())
 
 	return checksum, nil
 }
@@ -582,7 +608,3 @@ func getFilesByPattern(baseDir, pathPattern string) ([]string, error) {
 
 	return paths, nil
 }
-
-func debugChecksum() bool {
-	return os.Getenv("DAPP_DEBUG_GIT_REPO_CHECKSUM") == "1"
-}
@@ -2,13 +2,14 @@ package git_repo
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	git "gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
 	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+
+	"github.com/flant/dapp/pkg/logger"
 )
 
 type Local struct {
@@ -41,6 +42,10 @@ func (repo *Local) HeadCommit() (string, error) {
 	return fmt.Sprintf("%s", ref.Hash()), nil
 }
 
+func (repo *Local) HeadCommitInfo() (*CommitInfo, error) {
+	return repo.getHeadCommitInfo(repo.Path)
+}
+
 func (repo *Local) HeadBranchName() (string, error) {
 	return repo.getHeadBranchName(repo.Path)
 }
@@ -93,7 +98,7 @@ func (repo *Local) IsBranchState() bool {
 	if err == errNotABranch {
 		return false
 	} else if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR getting branch of local git: %s\n", err)
+		logger.LogErrorF("ERROR getting branch of local git: %s\n", err)
 		return false
 	}
 	return true
@@ -102,7 +107,7 @@ func (repo *Local) IsBranchState() bool {
 func (repo *Local) GetCurrentBranchName() string {
 	name, err := repo.HeadBranchName()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR getting branch of local git: %s\n", err)
+		logger.LogErrorF("ERROR getting branch of local git: %s\n", err)
 		return ""
 	}
 	return name
@@ -151,13 +156,13 @@ func (repo *Local) findTagByCommitID(repoPath string, commitID plumbing.Hash) (s
 func (repo *Local) GetCurrentTagName() string {
 	ref, err := repo.getReferenceForRepo(repo.Path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR cannot get local git repo head ref: %s\n", err)
+		logger.LogErrorF("ERROR cannot get local git repo head ref: %s\n", err)
 		return ""
 	}
 
 	tag, err := repo.findTagByCommitID(repo.Path, ref.Hash())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR cannot get local git repo tag: %s\n", err)
+		logger.LogErrorF("ERROR cannot get local git repo tag: %s\n", err)
 		return ""
 	}
 	return tag
@@ -166,7 +171,7 @@ func (repo *Local) GetCurrentTagName() string {
 func (repo *Local) GetHeadCommit() string {
 	ref, err := repo.getReferenceForRepo(repo.Path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR getting HEAD commit id of local git repo: %s\n", err)
+		logger.LogErrorF("ERROR getting HEAD commit id of local git repo: %s\n", err)
 		return ""
 	}
 	return fmt.Sprintf("%s", ref.Hash())
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/flant/dapp/pkg/lock"
+	"github.com/flant/dapp/pkg/logger"
 	"gopkg.in/ini.v1"
 	"gopkg.in/satori/go.uuid.v1"
 	git "gopkg.in/src-d/go-git.v4"
@@ -21,6 +22,7 @@ type Remote struct {
 	Base
 	Url       string
 	ClonePath string // TODO: move CacheVersion & path construction here
+	SSHKey    string
 	IsDryRun  bool
 }
 
@@ -89,12 +91,19 @@ func (repo *Remote) Clone() (bool, error) {
 			return nil
 		}
 
-		fmt.Printf("Clone remote git repo `%s` ...\n", repo.String())
+		logger.LogF("Clone remote git repo `%s` ...\n", repo.String())
 
 		path := filepath.Join("/tmp", fmt.Sprintf("dapp-git-repo-%s", uuid.NewV4().String()))
 
+		auth, closeAuth, err := repo.sshAuth()
+		if err != nil {
+			return fmt.Errorf("cannot init ssh auth for repo `%s`: %s", repo.String(), err)
+		}
+		defer closeAuth()
+
 		_, err = git.PlainClone(path, true, &git.CloneOptions{
 			URL:               repo.Url,
+			Auth:              auth,
 			RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
 		})
 		if err != nil {
@@ -113,7 +122,7 @@ func (repo *Remote) Clone() (bool, error) {
 			return err
 		}
 
-		fmt.Printf("Clone remote git repo `%s` DONE\n", repo.String())
+		logger.LogF("Clone remote git repo `%s` DONE\n", repo.String())
 
 		return nil
 	})
@@ -148,14 +157,20 @@ func (repo *Remote) Fetch() error {
 			return fmt.Errorf("cannot open repo: %s", err)
 		}
 
-		fmt.Printf("Fetching remote `%s` of repo `%s` ...\n", remoteName, repo.String())
+		logger.LogF("Fetching remote `%s` of repo `%s` ...\n", remoteName, repo.String())
+
+		auth, closeAuth, err := repo.sshAuth()
+		if err != nil {
+			return fmt.Errorf("cannot init ssh auth for repo `%s`: %s", repo.String(), err)
+		}
+		defer closeAuth()
 
-		err = rawRepo.Fetch(&git.FetchOptions{RemoteName: remoteName, Force: true})
+		err = rawRepo.Fetch(&git.FetchOptions{RemoteName: remoteName, Auth: auth, Force: true})
 		if err != nil && err != git.NoErrAlreadyUpToDate {
 			return fmt.Errorf("cannot fetch remote `%s` of repo `%s`: %s", remoteName, repo.String(), err)
 		}
 
-		fmt.Printf("Fetching remote `%s` of repo `%s` DONE\n", remoteName, repo.String())
+		logger.LogF("Fetching remote `%s` of repo `%s` DONE\n", remoteName, repo.String())
 
 		return nil
 	})
@@ -227,7 +242,7 @@ func (repo *Remote) LatestBranchCommit(branch string) (string, error) {
 		return "", fmt.Errorf("unknown branch `%s` of repo `%s`", branch, repo.String())
 	}
 
-	fmt.Printf("Using commit `%s` of repo `%s` branch `%s`\n", res, repo.String(), branch)
+	logger.LogF("Using commit `%s` of repo `%s` branch `%s`\n", res, repo.String(), branch)
 
 	return res, nil
 }
@@ -248,7 +263,7 @@ func (repo *Remote) LatestTagCommit(tag string) (string, error) {
 		return "", fmt.Errorf("unknown tag `%s` of repo `%s`", tag, repo.String())
 	}
 
-	fmt.Printf("Using commit `%s` of repo `%s` tag `%s`\n", res, repo.String(), tag)
+	logger.LogF("Using commit `%s` of repo `%s` tag `%s`\n", res, repo.String(), tag)
 
 	return res, nil
 }
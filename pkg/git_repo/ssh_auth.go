@@ -0,0 +1,72 @@
+package git_repo
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	gitssh "gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+
+	"github.com/flant/dapp/pkg/ssh_agent"
+)
+
+// sshAuth returns auth for ssh urls: the repo key, keys mapped to the host or the dapp ssh agent keys.
+// Host keys are verified by the project known hosts file if specified.
+// Nil auth is returned for other urls, go-git defaults are used then.
+func (repo *Remote) sshAuth() (transport.AuthMethod, func(), error) {
+	noop := func() {}
+
+	endpoint, err := transport.NewEndpoint(repo.Url)
+	if err != nil {
+		return nil, noop, fmt.Errorf("bad url `%s`: %s", repo.Url, err)
+	}
+
+	if endpoint.Protocol != "ssh" {
+		return nil, noop, nil
+	}
+
+	user := endpoint.User
+	if user == "" {
+		user = gitssh.DefaultUsername
+	}
+
+	hostKeyCallback, err := ssh_agent.HostKeyCallback()
+	if err != nil {
+		return nil, noop, err
+	}
+
+	keys := ssh_agent.GetHostKeys(endpoint.Host)
+	if repo.SSHKey != "" {
+		keys = []string{repo.SSHKey}
+	}
+
+	if len(keys) > 0 {
+		signers, err := ssh_agent.GetSigners(keys)
+		if err != nil {
+			return nil, noop, err
+		}
+
+		return &gitssh.PublicKeysCallback{
+			User:                  user,
+			Callback:              func() ([]ssh.Signer, error) { return signers, nil },
+			HostKeyCallbackHelper: gitssh.HostKeyCallbackHelper{HostKeyCallback: hostKeyCallback},
+		}, noop, nil
+	}
+
+	if ssh_agent.SSHAuthSock == "" {
+		return nil, noop, nil
+	}
+
+	conn, err := net.Dial("unix", ssh_agent.SSHAuthSock)
+	if err != nil {
+		return nil, noop, fmt.Errorf("error dialing with ssh agent %s: %s", ssh_agent.SSHAuthSock, err)
+	}
+
+	return &gitssh.PublicKeysCallback{
+		User:                  user,
+		Callback:              agent.NewClient(conn).Signers,
+		HostKeyCallbackHelper: gitssh.HostKeyCallbackHelper{HostKeyCallback: hostKeyCallback},
+	}, func() { conn.Close() }, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/docker/docker/api/types"
 
 	"github.com/flant/dapp/pkg/docker"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 type Stage struct {
@@ -77,7 +78,7 @@ func (i *Stage) Build(options BuildOptions) error {
 	if containerRunErr := i.container.run(); containerRunErr != nil {
 		if strings.HasPrefix(containerRunErr.Error(), "container run failed") {
 			if options.IntrospectBeforeError {
-				fmt.Printf("Launched command: %s\n", strings.Join(i.container.prepareAllRunCommands(), " && "))
+				logger.LogF("Launched command: %s\n", strings.Join(i.container.prepareAllRunCommands(), " && "))
 				if err := i.introspectBefore(); err != nil {
 					return fmt.Errorf("introspect error failed: %s", err)
 				}
@@ -86,7 +87,7 @@ func (i *Stage) Build(options BuildOptions) error {
 					return fmt.Errorf("introspect error failed: %s", err)
 				}
 
-				fmt.Printf("Launched command: %s\n", strings.Join(i.container.prepareAllRunCommands(), " && "))
+				logger.LogF("Launched command: %s\n", strings.Join(i.container.prepareAllRunCommands(), " && "))
 				if err := i.Introspect(); err != nil {
 					return fmt.Errorf("introspect error failed: %s", err)
 				}
@@ -0,0 +1,89 @@
+package lock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	FileBackend       = "file"
+	KubernetesBackend = "kubernetes"
+	HttpBackend       = "http"
+)
+
+var (
+	Backends = []string{FileBackend, KubernetesBackend, HttpBackend}
+
+	// Locks of the host resources (tmp dirs, local docker, git work trees) are always file locks
+	LocalLockNames        = []string{"gc"}
+	LocalLockNamePrefixes = []string{"dappdeps.", "git_work_tree ", "remote_git_artifact."}
+
+	backendStore LockStore
+	backendTTL   time.Duration
+)
+
+type BackendOptions struct {
+	Backend string
+	TTL     time.Duration
+
+	KubernetesNamespace string
+	KubernetesClient    kubernetes.Interface
+
+	HttpUrl string
+}
+
+// SetupBackend makes locks shared between hosts, should be called after Init.
+func SetupBackend(opts BackendOptions) error {
+	switch opts.Backend {
+	case "", FileBackend:
+		backendStore = nil
+	case KubernetesBackend:
+		if opts.KubernetesClient == nil {
+			return fmt.Errorf("kubernetes client required for %s lock backend", opts.Backend)
+		}
+		backendStore = NewKubernetesLeaseStore(opts.KubernetesNamespace, opts.KubernetesClient)
+	case HttpBackend:
+		if opts.HttpUrl == "" {
+			return fmt.Errorf("url required for %s lock backend", opts.Backend)
+		}
+		backendStore = NewHttpStore(opts.HttpUrl)
+	default:
+		return fmt.Errorf("unknown lock backend `%s`: expected %s", opts.Backend, strings.Join(Backends, ", "))
+	}
+
+	backendTTL = opts.TTL
+
+	return nil
+}
+
+func newLock(name string) LockObject {
+	if backendStore == nil || isLocalLockName(name) {
+		return NewFileLock(name, LocksDir)
+	}
+
+	return NewDistributedLock(name, backendStore, backendTTL)
+}
+
+func isLocalLockName(name string) bool {
+	for _, localName := range LocalLockNames {
+		if name == localName {
+			return true
+		}
+	}
+
+	for _, prefix := range LocalLockNamePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetBackendStore returns store of the lock backend shared between hosts, nil is returned for the file backend.
+func GetBackendStore() LockStore {
+	return backendStore
+}
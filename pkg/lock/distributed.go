@@ -0,0 +1,438 @@
+package lock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/flant/dapp/pkg/logger"
+)
+
+const exclusiveLockRecordId = "exclusive"
+
+var distributedLockPollPeriod = 2 * time.Second
+
+// LockStore keeps lock records shared between hosts.
+// Each lock has one exclusive record and a record per read-only holder, record version is used for compare-and-swap.
+type LockStore interface {
+	// Get returns nil info if the record does not exist
+	Get(name, id string) (*LockInfo, string, error)
+	// Create returns false if the record already exists
+	Create(name, id string, info *LockInfo) (bool, error)
+	// Update returns false if the record has been changed or removed since version
+	Update(name, id string, info *LockInfo, version string) (bool, error)
+	// Delete removes the record if it has not been changed since version
+	Delete(name, id string, version string) error
+	List(name string) ([]*LockRecord, error)
+}
+
+type LockRecord struct {
+	Id      string
+	Info    *LockInfo
+	Version string
+}
+
+// NewDistributedLock creates lock stored in the store shared between hosts.
+// Lock holder renews its record every ttl/3, record of the holder which has not renewed it during ttl is considered stale.
+// Exclusive holder waits for read-only holders, new read-only holders give way to the waiting exclusive holder.
+func NewDistributedLock(name string, store LockStore, ttl time.Duration) LockObject {
+	if ttl == 0 {
+		ttl = DefaultLockTTL
+	}
+
+	return &Distributed{Base: Base{Name: name}, Store: store, TTL: ttl}
+}
+
+type Distributed struct {
+	Base
+	Store  LockStore
+	TTL    time.Duration
+	locker *distributedLocker
+}
+
+func (lock *Distributed) newLocker(timeout time.Duration, readOnly bool, onWait func(doWait func() error) error) *distributedLocker {
+	return &distributedLocker{
+		baseLocker: baseLocker{
+			Timeout:  timeout,
+			ReadOnly: readOnly,
+			OnWait:   onWait,
+		},
+		DistributedLock: lock,
+	}
+}
+
+func (lock *Distributed) Lock(timeout time.Duration, readOnly bool, onWait func(doWait func() error) error) error {
+	lock.locker = lock.newLocker(timeout, readOnly, onWait)
+	return lock.Base.Lock(lock.locker)
+}
+
+func (lock *Distributed) Unlock() error {
+	if lock.locker == nil {
+		return nil
+	}
+
+	err := lock.Base.Unlock(lock.locker)
+	if err != nil {
+		return err
+	}
+
+	lock.locker = nil
+
+	return nil
+}
+
+func (lock *Distributed) WithLock(timeout time.Duration, readOnly bool, onWait func(doWait func() error) error, f func() error) error {
+	lock.locker = lock.newLocker(timeout, readOnly, onWait)
+
+	err := lock.Base.WithLock(lock.locker, f)
+	if err != nil {
+		return err
+	}
+
+	lock.locker = nil
+
+	return nil
+}
+
+type distributedLocker struct {
+	baseLocker
+
+	DistributedLock *Distributed
+	info            *LockInfo
+	stopRenew       chan struct{}
+	renewDone       chan struct{}
+	// lostErr is set by renew when the lock has been taken over or has not been renewed during ttl
+	lostErr error
+}
+
+func (locker *distributedLocker) name() string {
+	return locker.DistributedLock.Name
+}
+
+func (locker *distributedLocker) recordId() string {
+	if locker.ReadOnly {
+		return locker.info.HolderId
+	}
+	return exclusiveLockRecordId
+}
+
+func (locker *distributedLocker) Lock() error {
+	locker.info = newLockInfo(locker.name(), locker.DistributedLock.TTL, locker.ReadOnly)
+	locker.info.AcquiredAt = time.Now()
+
+	acquired, holders, err := locker.tryAcquire()
+	if err != nil {
+		locker.release()
+		return err
+	}
+
+	if !acquired {
+		logger.LogF("Lock `%s` is held by %s\n", locker.name(), formatLockHolders(holders))
+
+		err := locker.OnWait(func() error {
+			return locker.pollAcquire()
+		})
+		if err != nil {
+			locker.release()
+			return err
+		}
+	}
+
+	locker.stopRenew = make(chan struct{})
+	locker.renewDone = make(chan struct{})
+	locker.lostErr = nil
+	go locker.renew(locker.stopRenew, locker.renewDone)
+
+	return nil
+}
+
+func (locker *distributedLocker) pollAcquire() error {
+	timeout := time.After(locker.Timeout)
+
+	for {
+		select {
+		case <-timeout:
+			return fmt.Errorf("lock `%s` timeout %s expired", locker.name(), locker.Timeout)
+		case <-time.After(distributedLockPollPeriod):
+		}
+
+		acquired, _, err := locker.tryAcquire()
+		if err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+	}
+}
+
+// tryAcquire returns holders which the locker waits for when the lock is busy.
+func (locker *distributedLocker) tryAcquire() (bool, []*LockInfo, error) {
+	if locker.ReadOnly {
+		return locker.tryAcquireShared()
+	}
+	return locker.tryAcquireExclusive()
+}
+
+// tryAcquireExclusive takes the exclusive record first to stop new read-only holders and then waits for the current ones.
+// The taken record is kept and renewed between attempts.
+func (locker *distributedLocker) tryAcquireExclusive() (bool, []*LockInfo, error) {
+	store := locker.DistributedLock.Store
+
+	acquired, holder, err := locker.putRecord(exclusiveLockRecordId)
+	if err != nil || !acquired {
+		return false, holder, err
+	}
+
+	records, err := store.List(locker.name())
+	if err != nil {
+		return false, nil, fmt.Errorf("cannot list lock `%s` records: %s", locker.name(), err)
+	}
+
+	var holders []*LockInfo
+	for _, record := range records {
+		if record.Id == exclusiveLockRecordId || record.Info.HolderId == locker.info.HolderId {
+			continue
+		}
+
+		if record.Info.IsExpired() {
+			logger.LogF("Removing stale lock `%s` of %s\n", locker.name(), record.Info)
+			if err := store.Delete(locker.name(), record.Id, record.Version); err != nil {
+				return false, nil, fmt.Errorf("cannot remove stale lock `%s` record: %s", locker.name(), err)
+			}
+			continue
+		}
+
+		holders = append(holders, record.Info)
+	}
+
+	return len(holders) == 0, holders, nil
+}
+
+// tryAcquireShared puts the record of the read-only holder if there is no exclusive holder.
+// Exclusive record is checked once more after the put, so the exclusive holder either sees the record or the record is removed.
+func (locker *distributedLocker) tryAcquireShared() (bool, []*LockInfo, error) {
+	holder, err := locker.getExclusiveHolder()
+	if err != nil {
+		return false, nil, err
+	}
+	if holder != nil {
+		return false, []*LockInfo{holder}, nil
+	}
+
+	acquired, _, err := locker.putRecord(locker.recordId())
+	if err != nil || !acquired {
+		return false, nil, err
+	}
+
+	holder, err = locker.getExclusiveHolder()
+	if err == nil && holder == nil {
+		return true, nil, nil
+	}
+
+	if releaseErr := locker.release(); releaseErr != nil && err == nil {
+		err = releaseErr
+	}
+	if err != nil {
+		return false, nil, err
+	}
+
+	return false, []*LockInfo{holder}, nil
+}
+
+// getExclusiveHolder returns nil if there is no exclusive holder, stale exclusive record is removed.
+func (locker *distributedLocker) getExclusiveHolder() (*LockInfo, error) {
+	store := locker.DistributedLock.Store
+
+	info, version, err := store.Get(locker.name(), exclusiveLockRecordId)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get lock `%s` record: %s", locker.name(), err)
+	}
+
+	if info == nil {
+		return nil, nil
+	}
+
+	if info.IsExpired() {
+		logger.LogF("Removing stale lock `%s` of %s\n", locker.name(), info)
+		if err := store.Delete(locker.name(), exclusiveLockRecordId, version); err != nil {
+			return nil, fmt.Errorf("cannot remove stale lock `%s` record: %s", locker.name(), err)
+		}
+		return nil, nil
+	}
+
+	return info, nil
+}
+
+// putRecord creates the record of the locker or takes over the stale one.
+// Holder of the record is returned when the record is busy.
+func (locker *distributedLocker) putRecord(id string) (bool, []*LockInfo, error) {
+	store := locker.DistributedLock.Store
+
+	locker.info.RenewedAt = time.Now()
+
+	current, version, err := store.Get(locker.name(), id)
+	if err != nil {
+		return false, nil, fmt.Errorf("cannot get lock `%s` record: %s", locker.name(), err)
+	}
+
+	if current == nil {
+		created, err := store.Create(locker.name(), id, locker.info)
+		if err != nil {
+			return false, nil, fmt.Errorf("cannot create lock `%s` record: %s", locker.name(), err)
+		}
+		return created, nil, nil
+	}
+
+	if current.HolderId != locker.info.HolderId && !current.IsExpired() {
+		return false, []*LockInfo{current}, nil
+	}
+
+	if current.HolderId != locker.info.HolderId {
+		logger.LogF("Taking over stale lock `%s` of %s\n", locker.name(), current)
+	}
+
+	updated, err := store.Update(locker.name(), id, locker.info, version)
+	if err != nil {
+		return false, nil, fmt.Errorf("cannot update lock `%s` record: %s", locker.name(), err)
+	}
+	if !updated {
+		return false, []*LockInfo{current}, nil
+	}
+
+	return true, nil, nil
+}
+
+// renew stops renewing when the lock is lost: the record has been removed or taken over by another holder
+// or the record has not been renewed during ttl because of store errors.
+// The lost lock makes Unlock fail, so the operation done under the lock fails too.
+func (locker *distributedLocker) renew(stop, done chan struct{}) {
+	defer close(done)
+
+	store := locker.DistributedLock.Store
+	renewedAt := time.Now()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(locker.DistributedLock.TTL / 3):
+		}
+
+		current, version, err := store.Get(locker.name(), locker.recordId())
+		if err == nil && current == nil {
+			locker.lose(fmt.Errorf("lock has been removed"))
+			return
+		} else if err == nil && current.HolderId != locker.info.HolderId {
+			locker.lose(fmt.Errorf("lock has been taken over by %s", current))
+			return
+		}
+
+		if err == nil {
+			locker.info.RenewedAt = time.Now()
+
+			var updated bool
+			updated, err = store.Update(locker.name(), locker.recordId(), locker.info, version)
+			if err == nil && !updated {
+				err = fmt.Errorf("lock has been changed concurrently")
+			}
+		}
+
+		if err == nil {
+			renewedAt = locker.info.RenewedAt
+			continue
+		}
+
+		if time.Since(renewedAt) > locker.DistributedLock.TTL {
+			locker.lose(fmt.Errorf("lock has not been renewed during %s: %s", locker.DistributedLock.TTL, err))
+			return
+		}
+
+		logger.LogWarningF("WARNING: cannot renew lock `%s`: %s\n", locker.name(), err)
+	}
+}
+
+func (locker *distributedLocker) lose(err error) {
+	logger.LogErrorF("ERROR lock `%s` lost: %s\n", locker.name(), err)
+	locker.lostErr = err
+}
+
+func (locker *distributedLocker) Unlock() error {
+	close(locker.stopRenew)
+	<-locker.renewDone
+
+	if err := locker.release(); err != nil {
+		return err
+	}
+
+	if locker.lostErr != nil {
+		return fmt.Errorf("lock `%s` has been lost during the operation: %s", locker.name(), locker.lostErr)
+	}
+
+	return nil
+}
+
+// release removes the record of the locker if it is still held by the locker.
+func (locker *distributedLocker) release() error {
+	store := locker.DistributedLock.Store
+
+	current, version, err := store.Get(locker.name(), locker.recordId())
+	if err != nil {
+		return fmt.Errorf("cannot get lock `%s` record: %s", locker.name(), err)
+	}
+
+	if current == nil || current.HolderId != locker.info.HolderId {
+		return nil
+	}
+
+	if err := store.Delete(locker.name(), locker.recordId(), version); err != nil {
+		return fmt.Errorf("cannot remove lock `%s` record: %s", locker.name(), err)
+	}
+
+	return nil
+}
+
+func formatLockHolders(holders []*LockInfo) string {
+	var res []string
+	for _, holder := range holders {
+		res = append(res, holder.String())
+	}
+
+	if len(res) == 0 {
+		return "another holder"
+	}
+
+	return strings.Join(res, "; ")
+}
+
+// GetLockHolders returns holders of all records of the lock in the store including stale ones.
+func GetLockHolders(name string, store LockStore) ([]*LockInfo, error) {
+	records, err := store.List(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list lock `%s` records: %s", name, err)
+	}
+
+	var res []*LockInfo
+	for _, record := range records {
+		res = append(res, record.Info)
+	}
+
+	return res, nil
+}
+
+// ReleaseLock removes all records of the lock in the store regardless of their holders.
+// False is returned if the lock is not held.
+func ReleaseLock(name string, store LockStore) (bool, error) {
+	records, err := store.List(name)
+	if err != nil {
+		return false, fmt.Errorf("cannot list lock `%s` records: %s", name, err)
+	}
+
+	for _, record := range records {
+		if err := store.Delete(name, record.Id, record.Version); err != nil {
+			return false, fmt.Errorf("cannot remove lock `%s` record: %s", name, err)
+		}
+	}
+
+	return len(records) > 0, nil
+}
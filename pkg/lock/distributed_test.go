@@ -0,0 +1,370 @@
+package lock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// etcdStandIn implements the subset of etcd v2 keys API used by HttpStore.
+type etcdStandIn struct {
+	mux   sync.Mutex
+	index uint64
+	nodes map[string]*httpStoreNode
+}
+
+func newEtcdStandIn() *httptest.Server {
+	s := &etcdStandIn{nodes: make(map[string]*httpStoreNode)}
+	return httptest.NewServer(s)
+}
+
+func (s *etcdStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	key := r.URL.Path
+	node := s.nodes[key]
+
+	writeResponse := func(status int, node *httpStoreNode) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(&httpStoreResponse{Node: node})
+	}
+
+	prevIndexMatches := func() bool {
+		prevIndex := r.URL.Query().Get("prevIndex")
+		return prevIndex == "" || prevIndex == strconv.FormatUint(node.ModifiedIndex, 10)
+	}
+
+	switch r.Method {
+	case "GET":
+		if node != nil {
+			writeResponse(http.StatusOK, node)
+			return
+		}
+
+		dir := &httpStoreNode{Key: key, Dir: true}
+		for k, n := range s.nodes {
+			if strings.HasPrefix(k, key+"/") {
+				dir.Nodes = append(dir.Nodes, n)
+			}
+		}
+
+		if len(dir.Nodes) == 0 {
+			writeResponse(http.StatusNotFound, nil)
+			return
+		}
+
+		sort.Slice(dir.Nodes, func(i, j int) bool { return dir.Nodes[i].Key < dir.Nodes[j].Key })
+		writeResponse(http.StatusOK, dir)
+	case "PUT":
+		switch {
+		case r.URL.Query().Get("prevExist") == "false" && node != nil:
+			writeResponse(http.StatusPreconditionFailed, nil)
+		case r.URL.Query().Get("prevIndex") != "" && node == nil:
+			writeResponse(http.StatusNotFound, nil)
+		case node != nil && !prevIndexMatches():
+			writeResponse(http.StatusPreconditionFailed, nil)
+		default:
+			s.index++
+			newNode := &httpStoreNode{Key: key, Value: r.FormValue("value"), ModifiedIndex: s.index}
+			s.nodes[key] = newNode
+
+			if node == nil {
+				writeResponse(http.StatusCreated, newNode)
+			} else {
+				writeResponse(http.StatusOK, newNode)
+			}
+		}
+	case "DELETE":
+		switch {
+		case node == nil:
+			writeResponse(http.StatusNotFound, nil)
+		case !prevIndexMatches():
+			writeResponse(http.StatusPreconditionFailed, nil)
+		default:
+			delete(s.nodes, key)
+			writeResponse(http.StatusOK, node)
+		}
+	}
+}
+
+func testLockStores(t *testing.T, f func(t *testing.T, store LockStore)) {
+	oldPollPeriod := distributedLockPollPeriod
+	distributedLockPollPeriod = 50 * time.Millisecond
+	defer func() { distributedLockPollPeriod = oldPollPeriod }()
+
+	t.Run("http", func(t *testing.T) {
+		server := newEtcdStandIn()
+		defer server.Close()
+
+		f(t, NewHttpStore(server.URL+"/v2/keys/dapp/locks"))
+	})
+
+	t.Run("kubernetes", func(t *testing.T) {
+		f(t, NewKubernetesLeaseStore("default", fake.NewSimpleClientset()))
+	})
+}
+
+func noWait(doWait func() error) error {
+	return doWait()
+}
+
+func TestDistributedLock_Exclusive(t *testing.T) {
+	testLockStores(t, func(t *testing.T, store LockStore) {
+		first := NewDistributedLock("project.images", store, 0)
+		second := NewDistributedLock("project.images", store, 0)
+
+		if err := first.Lock(time.Second, false, noWait); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := second.Lock(200*time.Millisecond, false, noWait); err == nil {
+			t.Fatalf("exclusive lock acquired while held by another holder")
+		}
+
+		if err := second.Lock(200*time.Millisecond, true, noWait); err == nil {
+			t.Fatalf("read-only lock acquired while held by exclusive holder")
+		}
+
+		if err := first.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := second.Lock(time.Second, false, noWait); err != nil {
+			t.Fatalf("exclusive lock not acquired after release: %s", err)
+		}
+
+		if err := second.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestDistributedLock_ReadOnly(t *testing.T) {
+	testLockStores(t, func(t *testing.T, store LockStore) {
+		first := NewDistributedLock("project.images", store, 0)
+		second := NewDistributedLock("project.images", store, 0)
+		exclusive := NewDistributedLock("project.images", store, 0)
+
+		if err := first.Lock(time.Second, true, noWait); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := second.Lock(time.Second, true, noWait); err != nil {
+			t.Fatalf("read-only lock not acquired while held by read-only holder: %s", err)
+		}
+
+		if err := exclusive.Lock(200*time.Millisecond, false, noWait); err == nil {
+			t.Fatalf("exclusive lock acquired while held by read-only holders")
+		}
+
+		// Exclusive record of timed out holder should not block read-only holders
+		if err := first.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+		if err := first.Lock(time.Second, true, noWait); err != nil {
+			t.Fatalf("read-only lock not acquired after exclusive lock timeout: %s", err)
+		}
+
+		done := make(chan error)
+		go func() {
+			done <- exclusive.WithLock(5*time.Second, false, noWait, func() error { return nil })
+		}()
+
+		time.Sleep(200 * time.Millisecond)
+
+		if err := first.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+		if err := second.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("exclusive lock not acquired after read-only holders release: %s", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("exclusive lock not acquired after read-only holders release")
+		}
+
+		records, err := store.List("project.images")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 0 {
+			t.Errorf("\n[EXPECTED]: no records\n[GOT]: %d records", len(records))
+		}
+	})
+}
+
+func TestDistributedLock_Stale(t *testing.T) {
+	testLockStores(t, func(t *testing.T, store LockStore) {
+		stale := newLockInfo("project.images", 2*time.Second, false)
+		stale.AcquiredAt = time.Now().Add(-time.Minute)
+		stale.RenewedAt = stale.AcquiredAt
+
+		if _, err := store.Create("project.images", exclusiveLockRecordId, stale); err != nil {
+			t.Fatal(err)
+		}
+
+		staleReader := newLockInfo("project.images", 2*time.Second, true)
+		staleReader.AcquiredAt = stale.AcquiredAt
+		staleReader.RenewedAt = stale.AcquiredAt
+
+		if _, err := store.Create("project.images", staleReader.HolderId, staleReader); err != nil {
+			t.Fatal(err)
+		}
+
+		l := NewDistributedLock("project.images", store, 0)
+		if err := l.Lock(time.Second, false, noWait); err != nil {
+			t.Fatalf("stale lock not taken over: %s", err)
+		}
+
+		info, _, err := store.Get("project.images", staleReader.HolderId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info != nil {
+			t.Errorf("stale read-only record not removed")
+		}
+
+		if err := l.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestDistributedLock_Renew(t *testing.T) {
+	testLockStores(t, func(t *testing.T, store LockStore) {
+		l := NewDistributedLock("project.images", store, 3*time.Second)
+		if err := l.Lock(time.Second, false, noWait); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(4 * time.Second)
+
+		info, _, err := store.Get("project.images", exclusiveLockRecordId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info == nil || info.IsExpired() {
+			t.Fatalf("lock has not been renewed")
+		}
+
+		other := NewDistributedLock("project.images", store, 3*time.Second)
+		if err := other.Lock(200*time.Millisecond, false, noWait); err == nil {
+			t.Fatalf("renewed lock taken over")
+		}
+
+		if err := l.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestReleaseLock(t *testing.T) {
+	testLockStores(t, func(t *testing.T, store LockStore) {
+		l := NewDistributedLock("helm_release.app", store, 0)
+		if err := l.Lock(time.Second, false, noWait); err != nil {
+			t.Fatal(err)
+		}
+
+		holders, err := GetLockHolders("helm_release.app", store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(holders) != 1 {
+			t.Fatalf("\n[EXPECTED]: 1 holder\n[GOT]: %d holders", len(holders))
+		}
+
+		released, err := ReleaseLock("helm_release.app", store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !released {
+			t.Errorf("\n[EXPECTED]: released\n[GOT]: not held")
+		}
+
+		other := NewDistributedLock("helm_release.app", store, 0)
+		if err := other.WithLock(time.Second, false, noWait, func() error { return nil }); err != nil {
+			t.Fatalf("lock not acquired after release: %s", err)
+		}
+
+		released, err = ReleaseLock("helm_release.app", store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if released {
+			t.Errorf("\n[EXPECTED]: not held\n[GOT]: released")
+		}
+
+		if err := l.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestKubernetesLock(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	l := NewKubernetesLock("helm_release.app", "production", client, 0)
+	if err := l.Lock(time.Second, false, noWait); err != nil {
+		t.Fatal(err)
+	}
+
+	holders, err := GetKubernetesLockInfo("helm_release.app", "production", client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holders) != 1 {
+		t.Fatalf("\n[EXPECTED]: 1 holder\n[GOT]: %d holders", len(holders))
+	}
+
+	holders, err = GetKubernetesLockInfo("helm_release.app", "staging", client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holders) != 0 {
+		t.Errorf("\n[EXPECTED]: no holders in other namespace\n[GOT]: %d holders", len(holders))
+	}
+
+	if err := l.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	if released, err := ReleaseKubernetesLock("helm_release.app", "production", client); err != nil {
+		t.Fatal(err)
+	} else if released {
+		t.Errorf("\n[EXPECTED]: not held after unlock\n[GOT]: released")
+	}
+}
+
+func TestDistributedLock_Lost(t *testing.T) {
+	testLockStores(t, func(t *testing.T, store LockStore) {
+		l := NewDistributedLock("helm_release.app", store, 3*time.Second)
+
+		err := l.WithLock(time.Second, false, noWait, func() error {
+			if _, err := ReleaseLock("helm_release.app", store); err != nil {
+				t.Fatal(err)
+			}
+
+			time.Sleep(2 * time.Second)
+
+			return nil
+		})
+
+		if err == nil {
+			t.Errorf("\n[EXPECTED]: error of the operation under the lost lock\n[GOT]: no error")
+		}
+	})
+}
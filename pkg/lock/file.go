@@ -6,7 +6,8 @@ import (
 	"path/filepath"
 	"syscall"
 	"time"
-	
+
+	"github.com/flant/dapp/pkg/logger"
 	"github.com/flant/dapp/pkg/util"
 )
 
@@ -69,11 +70,57 @@ type fileLocker struct {
 
 	FileLock        *File
 	openFileHandler *os.File
+	holder          *FileLockHolder
+}
+
+func fileLockPath(locksDir, name string) string {
+	return filepath.Join(locksDir, util.MurmurHash(name))
 }
 
 func (locker *fileLocker) lockFilePath() string {
-	fileName := util.MurmurHash(locker.FileLock.GetName())
-	return filepath.Join(locker.FileLock.LocksDir, fileName)
+	return fileLockPath(locker.FileLock.LocksDir, locker.FileLock.GetName())
+}
+
+// Holders returns processes holding the lock except the current one.
+func (lock *File) Holders() []*FileLockHolder {
+	state, err := GetFileLockState(lock.GetName())
+	if err != nil {
+		return nil
+	}
+
+	var res []*FileLockHolder
+	for _, holder := range state.Holders {
+		if holder.Pid != os.Getpid() {
+			res = append(res, holder)
+		}
+	}
+
+	return res
+}
+
+func (locker *fileLocker) writeHolder(waiting bool) {
+	if locker.holder == nil {
+		locker.holder = newFileLockHolder(locker.FileLock.GetName(), locker.ReadOnly)
+	}
+
+	locker.holder.Waiting = waiting
+	locker.holder.StartedAt = time.Now()
+
+	if err := writeFileLockHolder(locker.lockFilePath(), locker.holder); err != nil {
+		logger.LogWarningF("WARNING: cannot write lock `%s` holder record: %s\n", locker.FileLock.GetName(), err)
+	}
+}
+
+func (locker *fileLocker) removeHolder() {
+	if locker.holder == nil {
+		return
+	}
+
+	if err := removeFileLockHolder(locker.holder); err != nil {
+		logger.LogWarningF("WARNING: cannot remove lock `%s` holder record: %s\n", locker.FileLock.GetName(), err)
+	}
+
+	locker.holder = nil
 }
 
 func (locker *fileLocker) Lock() error {
@@ -95,12 +142,22 @@ func (locker *fileLocker) Lock() error {
 	err = syscall.Flock(fd, mode|syscall.LOCK_NB)
 
 	if err == syscall.EWOULDBLOCK {
-		return locker.OnWait(func() error {
+		locker.writeHolder(true)
+
+		err = locker.OnWait(func() error {
 			return locker.pollFlock(fd, mode)
 		})
+		if err != nil {
+			locker.removeHolder()
+			return err
+		}
+	} else if err != nil {
+		return err
 	}
 
-	return err
+	locker.writeHolder(false)
+
+	return nil
 }
 
 func (locker *fileLocker) pollFlock(fd int, mode int) error {
@@ -134,6 +191,8 @@ func (locker *fileLocker) pollFlock(fd int, mode int) error {
 }
 
 func (locker *fileLocker) Unlock() error {
+	locker.removeHolder()
+
 	err := locker.openFileHandler.Close()
 	if err != nil {
 		return err
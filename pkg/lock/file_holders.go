@@ -0,0 +1,273 @@
+package lock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+const fileLockHoldersDirSuffix = ".holders"
+
+var secretArgRegexp = regexp.MustCompile(`(?i)^(--?[a-z0-9-]*(password|token|secret)[a-z0-9-]*)(=.*)?$`)
+
+// FileLockHolder is a record of the process holding or waiting for the file lock.
+// Records are kept in the dir next to the lock file, a record per locker.
+type FileLockHolder struct {
+	Name      string    `json:"name"`
+	Pid       int       `json:"pid"`
+	Host      string    `json:"host"`
+	Command   string    `json:"command"`
+	StartedAt time.Time `json:"startedAt"`
+	ReadOnly  bool      `json:"readOnly"`
+	Waiting   bool      `json:"waiting"`
+
+	recordPath string
+}
+
+// IsAlive returns true if the process is running or it cannot be checked from the current host.
+func (holder *FileLockHolder) IsAlive() bool {
+	if host, _ := os.Hostname(); host != holder.Host {
+		return true
+	}
+
+	err := syscall.Kill(holder.Pid, syscall.Signal(0))
+	return err == nil || err == syscall.EPERM
+}
+
+func (holder *FileLockHolder) String() string {
+	return fmt.Sprintf("pid %d on %s since %s (%s)", holder.Pid, holder.Host, holder.StartedAt.Format(time.RFC3339), holder.Command)
+}
+
+type FileLockState struct {
+	Name    string
+	Holders []*FileLockHolder
+	Waiters []*FileLockHolder
+}
+
+func newFileLockHolder(name string, readOnly bool) *FileLockHolder {
+	holder := &FileLockHolder{
+		Name:      name,
+		Pid:       os.Getpid(),
+		Command:   maskCommandArgs(os.Args),
+		StartedAt: time.Now(),
+		ReadOnly:  readOnly,
+	}
+
+	holder.Host, _ = os.Hostname()
+
+	return holder
+}
+
+func maskCommandArgs(args []string) string {
+	var res []string
+
+	maskNext := false
+	for _, arg := range args {
+		if maskNext {
+			res = append(res, "***")
+			maskNext = false
+			continue
+		}
+
+		if match := secretArgRegexp.FindStringSubmatch(arg); match != nil {
+			if match[3] != "" {
+				res = append(res, match[1]+"=***")
+			} else {
+				res = append(res, arg)
+				maskNext = true
+			}
+			continue
+		}
+
+		res = append(res, arg)
+	}
+
+	return strings.Join(res, " ")
+}
+
+func fileLockHoldersDir(lockFilePath string) string {
+	return lockFilePath + fileLockHoldersDirSuffix
+}
+
+func writeFileLockHolder(lockFilePath string, holder *FileLockHolder) error {
+	if holder.recordPath == "" {
+		dir := fileLockHoldersDir(lockFilePath)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+
+		holder.recordPath = filepath.Join(dir, fmt.Sprintf("%d-%s", holder.Pid, uuid.NewV4().String()))
+	}
+
+	data, err := json.Marshal(holder)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := holder.recordPath + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, holder.recordPath)
+}
+
+func removeFileLockHolder(holder *FileLockHolder) error {
+	if holder.recordPath == "" {
+		return nil
+	}
+
+	err := os.Remove(holder.recordPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Dir is removed when the last record is removed
+	os.Remove(filepath.Dir(holder.recordPath))
+
+	holder.recordPath = ""
+
+	return nil
+}
+
+func readFileLockHolders(holdersDir string) ([]*FileLockHolder, error) {
+	files, err := ioutil.ReadDir(holdersDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var res []*FileLockHolder
+	for _, f := range files {
+		if f.IsDir() || strings.HasSuffix(f.Name(), ".tmp") {
+			continue
+		}
+
+		path := filepath.Join(holdersDir, f.Name())
+
+		data, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		holder := &FileLockHolder{}
+		if err := json.Unmarshal(data, holder); err != nil {
+			continue
+		}
+		holder.recordPath = path
+
+		res = append(res, holder)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].StartedAt.Before(res[j].StartedAt) })
+
+	return res, nil
+}
+
+func newFileLockState(name string, records []*FileLockHolder) *FileLockState {
+	state := &FileLockState{Name: name}
+	for _, record := range records {
+		if record.Waiting {
+			state.Waiters = append(state.Waiters, record)
+		} else {
+			state.Holders = append(state.Holders, record)
+		}
+	}
+
+	return state
+}
+
+// ListFileLocks returns file locks which have holder or waiter records.
+func ListFileLocks() ([]*FileLockState, error) {
+	dirs, err := filepath.Glob(filepath.Join(LocksDir, "*"+fileLockHoldersDirSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*FileLockState
+	for _, dir := range dirs {
+		records, err := readFileLockHolders(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(records) == 0 {
+			continue
+		}
+
+		res = append(res, newFileLockState(records[0].Name, records))
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res, nil
+}
+
+// GetFileLockState returns holders and waiters of the file lock.
+func GetFileLockState(name string) (*FileLockState, error) {
+	records, err := readFileLockHolders(fileLockHoldersDir(fileLockPath(LocksDir, name)))
+	if err != nil {
+		return nil, err
+	}
+
+	return newFileLockState(name, records), nil
+}
+
+// ReleaseFileLock removes records of dead holders and waiters of the file lock.
+// Lock of the alive holder cannot be released: the holder process should be stopped first.
+func ReleaseFileLock(name string) ([]*FileLockHolder, error) {
+	lockFilePath := fileLockPath(LocksDir, name)
+
+	records, err := readFileLockHolders(fileLockHoldersDir(lockFilePath))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if !record.Waiting && record.IsAlive() {
+			return nil, fmt.Errorf("lock `%s` is held by alive process %s", name, record)
+		}
+	}
+
+	var released []*FileLockHolder
+	for _, record := range records {
+		if record.IsAlive() {
+			continue
+		}
+
+		if err := removeFileLockHolder(record); err != nil {
+			return nil, err
+		}
+
+		released = append(released, record)
+	}
+
+	// Lock of the dead process is released by the system, lock could be held by the process without record though
+	f, err := os.OpenFile(lockFilePath, os.O_RDWR, 0644)
+	if os.IsNotExist(err) {
+		return released, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err == syscall.EWOULDBLOCK {
+		return released, fmt.Errorf("lock `%s` is held by the process without holder record (check processes which opened %s)", name, lockFilePath)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return released, nil
+}
@@ -0,0 +1,76 @@
+package lock
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFileLockHolders(t *testing.T) {
+	locksDir, err := ioutil.TempDir("", "dapp-locks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(locksDir)
+
+	oldLocksDir := LocksDir
+	LocksDir = locksDir
+	defer func() { LocksDir = oldLocksDir }()
+
+	l := NewFileLock("project.images", locksDir)
+	if err := l.Lock(time.Second, false, noWait); err != nil {
+		t.Fatal(err)
+	}
+
+	dead := newFileLockHolder("project.images", true)
+	dead.Pid = 1 << 22 // above pid_max
+	dead.Waiting = true
+	if err := writeFileLockHolder(fileLockPath(locksDir, "project.images"), dead); err != nil {
+		t.Fatal(err)
+	}
+
+	states, err := ListFileLocks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(states) != 1 || states[0].Name != "project.images" || len(states[0].Holders) != 1 || len(states[0].Waiters) != 1 {
+		t.Fatalf("unexpected lock states: %#v", states)
+	}
+
+	if _, err := ReleaseFileLock("project.images"); err == nil {
+		t.Fatalf("lock of alive holder released")
+	}
+
+	if err := l.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	released, err := ReleaseFileLock("project.images")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(released) != 1 || released[0].Pid != dead.Pid {
+		t.Fatalf("unexpected released holders: %#v", released)
+	}
+
+	states, err = ListFileLocks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(states) != 0 {
+		t.Errorf("\n[EXPECTED]: no locks\n[GOT]: %d locks", len(states))
+	}
+}
+
+func TestMaskCommandArgs(t *testing.T) {
+	args := []string{"dapp", "cleanup", "--registry-password", "qwerty", "--secret-key=abc", "--repo", "registry/app"}
+	expected := "dapp cleanup --registry-password *** --secret-key=*** --repo registry/app"
+
+	if got := maskCommandArgs(args); got != expected {
+		t.Errorf("\n[EXPECTED]: %s\n[GOT]: %s", expected, got)
+	}
+}
@@ -0,0 +1,214 @@
+package lock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flant/dapp/pkg/util"
+)
+
+// NewHttpStore creates store which keeps lock records in the key-value service with etcd v2 keys API
+// (e.g. http://etcd:2379/v2/keys/dapp/locks).
+// Note that v2 API is disabled by default since etcd 3.4 (--enable-v2 is required) and removed in etcd 3.6,
+// v3 gRPC gateway is not supported.
+// Records are put with ttl, so the service removes records of stale holders too.
+func NewHttpStore(baseUrl string) LockStore {
+	return &HttpStore{BaseUrl: strings.TrimRight(baseUrl, "/"), Client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+type HttpStore struct {
+	BaseUrl string
+	Client  *http.Client
+}
+
+type httpStoreNode struct {
+	Key           string           `json:"key"`
+	Value         string           `json:"value"`
+	Dir           bool             `json:"dir"`
+	ModifiedIndex uint64           `json:"modifiedIndex"`
+	Nodes         []*httpStoreNode `json:"nodes"`
+}
+
+type httpStoreResponse struct {
+	Node    *httpStoreNode `json:"node"`
+	Message string         `json:"message"`
+}
+
+func (store *HttpStore) lockUrl(name string) string {
+	return fmt.Sprintf("%s/%s", store.BaseUrl, util.MurmurHash(name))
+}
+
+func (store *HttpStore) recordUrl(name, id string) string {
+	return fmt.Sprintf("%s/%s", store.lockUrl(name), url.PathEscape(id))
+}
+
+func (store *HttpStore) Get(name, id string) (*LockInfo, string, error) {
+	status, resp, err := store.do("GET", store.recordUrl(name, id), nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch status {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, "", nil
+	default:
+		return nil, "", httpStoreError(status, resp)
+	}
+
+	info, err := parseHttpStoreNode(resp.Node)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return info, strconv.FormatUint(resp.Node.ModifiedIndex, 10), nil
+}
+
+func (store *HttpStore) Create(name, id string, info *LockInfo) (bool, error) {
+	return store.put(name, id, info, url.Values{"prevExist": []string{"false"}})
+}
+
+func (store *HttpStore) Update(name, id string, info *LockInfo, version string) (bool, error) {
+	return store.put(name, id, info, url.Values{"prevIndex": []string{version}})
+}
+
+func (store *HttpStore) put(name, id string, info *LockInfo, params url.Values) (bool, error) {
+	value, err := json.Marshal(lockInfoData(info))
+	if err != nil {
+		return false, err
+	}
+
+	form := url.Values{
+		"value": []string{string(value)},
+		"ttl":   []string{strconv.Itoa(int(info.TTL.Seconds()))},
+	}
+
+	status, resp, err := store.do("PUT", store.recordUrl(name, id), params, form)
+	if err != nil {
+		return false, err
+	}
+
+	switch status {
+	case http.StatusOK, http.StatusCreated:
+		return true, nil
+	case http.StatusPreconditionFailed, http.StatusNotFound:
+		return false, nil
+	default:
+		return false, httpStoreError(status, resp)
+	}
+}
+
+func (store *HttpStore) Delete(name, id string, version string) error {
+	status, resp, err := store.do("DELETE", store.recordUrl(name, id), url.Values{"prevIndex": []string{version}}, nil)
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case http.StatusOK, http.StatusNotFound, http.StatusPreconditionFailed:
+		return nil
+	default:
+		return httpStoreError(status, resp)
+	}
+}
+
+func (store *HttpStore) List(name string) ([]*LockRecord, error) {
+	status, resp, err := store.do("GET", store.lockUrl(name), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, httpStoreError(status, resp)
+	}
+
+	var res []*LockRecord
+	for _, node := range resp.Node.Nodes {
+		if node.Dir {
+			continue
+		}
+
+		info, err := parseHttpStoreNode(node)
+		if err != nil {
+			return nil, err
+		}
+
+		if info.Name != name {
+			continue
+		}
+
+		res = append(res, &LockRecord{
+			Id:      path.Base(node.Key),
+			Info:    info,
+			Version: strconv.FormatUint(node.ModifiedIndex, 10),
+		})
+	}
+
+	return res, nil
+}
+
+func (store *HttpStore) do(method, rawUrl string, params, form url.Values) (int, *httpStoreResponse, error) {
+	if len(params) > 0 {
+		rawUrl = fmt.Sprintf("%s?%s", rawUrl, params.Encode())
+	}
+
+	req, err := http.NewRequest(method, rawUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	res, err := store.Client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	resp := &httpStoreResponse{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, resp); err != nil && res.StatusCode < 300 {
+			return 0, nil, fmt.Errorf("bad response %s %s: %s", method, rawUrl, err)
+		}
+	}
+
+	if res.StatusCode < 300 && resp.Node == nil {
+		return 0, nil, fmt.Errorf("bad response %s %s: no node", method, rawUrl)
+	}
+
+	return res.StatusCode, resp, nil
+}
+
+func parseHttpStoreNode(node *httpStoreNode) (*LockInfo, error) {
+	data := make(map[string]string)
+	if err := json.Unmarshal([]byte(node.Value), &data); err != nil {
+		return nil, fmt.Errorf("bad lock record %s: %s", node.Key, err)
+	}
+
+	return parseLockInfo(data), nil
+}
+
+func httpStoreError(status int, resp *httpStoreResponse) error {
+	if resp.Message != "" {
+		return fmt.Errorf("%s: %s", http.StatusText(status), resp.Message)
+	}
+	return fmt.Errorf("%s", http.StatusText(status))
+}
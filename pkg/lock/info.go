@@ -0,0 +1,135 @@
+package lock
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+const (
+	DefaultLockTTL = 30 * time.Second
+
+	lockNameKey       = "name"
+	lockHolderIdKey   = "holder-id"
+	lockHolderKey     = "holder"
+	lockHostKey       = "host"
+	lockUserKey       = "user"
+	lockCiRunnerKey   = "ci-runner"
+	lockCiPipelineKey = "ci-pipeline"
+	lockAcquiredAtKey = "acquired-at"
+	lockRenewedAtKey  = "renewed-at"
+	lockTTLKey        = "ttl"
+	lockReadOnlyKey   = "read-only"
+)
+
+// LockInfo describes the holder of the lock shared between hosts.
+type LockInfo struct {
+	Name       string
+	HolderId   string
+	Holder     string
+	Host       string
+	User       string
+	CiRunner   string
+	CiPipeline string
+	AcquiredAt time.Time
+	RenewedAt  time.Time
+	TTL        time.Duration
+	ReadOnly   bool
+}
+
+func (info *LockInfo) IsExpired() bool {
+	return time.Now().After(info.RenewedAt.Add(info.TTL))
+}
+
+func (info *LockInfo) String() string {
+	res := []string{fmt.Sprintf("holder %s", info.Holder)}
+	if info.CiRunner != "" {
+		res = append(res, fmt.Sprintf("ci runner %s", info.CiRunner))
+	}
+	if info.CiPipeline != "" {
+		res = append(res, fmt.Sprintf("ci pipeline %s", info.CiPipeline))
+	}
+	res = append(res, fmt.Sprintf("acquired at %s", info.AcquiredAt.Format(time.RFC3339)))
+
+	return strings.Join(res, ", ")
+}
+
+func newLockInfo(name string, ttl time.Duration, readOnly bool) *LockInfo {
+	info := &LockInfo{
+		Name:       name,
+		HolderId:   uuid.NewV4().String(),
+		CiRunner:   os.Getenv("CI_RUNNER_DESCRIPTION"),
+		CiPipeline: os.Getenv("CI_PIPELINE_URL"),
+		TTL:        ttl,
+		ReadOnly:   readOnly,
+	}
+
+	if info.CiRunner == "" {
+		info.CiRunner = os.Getenv("CI_RUNNER_ID")
+	}
+	if info.CiPipeline == "" {
+		info.CiPipeline = os.Getenv("CI_PIPELINE_ID")
+	}
+
+	info.Host, _ = os.Hostname()
+
+	info.User = os.Getenv("GITLAB_USER_LOGIN")
+	if info.User == "" {
+		if u, err := user.Current(); err == nil {
+			info.User = u.Username
+		}
+	}
+
+	info.Holder = fmt.Sprintf("%s@%s (pid %d)", info.User, info.Host, os.Getpid())
+
+	return info
+}
+
+func lockInfoData(info *LockInfo) map[string]string {
+	data := map[string]string{
+		lockNameKey:       info.Name,
+		lockHolderIdKey:   info.HolderId,
+		lockHolderKey:     info.Holder,
+		lockHostKey:       info.Host,
+		lockUserKey:       info.User,
+		lockCiRunnerKey:   info.CiRunner,
+		lockCiPipelineKey: info.CiPipeline,
+		lockAcquiredAtKey: info.AcquiredAt.UTC().Format(time.RFC3339),
+		lockRenewedAtKey:  info.RenewedAt.UTC().Format(time.RFC3339),
+		lockTTLKey:        strconv.Itoa(int(info.TTL.Seconds())),
+	}
+
+	if info.ReadOnly {
+		data[lockReadOnlyKey] = "true"
+	}
+
+	return data
+}
+
+func parseLockInfo(data map[string]string) *LockInfo {
+	info := &LockInfo{
+		Name:       data[lockNameKey],
+		HolderId:   data[lockHolderIdKey],
+		Holder:     data[lockHolderKey],
+		Host:       data[lockHostKey],
+		User:       data[lockUserKey],
+		CiRunner:   data[lockCiRunnerKey],
+		CiPipeline: data[lockCiPipelineKey],
+		TTL:        DefaultLockTTL,
+		ReadOnly:   data[lockReadOnlyKey] == "true",
+	}
+
+	info.AcquiredAt, _ = time.Parse(time.RFC3339, data[lockAcquiredAtKey])
+	info.RenewedAt, _ = time.Parse(time.RFC3339, data[lockRenewedAtKey])
+
+	if ttl, err := strconv.Atoi(data[lockTTLKey]); err == nil {
+		info.TTL = time.Duration(ttl) * time.Second
+	}
+
+	return info
+}
@@ -0,0 +1,42 @@
+package lock
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	KubernetesLockLabel = "dapp-lock"
+
+	DefaultKubernetesLockTTL = DefaultLockTTL
+)
+
+var kubernetesLockNameRegexp = regexp.MustCompile("[^a-z0-9.-]+")
+
+// NewKubernetesLock creates lock stored in the Lease objects of the namespace.
+// It is the distributed lock with the same records, renewal and stale holders handling as the kubernetes lock backend.
+func NewKubernetesLock(name, namespace string, client kubernetes.Interface, ttl time.Duration) LockObject {
+	if ttl == 0 {
+		ttl = DefaultKubernetesLockTTL
+	}
+
+	return NewDistributedLock(name, NewKubernetesLeaseStore(namespace, client), ttl)
+}
+
+func KubernetesLockObjectName(name string) string {
+	return fmt.Sprintf("dapp-lock.%s", strings.Trim(kubernetesLockNameRegexp.ReplaceAllString(strings.ToLower(name), "-"), "-."))
+}
+
+// GetKubernetesLockInfo returns holders of the lock, no holders are returned if the lock is not held.
+func GetKubernetesLockInfo(name, namespace string, client kubernetes.Interface) ([]*LockInfo, error) {
+	return GetLockHolders(name, NewKubernetesLeaseStore(namespace, client))
+}
+
+// ReleaseKubernetesLock removes the lock regardless of its holders, false is returned if the lock is not held.
+func ReleaseKubernetesLock(name, namespace string, client kubernetes.Interface) (bool, error) {
+	return ReleaseLock(name, NewKubernetesLeaseStore(namespace, client))
+}
@@ -0,0 +1,193 @@
+package lock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	coordinationv1beta1 "k8s.io/api/coordination/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coordinationv1beta1client "k8s.io/client-go/kubernetes/typed/coordination/v1beta1"
+
+	"github.com/flant/dapp/pkg/util"
+)
+
+const (
+	kubernetesLockNameLabel     = "dapp-lock-name"
+	kubernetesLockRecordIdLabel = "dapp-lock-record-id"
+
+	kubernetesLockAnnotationPrefix = "dapp-lock/"
+)
+
+// NewKubernetesLeaseStore creates store which keeps lock records in the Lease objects of the namespace.
+func NewKubernetesLeaseStore(namespace string, client kubernetes.Interface) LockStore {
+	return &KubernetesLeaseStore{Namespace: namespace, Client: client}
+}
+
+type KubernetesLeaseStore struct {
+	Namespace string
+	Client    kubernetes.Interface
+}
+
+func KubernetesLockLeaseName(name, id string) string {
+	return fmt.Sprintf("%s.%s", KubernetesLockObjectName(name), id)
+}
+
+func (store *KubernetesLeaseStore) Get(name, id string) (*LockInfo, string, error) {
+	lease, err := store.leases().Get(KubernetesLockLeaseName(name, id), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, "", nil
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	return parseLeaseLockInfo(lease), lease.ResourceVersion, nil
+}
+
+func (store *KubernetesLeaseStore) Create(name, id string, info *LockInfo) (bool, error) {
+	lease := &coordinationv1beta1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: KubernetesLockLeaseName(name, id),
+			Labels: map[string]string{
+				KubernetesLockLabel:         "true",
+				kubernetesLockNameLabel:     util.MurmurHash(name),
+				kubernetesLockRecordIdLabel: id,
+			},
+		},
+	}
+	setLeaseLockInfo(lease, info)
+
+	_, err := store.leases().Create(lease)
+	if apierrors.IsAlreadyExists(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (store *KubernetesLeaseStore) Update(name, id string, info *LockInfo, version string) (bool, error) {
+	lease, err := store.leases().Get(KubernetesLockLeaseName(name, id), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if lease.ResourceVersion != version {
+		return false, nil
+	}
+
+	setLeaseLockInfo(lease, info)
+
+	_, err = store.leases().Update(lease)
+	if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (store *KubernetesLeaseStore) Delete(name, id string, version string) error {
+	lease, err := store.leases().Get(KubernetesLockLeaseName(name, id), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if lease.ResourceVersion != version {
+		return nil
+	}
+
+	err = store.leases().Delete(lease.Name, &metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &lease.UID}})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (store *KubernetesLeaseStore) List(name string) ([]*LockRecord, error) {
+	list, err := store.leases().List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", kubernetesLockNameLabel, util.MurmurHash(name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*LockRecord
+	for i := range list.Items {
+		lease := &list.Items[i]
+
+		info := parseLeaseLockInfo(lease)
+		if info.Name != name {
+			continue
+		}
+
+		res = append(res, &LockRecord{
+			Id:      lease.Labels[kubernetesLockRecordIdLabel],
+			Info:    info,
+			Version: lease.ResourceVersion,
+		})
+	}
+
+	return res, nil
+}
+
+func (store *KubernetesLeaseStore) leases() coordinationv1beta1client.LeaseInterface {
+	return store.Client.CoordinationV1beta1().Leases(store.Namespace)
+}
+
+// setLeaseLockInfo keeps holder in the lease spec and the rest of the info in annotations.
+func setLeaseLockInfo(lease *coordinationv1beta1.Lease, info *LockInfo) {
+	holderId := info.HolderId
+	ttlSeconds := int32(info.TTL.Seconds())
+	acquireTime := metav1.NewMicroTime(info.AcquiredAt)
+	renewTime := metav1.NewMicroTime(info.RenewedAt)
+
+	lease.Spec = coordinationv1beta1.LeaseSpec{
+		HolderIdentity:       &holderId,
+		LeaseDurationSeconds: &ttlSeconds,
+		AcquireTime:          &acquireTime,
+		RenewTime:            &renewTime,
+	}
+
+	if lease.Annotations == nil {
+		lease.Annotations = make(map[string]string)
+	}
+	for k, v := range lockInfoData(info) {
+		lease.Annotations[kubernetesLockAnnotationPrefix+k] = v
+	}
+}
+
+func parseLeaseLockInfo(lease *coordinationv1beta1.Lease) *LockInfo {
+	data := make(map[string]string)
+	for k, v := range lease.Annotations {
+		if strings.HasPrefix(k, kubernetesLockAnnotationPrefix) {
+			data[strings.TrimPrefix(k, kubernetesLockAnnotationPrefix)] = v
+		}
+	}
+
+	info := parseLockInfo(data)
+
+	if lease.Spec.HolderIdentity != nil {
+		info.HolderId = *lease.Spec.HolderIdentity
+	}
+	if lease.Spec.LeaseDurationSeconds != nil {
+		info.TTL = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	if lease.Spec.AcquireTime != nil {
+		info.AcquiredAt = lease.Spec.AcquireTime.Time
+	}
+	if lease.Spec.RenewTime != nil {
+		info.RenewedAt = lease.Spec.RenewTime.Time
+	}
+
+	return info
+}
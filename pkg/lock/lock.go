@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/logger"
+
+	"k8s.io/client-go/kubernetes"
 )
 
 var (
@@ -37,7 +41,7 @@ func Lock(name string, opts LockOptions) error {
 
 	return lock.Lock(
 		getTimeout(opts), opts.ReadOnly,
-		func(doWait func() error) error { return onWait(name, doWait) },
+		func(doWait func() error) error { return onWait(lock, doWait) },
 	)
 }
 
@@ -56,20 +60,29 @@ func WithLock(name string, opts LockOptions, f func() error) error {
 
 	return lock.WithLock(
 		getTimeout(opts), opts.ReadOnly,
-		func(doWait func() error) error { return onWait(name, doWait) },
+		func(doWait func() error) error { return onWait(lock, doWait) },
 		f,
 	)
 }
 
-func onWait(name string, doWait func() error) error {
-	fmt.Printf("Waiting for locked resource `%s` ...\n", name)
+func onWait(lock LockObject, doWait func() error) error {
+	name := lock.GetName()
+
+	var heldBy string
+	if fileLock, ok := lock.(*File); ok {
+		if holders := fileLock.Holders(); len(holders) > 0 {
+			heldBy = fmt.Sprintf(" held by %s", formatFileLockHolders(holders))
+		}
+	}
+
+	logger.LogF("Waiting for locked resource `%s`%s ...\n", name, heldBy)
 
 	err := doWait()
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Waiting for locked resource `%s` DONE\n", name)
+	logger.LogF("Waiting for locked resource `%s` DONE\n", name)
 
 	return err
 }
@@ -86,7 +99,7 @@ func getLock(name string) LockObject {
 		return l
 	}
 
-	Locks[name] = NewFileLock(name, LocksDir)
+	Locks[name] = newLock(name)
 
 	return Locks[name]
 }
@@ -97,3 +110,29 @@ type LockObject interface {
 	Unlock() error
 	WithLock(timeout time.Duration, readOnly bool, onWait func(doWait func() error) error, f func() error) error
 }
+
+type KubernetesLockOptions struct {
+	LockOptions
+	Namespace string
+	Client    kubernetes.Interface
+	TTL       time.Duration
+}
+
+func WithKubernetesLock(name string, opts KubernetesLockOptions, f func() error) error {
+	lock := NewKubernetesLock(name, opts.Namespace, opts.Client, opts.TTL)
+
+	return lock.WithLock(
+		getTimeout(opts.LockOptions), opts.ReadOnly,
+		func(doWait func() error) error { return onWait(lock, doWait) },
+		f,
+	)
+}
+
+func formatFileLockHolders(holders []*FileLockHolder) string {
+	var res []string
+	for _, holder := range holders {
+		res = append(res, holder.String())
+	}
+
+	return strings.Join(res, "; ")
+}
@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	TerminalFormat = "terminal"
+	TextFormat     = "text"
+	JSONFormat     = "json"
+)
+
+var (
+	Formats = []string{TerminalFormat, TextFormat, JSONFormat}
+
+	sinks   = []*OutputSink{{Sink: NewTerminalSink(os.Stdout, os.Stderr), MinLevel: DebugLevel}}
+	logFile *os.File
+)
+
+type OutputSink struct {
+	Sink     Sink
+	MinLevel Level
+}
+
+type Options struct {
+	// Level spec, see SetLevel
+	Level string
+	// Format of the output: terminal, text or json
+	Format string
+	// File to write the log in addition to the output (text or json format)
+	File string
+	// Quiet output: only errors are written, the log file is not affected
+	Quiet bool
+	// Disable live progress on the terminal, see StartProgress
+	NoProgress bool
+}
+
+func Init(opts Options) error {
+	if opts.Level != "" {
+		if err := SetLevel(opts.Level); err != nil {
+			return err
+		}
+	}
+
+	outputSink, err := newFormatSink(opts.Format, os.Stdout, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	newSinks := []*OutputSink{{Sink: outputSink, MinLevel: DebugLevel}}
+	if opts.Quiet {
+		newSinks[0].MinLevel = ErrorLevel
+	}
+
+	if opts.File != "" {
+		f, err := os.OpenFile(opts.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("cannot open log file: %s", err)
+		}
+
+		fileSink := NewTextSink(f)
+		if opts.Format == JSONFormat {
+			fileSink = NewJSONSink(f)
+		}
+
+		newSinks = append(newSinks, &OutputSink{Sink: fileSink, MinLevel: DebugLevel})
+
+		logFile = f
+	}
+
+	mux.Lock()
+	sinks = newSinks
+	progressEnabled = !opts.NoProgress
+	mux.Unlock()
+
+	return nil
+}
+
+func newFormatSink(format string, out, err io.Writer) (Sink, error) {
+	switch format {
+	case "", TerminalFormat:
+		return NewTerminalSink(out, err), nil
+	case TextFormat:
+		return NewTextSink(out), nil
+	case JSONFormat:
+		return NewJSONSink(out), nil
+	default:
+		return nil, fmt.Errorf("bad log format `%s`: expected %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+func Close() error {
+	if logFile == nil {
+		return nil
+	}
+
+	err := logFile.Close()
+	logFile = nil
+
+	return err
+}
+
+// GetOutStream returns writer for the output of subprocesses, e.g. git or docker.
+// The output goes to stdout as is if there is only terminal output.
+func GetOutStream() io.Writer {
+	if isPlainTerminalOutput() {
+		return &terminalStream{file: os.Stdout, stream: &streamWriter{}}
+	}
+	return &streamWriter{}
+}
+
+func GetErrStream() io.Writer {
+	if isPlainTerminalOutput() {
+		return &terminalStream{file: os.Stderr, stream: &streamWriter{stderr: true}}
+	}
+	return &streamWriter{stderr: true}
+}
+
+func isPlainTerminalOutput() bool {
+	mux.Lock()
+	defer mux.Unlock()
+
+	if len(sinks) != 1 || sinks[0].MinLevel > InfoLevel {
+		return false
+	}
+
+	_, ok := sinks[0].Sink.(*terminalSink)
+	return ok
+}
+
+// streamWriter writes complete lines of the output as raw entries.
+type streamWriter struct {
+	stderr bool
+	buf    bytes.Buffer
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+
+	for {
+		ind := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if ind == -1 {
+			break
+		}
+
+		line := string(w.buf.Next(ind + 1))
+		write(&Entry{Level: InfoLevel, Msg: line, Stderr: w.stderr, kind: rawEntry})
+	}
+
+	return len(p), nil
+}
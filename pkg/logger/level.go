@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+var (
+	LevelNames = []string{"debug", "info", "warn", "error"}
+
+	level       = InfoLevel
+	scopeLevels = map[string]Level{}
+
+	// Env switches of the previous versions enable debug level of the scope
+	legacyDebugEnvs = map[string]string{
+		"DAPP_BUILD_DEBUG":                 "build",
+		"DAPP_DEBUG_DOCKER":                "docker",
+		"DAPP_DEPLOY_DEBUG":                "deploy",
+		"DAPP_DEBUG_GIT_REPO_CHECKSUM":     "git_repo.checksum",
+		"DAPP_TRUE_GIT_DEBUG_PATCH":        "true_git.patch",
+		"DAPP_TRUE_GIT_DEBUG_ARCHIVE":      "true_git.archive",
+		"DAPP_TRUE_GIT_DEBUG_PATCH_PARSER": "true_git.patch_parser",
+	}
+)
+
+func init() {
+	setLegacyDebugScopes()
+}
+
+func (l Level) String() string {
+	if int(l) < len(LevelNames) {
+		return LevelNames[l]
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+func ParseLevel(s string) (Level, error) {
+	for ind, name := range LevelNames {
+		if name == strings.ToLower(s) {
+			return Level(ind), nil
+		}
+	}
+
+	return 0, fmt.Errorf("bad log level `%s`: expected %s", s, strings.Join(LevelNames, ", "))
+}
+
+// SetLevel sets global level and levels of scopes by comma separated spec: LEVEL[,SCOPE=LEVEL...], e.g. info,deploy=debug.
+// Level of the scope is applied to nested scopes: true_git=debug enables true_git.patch debug.
+func SetLevel(spec string) error {
+	newLevel := InfoLevel
+	newScopeLevels := map[string]Level{}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if ind := strings.Index(part, "="); ind != -1 {
+			scopeLevel, err := ParseLevel(part[ind+1:])
+			if err != nil {
+				return err
+			}
+			newScopeLevels[part[:ind]] = scopeLevel
+			continue
+		}
+
+		l, err := ParseLevel(part)
+		if err != nil {
+			return err
+		}
+		newLevel = l
+	}
+
+	level = newLevel
+	scopeLevels = newScopeLevels
+	setLegacyDebugScopes()
+
+	return nil
+}
+
+func setLegacyDebugScopes() {
+	for env, scope := range legacyDebugEnvs {
+		if _, hasKey := scopeLevels[scope]; !hasKey && os.Getenv(env) == "1" {
+			scopeLevels[scope] = DebugLevel
+		}
+	}
+}
+
+// GetLevel returns level of the scope, global level is returned for empty scope.
+func GetLevel(scope string) Level {
+	for s := scope; s != ""; {
+		if l, hasKey := scopeLevels[s]; hasKey {
+			return l
+		}
+
+		ind := strings.LastIndex(s, ".")
+		if ind == -1 {
+			break
+		}
+		s = s[:ind]
+	}
+
+	return level
+}
+
+func IsDebug(scope string) bool {
+	return GetLevel(scope) <= DebugLevel
+}
+
+func isEnabled(scope string, l Level) bool {
+	return l >= GetLevel(scope)
+}
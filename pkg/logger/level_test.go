@@ -0,0 +1,39 @@
+package logger
+
+import "testing"
+
+func TestSetLevel(t *testing.T) {
+	defer SetLevel("")
+
+	if err := SetLevel("warn,deploy=debug,true_git=error,true_git.patch=info"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectations := map[string]Level{
+		"":                      WarnLevel,
+		"build":                 WarnLevel,
+		"deploy":                DebugLevel,
+		"deploy.track":          DebugLevel,
+		"deployment":            WarnLevel,
+		"true_git":              ErrorLevel,
+		"true_git.archive":      ErrorLevel,
+		"true_git.patch":        InfoLevel,
+		"true_git.patch_parser": ErrorLevel,
+	}
+
+	for scope, expected := range expectations {
+		if got := GetLevel(scope); got != expected {
+			t.Errorf("\n[SCOPE]: %q\n[EXPECTED]: %s\n[GOT]: %s", scope, expected, got)
+		}
+	}
+}
+
+func TestSetLevel_negative(t *testing.T) {
+	defer SetLevel("")
+
+	for _, spec := range []string{"verbose", "info,deploy=trace"} {
+		if err := SetLevel(spec); err == nil {
+			t.Errorf("\n[SPEC]: %q\n[EXPECTED]: error\n[GOT]: no error", spec)
+		}
+	}
+}
@@ -2,53 +2,52 @@ package logger
 
 import (
 	"fmt"
-	"io"
-	"os"
-	"strings"
+	"sync"
 	"time"
-
-	"github.com/fatih/color"
-	"golang.org/x/crypto/ssh/terminal"
 )
 
 const (
-	defaultTerminalWidth = 120
-
 	logProcessDefaultProcessMsg      = "[RUNNING]"
 	logProcessSuccessStatus          = "[OK]"
 	logProcessFailedStatus           = "[FAILED]"
-	logProcessTimeFormat             = "%5.2f sec "
 	logProcessInlineProcessMsgFormat = "%s ..."
-
-	logStateRightPartsSeparator = " "
 )
 
 var (
 	indent = 0
+	mux    sync.Mutex
 )
 
 func LogProcessInline(msg string, processFunc func() error) error {
-	return logProcessInlineBase(msg, processFunc, colorizeStep, colorizeSuccess)
+	return logProcessInlineBase(msg, processFunc, StepStyle, SuccessStyle)
 }
 
 func LogServiceProcessInline(msg string, processFunc func() error) error {
-	return logProcessInlineBase(msg, processFunc, colorizeService, colorizeService)
+	return logProcessInlineBase(msg, processFunc, ServiceStyle, ServiceStyle)
 }
 
 func LogProcess(msg, processMsg string, processFunc func() error) error {
-	return logProcessBase(msg, processMsg, processFunc, colorizeStep, colorizeSuccess)
+	return logProcessBase(msg, processMsg, processFunc, StepStyle, SuccessStyle)
 }
 
 func LogServiceProcess(msg, processMsg string, processFunc func() error) error {
-	return logProcessBase(msg, processMsg, processFunc, colorizeService, colorizeService)
+	return logProcessBase(msg, processMsg, processFunc, ServiceStyle, ServiceStyle)
 }
 
 func LogState(msg, state string) {
-	logStateBase(msg, state, "", colorizeStep, colorizeService)
+	logStateBase(msg, state, 0, StepStyle, ServiceStyle)
 }
 
 func LogServiceState(msg, state string) {
-	logStateBase(msg, state, "", colorizeService, colorizeService)
+	logStateBase(msg, state, 0, ServiceStyle, ServiceStyle)
+}
+
+func Log(msg string) {
+	LogF("%s\n", msg)
+}
+
+func LogF(format string, args ...interface{}) {
+	logMessageF(InfoLevel, "", NoStyle, format, args...)
 }
 
 func LogStep(msg string) {
@@ -56,7 +55,7 @@ func LogStep(msg string) {
 }
 
 func LogStepF(format string, args ...interface{}) {
-	colorizeLogBaseF(os.Stdout, colorizeStep, format, args...)
+	logMessageF(InfoLevel, "", StepStyle, format, args...)
 }
 
 func LogService(msg string) {
@@ -64,7 +63,7 @@ func LogService(msg string) {
 }
 
 func LogServiceF(format string, args ...interface{}) {
-	colorizeLogBaseF(os.Stdout, colorizeService, format, args...)
+	logMessageF(InfoLevel, "", ServiceStyle, format, args...)
 }
 
 func LogInfo(msg string) {
@@ -72,7 +71,7 @@ func LogInfo(msg string) {
 }
 
 func LogInfoF(format string, args ...interface{}) {
-	colorizeLogBaseF(os.Stdout, colorizeInfo, format, args...)
+	logMessageF(InfoLevel, "", InfoStyle, format, args...)
 }
 
 func LogWarning(msg string) {
@@ -80,47 +79,28 @@ func LogWarning(msg string) {
 }
 
 func LogWarningF(format string, args ...interface{}) {
-	colorizeLogBaseF(os.Stderr, colorizeWarning, format, args...)
+	logMessageF(WarnLevel, "", WarningStyle, format, args...)
 }
 
-func colorizeLogBaseF(w io.Writer, colorizeFunc func(string) string, format string, args ...interface{}) {
-	var colorizeLines []string
-	lines := strings.Split(fmt.Sprintf(format, args...), "\n")
-	for _, line := range lines {
-		if line == "" {
-			colorizeLines = append(colorizeLines, line)
-		} else {
-			colorizeLines = append(colorizeLines, colorizeFunc(line))
-		}
-	}
-
-	logF(w, strings.Join(colorizeLines, "\n"))
+func LogError(msg string) {
+	LogErrorF("%s\n", msg)
 }
 
-func log(w io.Writer, msg string) {
-	logF(w, "%s\n", msg)
+func LogErrorF(format string, args ...interface{}) {
+	logMessageF(ErrorLevel, "", WarningStyle, format, args...)
 }
 
-func logF(w io.Writer, format string, args ...interface{}) {
-	var linesWithIndent []string
-	lines := strings.Split(fmt.Sprintf(format, args...), "\n")
-	for _, line := range lines {
-		if line == "" {
-			linesWithIndent = append(linesWithIndent, line)
-		} else {
-			linesWithIndent = append(linesWithIndent, fmt.Sprintf("%s%s", logIndent(), line))
-		}
-	}
-
-	logBase(w, strings.Join(linesWithIndent, "\n"))
+// LogDebug logs the message if debug level is enabled for the scope.
+func LogDebug(scope, msg string) {
+	LogDebugF(scope, "%s\n", msg)
 }
 
-func logBase(w io.Writer, msg string) {
-	fmt.Fprintf(w, msg)
+func LogDebugF(scope, format string, args ...interface{}) {
+	logMessageF(DebugLevel, scope, NoStyle, format, args...)
 }
 
-func logIndent() string {
-	return strings.Repeat("  ", indent)
+func logMessageF(level Level, scope string, style Style, format string, args ...interface{}) {
+	write(&Entry{Level: level, Scope: scope, Style: style, Msg: fmt.Sprintf(format, args...)})
 }
 
 func withLogIndent(f func() error) error {
@@ -143,147 +123,85 @@ func indentDown() {
 	indent -= 1
 }
 
-func logProcessInlineBase(processMsg string, processFunc func() error, colorizeProcessMsgFunc, colorizeSuccessFunc func(string) string) error {
-	processMsg = fmt.Sprintf(logProcessInlineProcessMsgFormat, processMsg)
-	colorizeLogBaseF(os.Stdout, colorizeProcessMsgFunc, "%s", processMsg)
+func logProcessInlineBase(msg string, processFunc func() error, processMsgStyle, successStyle Style) error {
+	processMsg := fmt.Sprintf(logProcessInlineProcessMsgFormat, msg)
+	write(&Entry{Level: InfoLevel, Style: processMsgStyle, Msg: processMsg, kind: inlineStartEntry})
 
 	resultStatus := logProcessSuccessStatus
-	resultColorize := colorizeSuccessFunc
+	resultStyle := successStyle
 	start := time.Now()
 
 	err := withLogIndent(processFunc)
 	if err != nil {
 		resultStatus = logProcessFailedStatus
-		resultColorize = colorizeFail
+		resultStyle = FailStyle
 	}
 
-	elapsedSeconds := fmt.Sprintf(logProcessTimeFormat, time.Since(start).Seconds())
-
-	rightPart := prepareLogStateRightPart(processMsg, resultStatus, elapsedSeconds, resultColorize)
-	logBase(os.Stdout, fmt.Sprintf("%s\n", rightPart))
+	write(&Entry{
+		Level:      InfoLevel,
+		Msg:        processMsg,
+		State:      resultStatus,
+		StateStyle: resultStyle,
+		Elapsed:    time.Since(start),
+		kind:       inlineEndEntry,
+	})
 
 	return err
 }
 
-func logProcessBase(msg, processMsg string, processFunc func() error, colorizeMsgFunc, colorizeSuccessFunc func(string) string) error {
+func logProcessBase(msg, processMsg string, processFunc func() error, msgStyle, successStyle Style) error {
 	if processMsg == "" {
 		processMsg = logProcessDefaultProcessMsg
 	}
 
-	logStateBase(msg, processMsg, "", colorizeMsgFunc, colorizeSuccessFunc)
+	logStateBase(msg, processMsg, 0, msgStyle, successStyle)
 
 	start := time.Now()
-	resultStatus := logProcessSuccessStatus
 
 	err := withLogIndent(processFunc)
 
-	elapsedSeconds := fmt.Sprintf(logProcessTimeFormat, time.Since(start).Seconds())
+	elapsed := time.Since(start)
 
 	if err != nil {
-		resultStatus = logProcessFailedStatus
-		logStateBase(msg, resultStatus, elapsedSeconds, colorizeFail, colorizeFail)
+		logStateBase(msg, logProcessFailedStatus, elapsed, FailStyle, FailStyle)
 
 		return err
 	}
 
-	logStateBase(msg, resultStatus, elapsedSeconds, colorizeMsgFunc, colorizeSuccessFunc)
+	logStateBase(msg, logProcessSuccessStatus, elapsed, msgStyle, successStyle)
 
 	return nil
 }
 
-func logStateBase(msg string, state, time string, colorizeLeftPartFunc, colorizeRightPartFunc func(string) string) {
-	leftPart := prepareLogStateLeftPart(msg, state, time, colorizeLeftPartFunc)
-	rightPart := prepareLogStateRightPart(msg, state, time, colorizeRightPartFunc)
-	log(os.Stdout, fmt.Sprintf("%s%s", leftPart, rightPart))
-}
-
-func prepareLogStateLeftPart(msg, state, time string, colorizeFunc func(string) string) string {
-	var result string
-
-	spaceLength := availableTerminalLineSpace(state, timeOrStub(time))
-	if spaceLength > 0 {
-		if spaceLength > len(msg) {
-			result = msg
-		} else {
-			result = msg[0:spaceLength]
-		}
-	} else {
-		return ""
-	}
-
-	return colorizeFunc(result)
-}
-
-func prepareLogStateRightPart(msg, state, time string, colorizeFunc func(string) string) string {
-	var result string
-	spaceLength := availableTerminalLineSpace(msg)
-
-	rightPartLength := len(state + timeOrStub(time) + logStateRightPartsSeparator)
-	if spaceLength-rightPartLength > 0 {
-		result += strings.Repeat(" ", spaceLength-rightPartLength)
-	}
-
-	var rightPart []string
-	rightPart = append(rightPart, colorizeFunc(state))
-	rightPart = append(rightPart, colorizeFunc(time))
-
-	result += strings.Join(rightPart, logStateRightPartsSeparator)
-
-	return result
+func logStateBase(msg, state string, elapsed time.Duration, style, stateStyle Style) {
+	write(&Entry{
+		Level:      InfoLevel,
+		Style:      style,
+		Msg:        msg,
+		State:      state,
+		StateStyle: stateStyle,
+		Elapsed:    elapsed,
+		kind:       stateEntry,
+	})
 }
 
-func timeOrStub(time string) string {
-	if time == "" {
-		return fmt.Sprintf(logProcessTimeFormat, 0.0)
+func write(entry *Entry) {
+	if !isEnabled(entry.Scope, entry.Level) {
+		return
 	}
 
-	return time
-}
-
-func availableTerminalLineSpace(parts ...string) int {
-	logIndentLength := len(logIndent())
-	msgsLength := len(strings.Join(parts, " "))
+	mux.Lock()
+	defer mux.Unlock()
 
-	return terminalWidth() - logIndentLength - msgsLength
-}
+	entry.Time = time.Now()
+	entry.Indent = indent
 
-func terminalWidth() int {
-	if terminal.IsTerminal(int(os.Stdout.Fd())) {
-		w, _, err := terminal.GetSize(int(os.Stdout.Fd()))
-		if err != nil {
-			panic(err)
+	for _, s := range sinks {
+		if entry.Level < s.MinLevel {
+			continue
 		}
 
-		return w
+		// Logging errors are ignored, the output could be closed
+		_ = s.Sink.Write(entry)
 	}
-
-	return defaultTerminalWidth
-}
-
-func colorizeFail(msg string) string {
-	return colorizeWarning(msg)
-}
-
-func colorizeSuccess(msg string) string {
-	return colorize(msg, color.FgGreen, color.Bold)
-}
-
-func colorizeStep(msg string) string {
-	return colorize(msg, color.FgYellow, color.Bold)
-}
-
-func colorizeService(msg string) string {
-	return colorize(msg, color.FgWhite, color.Bold)
-}
-
-func colorizeInfo(msg string) string {
-	return colorize(msg, color.FgBlue)
-}
-
-func colorizeWarning(msg string) string {
-	return colorize(msg, color.FgRed, color.Bold)
-}
-
-func colorize(msg string, attributes ...color.Attribute) string {
-	return color.New(attributes...).Sprint(msg)
 }
@@ -0,0 +1,514 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const progressRenderPeriod = 100 * time.Millisecond
+
+var (
+	progress        *Progress
+	progressEnabled = true
+
+	progressSpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+	ansiEscapeRegexp = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+)
+
+// Progress is a live tree of tasks with states, spinners and elapsed time, rendered in place on the terminal.
+// Output of the running task is captured and shown only when the task fails,
+// other messages are printed above the tree.
+type Progress struct {
+	out   *os.File
+	inner Sink
+	tasks []*ProgressTask
+
+	capturing     *ProgressTask
+	renderedLines int
+	frame         int
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+type ProgressTask struct {
+	progress *Progress
+	parent   *ProgressTask
+	children []*ProgressTask
+
+	name  string
+	state string
+
+	running   bool
+	done      bool
+	failed    bool
+	startedAt time.Time
+	elapsed   time.Duration
+
+	output bytes.Buffer
+}
+
+// StartProgress starts live rendering of the tasks tree.
+// Nil is returned when the output is not an interactive terminal (CI, redirected output, non terminal log format or quiet mode),
+// callers should fall back to the line output then.
+func StartProgress() *Progress {
+	mux.Lock()
+	defer mux.Unlock()
+
+	if progress != nil || !isProgressAvailable() {
+		return nil
+	}
+
+	p := &Progress{
+		out:    os.Stdout,
+		inner:  sinks[0].Sink,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+
+	sinks[0].Sink = &progressSink{progress: p}
+	progress = p
+
+	go p.renderLoop()
+
+	return p
+}
+
+func isProgressAvailable() bool {
+	if !progressEnabled || len(sinks) == 0 || sinks[0].MinLevel > InfoLevel {
+		return false
+	}
+
+	if _, ok := sinks[0].Sink.(*terminalSink); !ok {
+		return false
+	}
+
+	if os.Getenv("CI") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return terminal.IsTerminal(int(os.Stdout.Fd()))
+}
+
+func isProgressActive() bool {
+	mux.Lock()
+	defer mux.Unlock()
+
+	return progress != nil
+}
+
+// Stop stops live rendering, renders the final tree and the output of the failed tasks.
+func (p *Progress) Stop() {
+	if p == nil {
+		return
+	}
+
+	close(p.stopCh)
+	<-p.doneCh
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	p.clear()
+	p.render(true)
+
+	for _, task := range p.failedTasks(p.tasks) {
+		if task.output.Len() == 0 {
+			continue
+		}
+
+		fmt.Fprintf(p.out, "\n%s\n", colorizeWarning(fmt.Sprintf("Output of %s:", task.path())))
+		p.out.Write(task.output.Bytes())
+		if !bytes.HasSuffix(task.output.Bytes(), []byte("\n")) {
+			fmt.Fprintln(p.out)
+		}
+	}
+
+	sinks[0].Sink = p.inner
+	progress = nil
+}
+
+func (p *Progress) AddTask(name string) *ProgressTask {
+	if p == nil {
+		return nil
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	task := &ProgressTask{progress: p, name: name}
+	p.tasks = append(p.tasks, task)
+
+	return task
+}
+
+func (t *ProgressTask) AddTask(name string) *ProgressTask {
+	if t == nil {
+		return nil
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	task := &ProgressTask{progress: t.progress, parent: t, name: name}
+	t.children = append(t.children, task)
+
+	return task
+}
+
+// SetState sets state of the task which is not started yet, e.g. pending.
+func (t *ProgressTask) SetState(state string) {
+	if t == nil {
+		return
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	t.state = state
+}
+
+// Finish marks the task done without running, e.g. cached.
+func (t *ProgressTask) Finish(state string) {
+	if t == nil {
+		return
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	t.state = state
+	t.done = true
+	t.failed = false
+}
+
+// Run runs the task function with the spinner and elapsed time, output of the function is captured.
+// The task is marked done with doneState or failed by the function error.
+func (t *ProgressTask) Run(state, doneState string, f func() error) error {
+	if t == nil {
+		return f()
+	}
+
+	mux.Lock()
+	t.state = state
+	t.running = true
+	t.done = false
+	t.failed = false
+	t.startedAt = time.Now()
+	t.output.Reset()
+	prevCapturing := t.progress.capturing
+	t.progress.capturing = t
+	mux.Unlock()
+
+	err := f()
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	t.progress.capturing = prevCapturing
+	t.running = false
+	t.elapsed = time.Since(t.startedAt)
+
+	if err != nil {
+		t.state = "failed"
+		t.failed = true
+	} else {
+		t.state = doneState
+		t.done = true
+	}
+
+	return err
+}
+
+func (t *ProgressTask) path() string {
+	if t.parent == nil {
+		return t.name
+	}
+	return fmt.Sprintf("%s %s", t.parent.path(), t.name)
+}
+
+func (p *Progress) renderLoop() {
+	ticker := time.NewTicker(progressRenderPeriod)
+	defer ticker.Stop()
+	defer close(p.doneCh)
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ticker.C:
+			mux.Lock()
+			p.frame++
+			p.clear()
+			p.render(false)
+			mux.Unlock()
+		}
+	}
+}
+
+func (p *Progress) clear() {
+	if p.renderedLines == 0 {
+		return
+	}
+
+	fmt.Fprintf(p.out, "\x1b[%dA\x1b[J", p.renderedLines)
+	p.renderedLines = 0
+}
+
+func (p *Progress) render(final bool) {
+	width, height := defaultTerminalWidth, 0
+	if w, h, err := terminal.GetSize(int(p.out.Fd())); err == nil && w > 0 {
+		width, height = w, h
+	}
+
+	var lines []string
+	for _, task := range p.tasks {
+		lines = append(lines, p.taskLines(task, 0, width, final, false)...)
+	}
+
+	if !final && height > 0 && len(lines) >= height {
+		// Finished tasks are collapsed to fit the tree into the terminal, the tail is shown if it is not enough
+		lines = nil
+		for _, task := range p.tasks {
+			lines = append(lines, p.taskLines(task, 0, width, final, true)...)
+		}
+
+		if len(lines) >= height {
+			lines = lines[len(lines)-height+1:]
+		}
+	}
+
+	if len(lines) == 0 {
+		return
+	}
+
+	fmt.Fprintf(p.out, "%s\n", strings.Join(lines, "\n"))
+	p.renderedLines = len(lines)
+}
+
+func (p *Progress) taskLines(task *ProgressTask, level, width int, final, collapse bool) []string {
+	symbol, symbolStyle := p.taskSymbol(task, final)
+
+	state := task.state
+	if len(task.children) > 0 {
+		state = task.childrenState()
+	}
+
+	var elapsed time.Duration
+	if task.running {
+		elapsed = time.Since(task.startedAt)
+	} else {
+		elapsed = task.elapsed
+	}
+
+	lines := []string{formatProgressLine(level, width, symbol, task.name, state, elapsed, symbolStyle)}
+
+	if collapse && task.isDone() {
+		return lines
+	}
+
+	for _, child := range task.children {
+		lines = append(lines, p.taskLines(child, level+1, width, final, collapse)...)
+	}
+
+	if task.running && !final {
+		if line := lastOutputLine(task.output.Bytes()); line != "" {
+			lines = append(lines, formatProgressOutputLine(level+1, width, line))
+		}
+	}
+
+	return lines
+}
+
+func (p *Progress) taskSymbol(task *ProgressTask, final bool) (string, Style) {
+	switch {
+	case task.isFailed():
+		return "✗", FailStyle
+	case task.isRunning():
+		if final {
+			return "-", StepStyle
+		}
+		return progressSpinnerFrames[p.frame%len(progressSpinnerFrames)], StepStyle
+	case task.isDone():
+		return "✓", SuccessStyle
+	default:
+		return "·", NoStyle
+	}
+}
+
+func (p *Progress) failedTasks(tasks []*ProgressTask) []*ProgressTask {
+	var res []*ProgressTask
+	for _, task := range tasks {
+		if task.failed {
+			res = append(res, task)
+		}
+		res = append(res, p.failedTasks(task.children)...)
+	}
+
+	return res
+}
+
+func (t *ProgressTask) isRunning() bool {
+	if t.running {
+		return true
+	}
+
+	for _, child := range t.children {
+		if child.isRunning() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *ProgressTask) isFailed() bool {
+	if t.failed {
+		return true
+	}
+
+	for _, child := range t.children {
+		if child.isFailed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *ProgressTask) isDone() bool {
+	if len(t.children) == 0 {
+		return t.done
+	}
+
+	for _, child := range t.children {
+		if !child.isDone() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t *ProgressTask) childrenState() string {
+	var done int
+	for _, child := range t.children {
+		if child.isDone() {
+			done++
+		}
+	}
+
+	return fmt.Sprintf("%d/%d", done, len(t.children))
+}
+
+func formatProgressLine(level, width int, symbol, name, state string, elapsed time.Duration, symbolStyle Style) string {
+	right := state
+	if elapsed != 0 {
+		right = fmt.Sprintf("%s %s", right, formatProgressElapsed(elapsed))
+	}
+
+	left := fmt.Sprintf("%s%s ", logIndent(level), symbol)
+
+	nameSpace := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right) - 2
+	if nameSpace < 0 {
+		nameSpace = 0
+	}
+	name = truncateString(name, nameSpace)
+
+	padding := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(name) - utf8.RuneCountInString(right) - 1
+	if padding < 1 {
+		padding = 1
+	}
+
+	return fmt.Sprintf("%s%s %s%s%s", logIndent(level), colorizeFunc(symbolStyle)(symbol), name, strings.Repeat(" ", padding), right)
+}
+
+func formatProgressOutputLine(level, width int, line string) string {
+	prefix := logIndent(level) + "  "
+	line = truncateString(line, width-len(prefix)-1)
+
+	return prefix + color.New(color.Faint).Sprint(line)
+}
+
+func formatProgressElapsed(elapsed time.Duration) string {
+	if elapsed < time.Minute {
+		return fmt.Sprintf("%.1fs", elapsed.Seconds())
+	}
+	return elapsed.Round(time.Second).String()
+}
+
+func truncateString(s string, max int) string {
+	if max <= 0 {
+		return ""
+	}
+
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	runes := []rune(s)
+	if max == 1 {
+		return string(runes[:1])
+	}
+
+	return string(runes[:max-1]) + "…"
+}
+
+func lastOutputLine(output []byte) string {
+	lines := strings.FieldsFunc(string(output), func(r rune) bool { return r == '\n' || r == '\r' })
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(ansiEscapeRegexp.ReplaceAllString(lines[i], ""))
+		if line != "" {
+			return line
+		}
+	}
+
+	return ""
+}
+
+// progressSink captures entries into the output of the running task or prints them above the tree.
+type progressSink struct {
+	progress *Progress
+}
+
+func (sink *progressSink) Write(entry *Entry) error {
+	p := sink.progress
+
+	if p.capturing != nil && entry.Level < WarnLevel {
+		return NewTerminalSink(&p.capturing.output, &p.capturing.output).Write(entry)
+	}
+
+	p.clear()
+	err := p.inner.Write(entry)
+	p.render(false)
+
+	return err
+}
+
+// terminalStream writes the output of subprocesses to the terminal as is,
+// the output is written as raw entries while progress is active to be captured.
+type terminalStream struct {
+	file   *os.File
+	stream *streamWriter
+}
+
+func (w *terminalStream) Write(p []byte) (int, error) {
+	if isProgressActive() {
+		return w.stream.Write(p)
+	}
+	return w.file.Write(p)
+}
+
+// Fd allows docker cli to detect terminal.
+func (w *terminalStream) Fd() uintptr {
+	return w.file.Fd()
+}
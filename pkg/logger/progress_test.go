@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestLastOutputLine(t *testing.T) {
+	expectations := map[string]string{
+		"":                                 "",
+		"first\nsecond\n":                  "second",
+		"first\nsecond\n\n  \n":            "second",
+		"Downloading 10%\rDownloading 50%": "Downloading 50%",
+		"\x1b[1mbold\x1b[0m\n":             "bold",
+	}
+
+	for output, expected := range expectations {
+		if got := lastOutputLine([]byte(output)); got != expected {
+			t.Errorf("\n[OUTPUT]: %q\n[EXPECTED]: %q\n[GOT]: %q", output, expected, got)
+		}
+	}
+}
+
+func TestFormatProgressLine(t *testing.T) {
+	line := formatProgressLine(1, 40, "·", "a very long stage name which does not fit", "pending", 1500*time.Millisecond, NoStyle)
+
+	if length := utf8.RuneCountInString(line); length >= 40 {
+		t.Errorf("\n[EXPECTED]: line shorter than terminal width 40\n[GOT]: %q (%d)", line, length)
+	}
+
+	expected := "  · a very long stage nam… pending 1.5s"
+	if line != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, line)
+	}
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type Style int
+
+const (
+	NoStyle Style = iota
+	StepStyle
+	ServiceStyle
+	InfoStyle
+	WarningStyle
+	SuccessStyle
+	FailStyle
+)
+
+type entryKind int
+
+const (
+	messageEntry entryKind = iota
+	// Message with the state aligned to the right, e.g. process start or finish
+	stateEntry
+	// Inline process message, the state is printed on the same line by the inline end entry
+	inlineStartEntry
+	inlineEndEntry
+	// Output of subprocesses as is
+	rawEntry
+)
+
+type Entry struct {
+	Time   time.Time
+	Level  Level
+	Scope  string
+	Style  Style
+	Msg    string
+	Indent int
+
+	State      string
+	StateStyle Style
+	Elapsed    time.Duration
+	Stderr     bool
+
+	kind entryKind
+}
+
+// Sink writes log entries in its format.
+type Sink interface {
+	Write(entry *Entry) error
+}
+
+// NewTextSink creates sink which writes entries as plain text lines with time, level and scope.
+func NewTextSink(w io.Writer) Sink {
+	return &textSink{w: w}
+}
+
+type textSink struct {
+	w io.Writer
+}
+
+func (sink *textSink) Write(entry *Entry) error {
+	msg := strings.TrimSuffix(entry.Msg, "\n")
+
+	if entry.kind == stateEntry || entry.kind == inlineEndEntry {
+		msg = fmt.Sprintf("%s %s", msg, entry.State)
+		if entry.Elapsed != 0 {
+			msg = fmt.Sprintf("%s %.2f sec", msg, entry.Elapsed.Seconds())
+		}
+	}
+
+	prefix := fmt.Sprintf("%s %-5s ", entry.Time.UTC().Format(time.RFC3339), entry.Level)
+	if entry.Scope != "" {
+		prefix += fmt.Sprintf("[%s] ", entry.Scope)
+	}
+	prefix += strings.Repeat("  ", entry.Indent)
+
+	var lines []string
+	for _, line := range strings.Split(msg, "\n") {
+		lines = append(lines, prefix+line)
+	}
+
+	_, err := fmt.Fprintln(sink.w, strings.Join(lines, "\n"))
+	return err
+}
+
+// NewJSONSink creates sink which writes an entry per line as json object.
+func NewJSONSink(w io.Writer) Sink {
+	return &jsonSink{w: w}
+}
+
+type jsonSink struct {
+	w io.Writer
+}
+
+type jsonEntry struct {
+	Time    string  `json:"time"`
+	Level   string  `json:"level"`
+	Scope   string  `json:"scope,omitempty"`
+	Msg     string  `json:"msg"`
+	Indent  int     `json:"indent,omitempty"`
+	State   string  `json:"state,omitempty"`
+	Elapsed float64 `json:"elapsed,omitempty"`
+	Stream  string  `json:"stream,omitempty"`
+}
+
+func (sink *jsonSink) Write(entry *Entry) error {
+	e := &jsonEntry{
+		Time:    entry.Time.UTC().Format(time.RFC3339Nano),
+		Level:   entry.Level.String(),
+		Scope:   entry.Scope,
+		Msg:     strings.TrimSuffix(entry.Msg, "\n"),
+		Indent:  entry.Indent,
+		State:   entry.State,
+		Elapsed: entry.Elapsed.Seconds(),
+	}
+
+	if entry.kind == rawEntry {
+		e.Stream = "stdout"
+		if entry.Stderr {
+			e.Stream = "stderr"
+		}
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(sink.w, "%s\n", data)
+	return err
+}
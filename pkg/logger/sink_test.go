@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testEntryTime = time.Date(2018, 10, 15, 12, 30, 0, 500000000, time.FixedZone("MSK", 3*60*60))
+
+func TestTextSink(t *testing.T) {
+	for _, e := range []struct {
+		entry    *Entry
+		expected string
+	}{
+		{
+			&Entry{Time: testEntryTime, Level: InfoLevel, Msg: "Building stage\n"},
+			"2018-10-15T09:30:00Z info  Building stage\n",
+		},
+		{
+			&Entry{Time: testEntryTime, Level: DebugLevel, Scope: "deploy", Indent: 1, Msg: "first\nsecond\n"},
+			"2018-10-15T09:30:00Z debug [deploy]   first\n2018-10-15T09:30:00Z debug [deploy]   second\n",
+		},
+		{
+			&Entry{Time: testEntryTime, Level: InfoLevel, Msg: "Building stage", State: "[OK]", Elapsed: 1500 * time.Millisecond, kind: stateEntry},
+			"2018-10-15T09:30:00Z info  Building stage [OK] 1.50 sec\n",
+		},
+		{
+			&Entry{Time: testEntryTime, Level: WarnLevel, Msg: "WARNING: message", State: "ignored", kind: messageEntry},
+			"2018-10-15T09:30:00Z warn  WARNING: message\n",
+		},
+	} {
+		buf := &bytes.Buffer{}
+		if err := NewTextSink(buf).Write(e.entry); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := buf.String(); got != e.expected {
+			t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", e.expected, got)
+		}
+	}
+}
+
+func TestJSONSink(t *testing.T) {
+	for _, e := range []struct {
+		entry    *Entry
+		expected map[string]interface{}
+	}{
+		{
+			&Entry{Time: testEntryTime, Level: InfoLevel, Msg: "Building stage\n"},
+			map[string]interface{}{"time": "2018-10-15T09:30:00.5Z", "level": "info", "msg": "Building stage"},
+		},
+		{
+			&Entry{Time: testEntryTime, Level: ErrorLevel, Scope: "deploy", Indent: 2, Msg: "Deploy", State: "[FAILED]", Elapsed: 2 * time.Second, kind: stateEntry},
+			map[string]interface{}{"time": "2018-10-15T09:30:00.5Z", "level": "error", "scope": "deploy", "msg": "Deploy", "indent": float64(2), "state": "[FAILED]", "elapsed": float64(2)},
+		},
+		{
+			&Entry{Time: testEntryTime, Level: InfoLevel, Msg: "output line\n", Stderr: true, kind: rawEntry},
+			map[string]interface{}{"time": "2018-10-15T09:30:00.5Z", "level": "info", "msg": "output line", "stream": "stderr"},
+		},
+		{
+			&Entry{Time: testEntryTime, Level: InfoLevel, Msg: "output line\n", kind: rawEntry},
+			map[string]interface{}{"time": "2018-10-15T09:30:00.5Z", "level": "info", "msg": "output line", "stream": "stdout"},
+		},
+	} {
+		buf := &bytes.Buffer{}
+		if err := NewJSONSink(buf).Write(e.entry); err != nil {
+			t.Fatal(err)
+		}
+
+		data := buf.Bytes()
+		if len(data) == 0 || data[len(data)-1] != '\n' || bytes.Count(data, []byte("\n")) != 1 {
+			t.Errorf("\n[EXPECTED]: single json line\n[GOT]: %q", data)
+		}
+
+		var got map[string]interface{}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("\n[DATA]: %s\n[ERROR]: %s", data, err)
+		}
+
+		if !reflect.DeepEqual(got, e.expected) {
+			t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v", e.expected, got)
+		}
+	}
+}
+
+func TestNewFormatSink(t *testing.T) {
+	for format, expected := range map[string]interface{}{
+		"":             &terminalSink{},
+		TerminalFormat: &terminalSink{},
+		TextFormat:     &textSink{},
+		JSONFormat:     &jsonSink{},
+	} {
+		sink, err := newFormatSink(format, &bytes.Buffer{}, &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("\n[FORMAT]: %q\n[ERROR]: %s", format, err)
+		}
+
+		if reflect.TypeOf(sink) != reflect.TypeOf(expected) {
+			t.Errorf("\n[FORMAT]: %q\n[EXPECTED]: %T\n[GOT]: %T", format, expected, sink)
+		}
+	}
+
+	if _, err := newFormatSink("yaml", &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("\n[FORMAT]: yaml\n[EXPECTED]: error\n[GOT]: no error")
+	}
+}
@@ -0,0 +1,196 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	defaultTerminalWidth = 120
+
+	logProcessTimeFormat = "%5.2f sec "
+
+	logStateRightPartsSeparator = " "
+)
+
+// NewTerminalSink creates sink which writes colorized entries with indentation and aligned states,
+// warnings and errors are written to err.
+func NewTerminalSink(out, err io.Writer) Sink {
+	return &terminalSink{out: out, err: err}
+}
+
+type terminalSink struct {
+	out io.Writer
+	err io.Writer
+}
+
+func (sink *terminalSink) Write(entry *Entry) error {
+	w := sink.out
+	if entry.Level >= WarnLevel || entry.Stderr {
+		w = sink.err
+	}
+
+	var msg string
+	switch entry.kind {
+	case messageEntry, inlineStartEntry:
+		msg = indentLines(entry.Indent, colorizeLines(entry.Msg, colorizeFunc(entry.Style)))
+	case stateEntry:
+		elapsed := elapsedOrEmpty(entry)
+		leftPart := prepareLogStateLeftPart(entry.Indent, entry.Msg, entry.State, elapsed, colorizeFunc(entry.Style))
+		rightPart := prepareLogStateRightPart(entry.Indent, entry.Msg, entry.State, elapsed, colorizeFunc(entry.StateStyle))
+		msg = indentLines(entry.Indent, fmt.Sprintf("%s%s\n", leftPart, rightPart))
+	case inlineEndEntry:
+		msg = fmt.Sprintf("%s\n", prepareLogStateRightPart(entry.Indent, entry.Msg, entry.State, elapsedOrEmpty(entry), colorizeFunc(entry.StateStyle)))
+	case rawEntry:
+		msg = entry.Msg
+	}
+
+	_, err := io.WriteString(w, msg)
+	return err
+}
+
+func elapsedOrEmpty(entry *Entry) string {
+	if entry.Elapsed == 0 {
+		return ""
+	}
+	return fmt.Sprintf(logProcessTimeFormat, entry.Elapsed.Seconds())
+}
+
+func colorizeLines(msg string, colorizeFunc func(string) string) string {
+	var colorizeLines []string
+	for _, line := range strings.Split(msg, "\n") {
+		if line == "" {
+			colorizeLines = append(colorizeLines, line)
+		} else {
+			colorizeLines = append(colorizeLines, colorizeFunc(line))
+		}
+	}
+
+	return strings.Join(colorizeLines, "\n")
+}
+
+func indentLines(indent int, msg string) string {
+	var linesWithIndent []string
+	for _, line := range strings.Split(msg, "\n") {
+		if line == "" {
+			linesWithIndent = append(linesWithIndent, line)
+		} else {
+			linesWithIndent = append(linesWithIndent, fmt.Sprintf("%s%s", logIndent(indent), line))
+		}
+	}
+
+	return strings.Join(linesWithIndent, "\n")
+}
+
+func logIndent(indent int) string {
+	return strings.Repeat("  ", indent)
+}
+
+func prepareLogStateLeftPart(indent int, msg, state, time string, colorizeFunc func(string) string) string {
+	var result string
+
+	spaceLength := availableTerminalLineSpace(indent, state, timeOrStub(time))
+	if spaceLength > 0 {
+		if spaceLength > len(msg) {
+			result = msg
+		} else {
+			result = msg[0:spaceLength]
+		}
+	} else {
+		return ""
+	}
+
+	return colorizeFunc(result)
+}
+
+func prepareLogStateRightPart(indent int, msg, state, time string, colorizeFunc func(string) string) string {
+	var result string
+	spaceLength := availableTerminalLineSpace(indent, msg)
+
+	rightPartLength := len(state + timeOrStub(time) + logStateRightPartsSeparator)
+	if spaceLength-rightPartLength > 0 {
+		result += strings.Repeat(" ", spaceLength-rightPartLength)
+	}
+
+	var rightPart []string
+	rightPart = append(rightPart, colorizeFunc(state))
+	rightPart = append(rightPart, colorizeFunc(time))
+
+	result += strings.Join(rightPart, logStateRightPartsSeparator)
+
+	return result
+}
+
+func timeOrStub(time string) string {
+	if time == "" {
+		return fmt.Sprintf(logProcessTimeFormat, 0.0)
+	}
+
+	return time
+}
+
+func availableTerminalLineSpace(indent int, parts ...string) int {
+	logIndentLength := len(logIndent(indent))
+	msgsLength := len(strings.Join(parts, " "))
+
+	return terminalWidth() - logIndentLength - msgsLength
+}
+
+func terminalWidth() int {
+	if terminal.IsTerminal(int(os.Stdout.Fd())) {
+		w, _, err := terminal.GetSize(int(os.Stdout.Fd()))
+		if err != nil {
+			panic(err)
+		}
+
+		return w
+	}
+
+	return defaultTerminalWidth
+}
+
+func colorizeFunc(style Style) func(string) string {
+	switch style {
+	case StepStyle:
+		return colorizeStep
+	case ServiceStyle:
+		return colorizeService
+	case InfoStyle:
+		return colorizeInfo
+	case WarningStyle, FailStyle:
+		return colorizeWarning
+	case SuccessStyle:
+		return colorizeSuccess
+	default:
+		return func(msg string) string { return msg }
+	}
+}
+
+func colorizeSuccess(msg string) string {
+	return colorize(msg, color.FgGreen, color.Bold)
+}
+
+func colorizeStep(msg string) string {
+	return colorize(msg, color.FgYellow, color.Bold)
+}
+
+func colorizeService(msg string) string {
+	return colorize(msg, color.FgWhite, color.Bold)
+}
+
+func colorizeInfo(msg string) string {
+	return colorize(msg, color.FgBlue)
+}
+
+func colorizeWarning(msg string) string {
+	return colorize(msg, color.FgRed, color.Bold)
+}
+
+func colorize(msg string, attributes ...color.Attribute) string {
+	return color.New(attributes...).Sprint(msg)
+}
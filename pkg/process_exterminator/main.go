@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/flant/dapp/pkg/dapp"
+	"github.com/flant/dapp/pkg/logger"
 )
 
 func Init() error {
@@ -39,7 +40,7 @@ func run(parentsPids []int) {
 
 			err := writePidToFile(os.Getpid(), filepath.Join(dapp.GetHomeDir(), ".killed_pids"))
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Process exterminator error: %s\n", err)
+				logger.LogErrorF("Process exterminator error: %s\n", err)
 			}
 
 			syscall.Kill(ownPid, syscall.SIGINT)
@@ -0,0 +1,138 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// VersionedDataPrefix starts data in the versioned envelope format: dapp:v2:KEY_ID:BASE64(NONCE+CIPHERTEXT)
+	VersionedDataPrefix = "dapp:v2:"
+
+	aesGcmKeyInfo = "dapp secret aes-256-gcm"
+	keyIdInfo     = "dapp secret key id"
+	keyIdSize     = 4
+)
+
+// AesGcmSecret encrypts data with AES-256-GCM using key derived from the project secret key.
+// Key id and format prefix are authenticated with the data.
+type AesGcmSecret struct {
+	AEAD  cipher.AEAD
+	KeyId string
+}
+
+func NewAesGcmSecret(key []byte) (*AesGcmSecret, error) {
+	binaryKey, err := hexToBinary(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcmKey := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, binaryKey, nil, []byte(aesGcmKeyInfo)), gcmKey); err != nil {
+		return nil, err
+	}
+
+	c, err := aes.NewCipher(gcmKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(c)
+	if err != nil {
+		return nil, err
+	}
+
+	keyId, err := GetKeyId(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AesGcmSecret{AEAD: aead, KeyId: keyId}, nil
+}
+
+// GetKeyId returns short public identifier of the secret key.
+func GetKeyId(key []byte) (string, error) {
+	binaryKey, err := hexToBinary(key)
+	if err != nil {
+		return "", err
+	}
+
+	keyId := make([]byte, keyIdSize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, binaryKey, nil, []byte(keyIdInfo)), keyId); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(keyId), nil
+}
+
+func (s *AesGcmSecret) header() []byte {
+	return []byte(VersionedDataPrefix + s.KeyId + ":")
+}
+
+func (s *AesGcmSecret) Generate(data []byte) ([]byte, error) {
+	nonce := make([]byte, s.AEAD.NonceSize(), s.AEAD.NonceSize()+len(data)+s.AEAD.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	header := s.header()
+	sealed := s.AEAD.Seal(nonce, nonce, data, header)
+
+	return append(header, []byte(base64.RawURLEncoding.EncodeToString(sealed))...), nil
+}
+
+func (s *AesGcmSecret) Extract(data []byte) ([]byte, error) {
+	keyId, payload, err := ParseVersionedData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyId != s.KeyId {
+		return nil, fmt.Errorf("data is encrypted with key '%s', not with key '%s'", keyId, s.KeyId)
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("bad secret data format: %s", err)
+	}
+
+	if len(sealed) < s.AEAD.NonceSize()+s.AEAD.Overhead() {
+		return nil, fmt.Errorf("bad secret data format: data is too short")
+	}
+
+	nonce, cipherText := sealed[:s.AEAD.NonceSize()], sealed[s.AEAD.NonceSize():]
+	result, err := s.AEAD.Open(nil, nonce, cipherText, s.header())
+	if err != nil {
+		return nil, fmt.Errorf("data authentication failed: data is corrupted or encrypted with another key")
+	}
+
+	return result, nil
+}
+
+// IsVersionedData checks whether data is in the versioned envelope format.
+func IsVersionedData(data []byte) bool {
+	return strings.HasPrefix(string(data), VersionedDataPrefix)
+}
+
+// ParseVersionedData returns key id and encoded payload of the versioned envelope.
+func ParseVersionedData(data []byte) (string, string, error) {
+	if !IsVersionedData(data) {
+		return "", "", fmt.Errorf("bad secret data format: expected '%s' prefix", VersionedDataPrefix)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(string(data), VersionedDataPrefix), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("bad secret data format: expected '%sKEY_ID:DATA'", VersionedDataPrefix)
+	}
+
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,128 @@
+package secret
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAesGcmSecret(t *testing.T) {
+	s, err := NewAesGcmSecret(AesSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []string{"", "value"} {
+		t.Run(test, func(t *testing.T) {
+			encodedData, err := s.Generate([]byte(test))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.HasPrefix(string(encodedData), VersionedDataPrefix+s.KeyId+":") {
+				t.Errorf("unexpected encoded data '%s'", encodedData)
+			}
+
+			result, err := s.Extract(encodedData)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test != string(result) {
+				t.Errorf("\n[EXPECTED]: %s\n[GOT]: %s", test, result)
+			}
+		})
+	}
+}
+
+func TestAesGcmSecret_Extract_negative(t *testing.T) {
+	s, err := NewAesGcmSecret(AesSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKeySecret, err := NewAesGcmSecret([]byte("22ac8312520b5ff037bae386ea2e8a07"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encodedData, err := s.Generate([]byte("flant"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := []byte(string(encodedData))
+	if tampered[len(tampered)-10] == 'A' {
+		tampered[len(tampered)-10] = 'B'
+	} else {
+		tampered[len(tampered)-10] = 'A'
+	}
+
+	tests := []struct {
+		name         string
+		secret       *AesGcmSecret
+		encodedData  []byte
+		errorMessage string
+	}{
+		{
+			name:         "tampered data",
+			secret:       s,
+			encodedData:  tampered,
+			errorMessage: "data authentication failed",
+		},
+		{
+			name:         "another key",
+			secret:       otherKeySecret,
+			encodedData:  encodedData,
+			errorMessage: "data is encrypted with key '" + s.KeyId + "'",
+		},
+		{
+			name:         "no key id",
+			secret:       s,
+			encodedData:  []byte(VersionedDataPrefix + "data"),
+			errorMessage: "bad secret data format",
+		},
+		{
+			name:         "short data",
+			secret:       s,
+			encodedData:  []byte(VersionedDataPrefix + s.KeyId + ":AAAA"),
+			errorMessage: "bad secret data format",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.secret.Extract(test.encodedData)
+			if err == nil {
+				t.Errorf("Expected error: %s", test.errorMessage)
+			} else if !strings.HasPrefix(err.Error(), test.errorMessage) {
+				t.Errorf("\n[EXPECTED]: %s\n[GOT]: %s", test.errorMessage, err.Error())
+			}
+		})
+	}
+}
+
+func TestVersionedSecret_Extract_legacy(t *testing.T) {
+	s, err := NewSecret(AesSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacy, err := NewAesSecret(AesSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacyData, err := legacy.Generate([]byte("flant"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.Extract(legacyData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(result) != "flant" {
+		t.Errorf("\n[EXPECTED]: flant\n[GOT]: %s", result)
+	}
+}
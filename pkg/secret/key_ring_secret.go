@@ -0,0 +1,104 @@
+package secret
+
+import (
+	"fmt"
+)
+
+// KeyRingSecret encrypts data with the primary key and decrypts versioned data with the key of data key id.
+// Legacy data without key id is not authenticated and cannot be matched to the key,
+// so it is decrypted only with the legacy key if it is set or with the primary key of the single key ring:
+// legacy data should be migrated before adding a new primary key to the key ring.
+type KeyRingSecret struct {
+	Primary   *VersionedSecret
+	Keys      []*VersionedSecret
+	LegacyKey *VersionedSecret
+}
+
+func NewKeyRingSecret(keys [][]byte) (*KeyRingSecret, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("empty key ring")
+	}
+
+	s := &KeyRingSecret{}
+	for _, key := range keys {
+		ss, err := NewSecret(key)
+		if err != nil {
+			return nil, err
+		}
+
+		s.Keys = append(s.Keys, ss.(*VersionedSecret))
+	}
+	s.Primary = s.Keys[0]
+
+	return s, nil
+}
+
+// SetLegacyKey sets the key to decrypt legacy data without key id.
+func (s *KeyRingSecret) SetLegacyKey(key []byte) error {
+	ss, err := NewSecret(key)
+	if err != nil {
+		return err
+	}
+
+	s.LegacyKey = ss.(*VersionedSecret)
+
+	return nil
+}
+
+func (s *KeyRingSecret) PrimaryKeyId() string {
+	return s.Primary.Current.KeyId
+}
+
+func (s *KeyRingSecret) Generate(data []byte) ([]byte, error) {
+	return s.Primary.Generate(data)
+}
+
+func (s *KeyRingSecret) Extract(data []byte) ([]byte, error) {
+	if IsVersionedData(data) {
+		keyId, _, err := ParseVersionedData(data)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range s.Keys {
+			if key.Current.KeyId == keyId {
+				return key.Extract(data)
+			}
+		}
+
+		return nil, fmt.Errorf("data is encrypted with key '%s' which is not in the key ring", keyId)
+	}
+
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	if s.LegacyKey != nil {
+		return s.LegacyKey.Legacy.Extract(data)
+	}
+
+	if len(s.Keys) > 1 {
+		return nil, fmt.Errorf("legacy data without key id cannot be decrypted with the key ring of several keys: run `dapp secret migrate` with the previous key before the key rotation or pass the key of legacy data with --old-key")
+	}
+
+	return s.Primary.Legacy.Extract(data)
+}
+
+// IsCurrentData checks whether data is encrypted with the primary key.
+func (s *KeyRingSecret) IsCurrentData(data []byte) bool {
+	return DataKeyId(data) == s.PrimaryKeyId()
+}
+
+// DataKeyId returns key id of versioned data, legacy data and data encrypted to recipients have no key id.
+func DataKeyId(data []byte) string {
+	if !IsVersionedData(data) {
+		return ""
+	}
+
+	keyId, _, err := ParseVersionedData(data)
+	if err != nil {
+		return ""
+	}
+
+	return keyId
+}
@@ -0,0 +1,81 @@
+package secret
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyRingSecret(t *testing.T) {
+	newKey := []byte("22ac8312520b5ff037bae386ea2e8a07")
+
+	oldRing, err := NewKeyRingSecret([][]byte{AesSecretKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotationRing, err := NewKeyRingSecret([][]byte{newKey, AesSecretKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRing, err := NewKeyRingSecret([][]byte{newKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldData, err := oldRing.Generate([]byte("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacyData := []byte("10000f13a718d019612ab8ad30d9bec8e2c09df0f2d168c179bef954e78371bf6a5a")
+
+	newData, err := rotationRing.Generate([]byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if DataKeyId(newData) != newRing.PrimaryKeyId() {
+		t.Errorf("data is not encrypted with primary key: %s", newData)
+	}
+
+	for data, expected := range map[string]string{string(oldData): "old", string(newData): "new"} {
+		result, err := rotationRing.Extract([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(result) != expected {
+			t.Errorf("\n[EXPECTED]: %s\n[GOT]: %s", expected, result)
+		}
+	}
+
+	// Legacy data is not authenticated: it is not decrypted with any key of the key ring but the primary one
+	if _, err := rotationRing.Extract(legacyData); err == nil {
+		t.Errorf("\n[EXPECTED]: legacy data decryption error\n[GOT]: no error")
+	}
+
+	if result, err := oldRing.Extract(legacyData); err != nil {
+		t.Fatal(err)
+	} else if string(result) != "flant" {
+		t.Errorf("\n[EXPECTED]: flant\n[GOT]: %s", result)
+	}
+
+	if err := rotationRing.SetLegacyKey(AesSecretKey); err != nil {
+		t.Fatal(err)
+	}
+
+	if result, err := rotationRing.Extract(legacyData); err != nil {
+		t.Fatal(err)
+	} else if string(result) != "flant" {
+		t.Errorf("\n[EXPECTED]: flant\n[GOT]: %s", result)
+	}
+
+	if !rotationRing.IsCurrentData(newData) || rotationRing.IsCurrentData(oldData) || rotationRing.IsCurrentData(legacyData) {
+		t.Errorf("only data encrypted with primary key is current")
+	}
+
+	if _, err := newRing.Extract(oldData); err == nil || !strings.Contains(err.Error(), "not in the key ring") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
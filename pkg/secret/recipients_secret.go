@@ -0,0 +1,320 @@
+package secret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// RecipientsDataPrefix starts data encrypted to X25519 recipients:
+	// dapp:x25519:BASE64(EPHEMERAL_PUBLIC_KEY+STANZAS_COUNT+STANZAS+NONCE+CIPHERTEXT)
+	// It is dapp own format: only keys are encoded as age keys, the data cannot be encrypted or decrypted by age tool.
+	RecipientsDataPrefix = "dapp:x25519:"
+
+	recipientHrp = "age"
+	identityHrp  = "AGE-SECRET-KEY-"
+
+	x25519WrapInfo     = "dapp secret x25519"
+	recipientTagSize   = 4
+	wrappedFileKeySize = 32 + 16
+	maxRecipients      = 255
+)
+
+// X25519Recipient is a public key data is encrypted to, age-style "age1..." string is used.
+type X25519Recipient struct {
+	PublicKey []byte
+}
+
+// X25519Identity is a private key data is decrypted with, age-style "AGE-SECRET-KEY-1..." string is used.
+type X25519Identity struct {
+	PrivateKey []byte
+	PublicKey  []byte
+}
+
+func GenerateX25519Identity() (*X25519Identity, error) {
+	privateKey := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(privateKey); err != nil {
+		return nil, err
+	}
+
+	return newX25519Identity(privateKey)
+}
+
+func newX25519Identity(privateKey []byte) (*X25519Identity, error) {
+	publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &X25519Identity{PrivateKey: privateKey, PublicKey: publicKey}, nil
+}
+
+func ParseX25519Recipient(s string) (*X25519Recipient, error) {
+	hrp, data, err := bech32Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("bad recipient '%s': %s", s, err)
+	}
+
+	if hrp != recipientHrp {
+		return nil, fmt.Errorf("bad recipient '%s': expected '%s1' prefix", s, recipientHrp)
+	}
+
+	if len(data) != curve25519.PointSize {
+		return nil, fmt.Errorf("bad recipient '%s': bad key length", s)
+	}
+
+	return &X25519Recipient{PublicKey: data}, nil
+}
+
+func ParseX25519Identity(s string) (*X25519Identity, error) {
+	hrp, data, err := bech32Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("bad identity: %s", err)
+	}
+
+	if hrp != strings.ToLower(identityHrp) {
+		return nil, fmt.Errorf("bad identity: expected '%s1' prefix", identityHrp)
+	}
+
+	if len(data) != curve25519.ScalarSize {
+		return nil, fmt.Errorf("bad identity: bad key length")
+	}
+
+	return newX25519Identity(data)
+}
+
+// ParseX25519Identities parses identities file, empty lines and comments are skipped.
+func ParseX25519Identities(data []byte) ([]*X25519Identity, error) {
+	var identities []*X25519Identity
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		identity, err := ParseX25519Identity(line)
+		if err != nil {
+			return nil, err
+		}
+
+		identities = append(identities, identity)
+	}
+
+	return identities, nil
+}
+
+func (r *X25519Recipient) String() string {
+	res, _ := bech32Encode(recipientHrp, r.PublicKey)
+	return res
+}
+
+func (i *X25519Identity) String() string {
+	res, _ := bech32Encode(identityHrp, i.PrivateKey)
+	return strings.ToUpper(res)
+}
+
+func (i *X25519Identity) Recipient() *X25519Recipient {
+	return &X25519Recipient{PublicKey: i.PublicKey}
+}
+
+func recipientTag(publicKey []byte) []byte {
+	sum := sha256.Sum256(publicKey)
+	return sum[:recipientTagSize]
+}
+
+func wrapKeyAEAD(sharedSecret, ephemeralPublicKey, recipientPublicKey []byte) (cipher.AEAD, error) {
+	salt := append(append([]byte{}, ephemeralPublicKey...), recipientPublicKey...)
+
+	wrapKey := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, sharedSecret, salt, []byte(x25519WrapInfo)), wrapKey); err != nil {
+		return nil, err
+	}
+
+	return newAesGcm(wrapKey)
+}
+
+func newAesGcm(key []byte) (cipher.AEAD, error) {
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(c)
+}
+
+// RecipientsSecret encrypts data with random file key wrapped for each recipient.
+// Data is extracted with any of identities, data in other formats is extracted with Fallback secret if specified.
+type RecipientsSecret struct {
+	Recipients []*X25519Recipient
+	Identities []*X25519Identity
+	Fallback   Secret
+	// IdentityNotFoundErr is returned when data in recipients format is extracted without identities
+	IdentityNotFoundErr error
+}
+
+func (s *RecipientsSecret) Generate(data []byte) ([]byte, error) {
+	if len(s.Recipients) == 0 {
+		return nil, fmt.Errorf("no recipients to encrypt data to")
+	}
+	if len(s.Recipients) > maxRecipients {
+		return nil, fmt.Errorf("too many recipients: maximum %d supported", maxRecipients)
+	}
+
+	fileKey := make([]byte, 32)
+	if _, err := rand.Read(fileKey); err != nil {
+		return nil, err
+	}
+
+	ephemeral, err := GenerateX25519Identity()
+	if err != nil {
+		return nil, err
+	}
+
+	header := bytes.NewBuffer(nil)
+	header.Write(ephemeral.PublicKey)
+	header.WriteByte(byte(len(s.Recipients)))
+
+	for _, recipient := range s.Recipients {
+		sharedSecret, err := curve25519.X25519(ephemeral.PrivateKey, recipient.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %s: %s", recipient, err)
+		}
+
+		aead, err := wrapKeyAEAD(sharedSecret, ephemeral.PublicKey, recipient.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		// Wrap key is unique for each ephemeral key, so zero nonce is used
+		header.Write(recipientTag(recipient.PublicKey))
+		header.Write(aead.Seal(nil, make([]byte, aead.NonceSize()), fileKey, nil))
+	}
+
+	aead, err := newAesGcm(fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	payload := append(header.Bytes(), nonce...)
+	payload = aead.Seal(payload, nonce, data, append([]byte(RecipientsDataPrefix), header.Bytes()...))
+
+	return []byte(RecipientsDataPrefix + base64.RawURLEncoding.EncodeToString(payload)), nil
+}
+
+func (s *RecipientsSecret) Extract(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	if !IsRecipientsData(data) {
+		if s.Fallback == nil {
+			return nil, fmt.Errorf("bad secret data format: expected '%s' prefix", RecipientsDataPrefix)
+		}
+
+		return s.Fallback.Extract(data)
+	}
+
+	if len(s.Identities) == 0 {
+		if s.IdentityNotFoundErr != nil {
+			return nil, s.IdentityNotFoundErr
+		}
+		return nil, fmt.Errorf("no identity to decrypt data")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(string(data), RecipientsDataPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("bad secret data format: %s", err)
+	}
+
+	if len(payload) < curve25519.PointSize+1 {
+		return nil, fmt.Errorf("bad secret data format: data is too short")
+	}
+
+	ephemeralPublicKey := payload[:curve25519.PointSize]
+	stanzasCount := int(payload[curve25519.PointSize])
+	stanzasStart := curve25519.PointSize + 1
+	stanzaSize := recipientTagSize + wrappedFileKeySize
+	headerSize := stanzasStart + stanzasCount*stanzaSize
+
+	if len(payload) < headerSize {
+		return nil, fmt.Errorf("bad secret data format: data is too short")
+	}
+
+	var fileKey []byte
+	for _, identity := range s.Identities {
+		tag := recipientTag(identity.PublicKey)
+
+		for ind := 0; ind < stanzasCount; ind++ {
+			stanza := payload[stanzasStart+ind*stanzaSize : stanzasStart+(ind+1)*stanzaSize]
+			if !bytes.Equal(stanza[:recipientTagSize], tag) {
+				continue
+			}
+
+			sharedSecret, err := curve25519.X25519(identity.PrivateKey, ephemeralPublicKey)
+			if err != nil {
+				return nil, fmt.Errorf("bad secret data format: %s", err)
+			}
+
+			aead, err := wrapKeyAEAD(sharedSecret, ephemeralPublicKey, identity.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+
+			if key, err := aead.Open(nil, make([]byte, aead.NonceSize()), stanza[recipientTagSize:], nil); err == nil {
+				fileKey = key
+				break
+			}
+		}
+
+		if fileKey != nil {
+			break
+		}
+	}
+
+	if fileKey == nil {
+		return nil, fmt.Errorf("data is not encrypted to any of available identities")
+	}
+
+	aead, err := newAesGcm(fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) < headerSize+aead.NonceSize()+aead.Overhead() {
+		return nil, fmt.Errorf("bad secret data format: data is too short")
+	}
+
+	nonce := payload[headerSize : headerSize+aead.NonceSize()]
+	cipherText := payload[headerSize+aead.NonceSize():]
+
+	result, err := aead.Open(nil, nonce, cipherText, append([]byte(RecipientsDataPrefix), payload[:headerSize]...))
+	if err != nil {
+		return nil, fmt.Errorf("data authentication failed: data is corrupted")
+	}
+
+	return result, nil
+}
+
+// IsCurrentData checks whether data is encrypted to recipients.
+func (s *RecipientsSecret) IsCurrentData(data []byte) bool {
+	return IsRecipientsData(data)
+}
+
+func IsRecipientsData(data []byte) bool {
+	return strings.HasPrefix(string(data), RecipientsDataPrefix)
+}
@@ -0,0 +1,100 @@
+package secret
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBech32Decode(t *testing.T) {
+	for _, test := range []string{
+		"A12UEL5L",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+	} {
+		t.Run(test, func(t *testing.T) {
+			if _, _, err := bech32Decode(test); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	if _, _, err := bech32Decode("abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxx"); err == nil {
+		t.Error("expected invalid checksum error")
+	}
+}
+
+func TestX25519Keys(t *testing.T) {
+	identity, err := GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(identity.String(), "AGE-SECRET-KEY-1") || !strings.HasPrefix(identity.Recipient().String(), "age1") {
+		t.Fatalf("unexpected keys %s %s", identity, identity.Recipient())
+	}
+
+	parsedIdentity, err := ParseX25519Identity(identity.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedRecipient, err := ParseX25519Recipient(identity.Recipient().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsedRecipient.String() != parsedIdentity.Recipient().String() {
+		t.Errorf("\n[EXPECTED]: %s\n[GOT]: %s", parsedIdentity.Recipient(), parsedRecipient)
+	}
+}
+
+func TestRecipientsSecret(t *testing.T) {
+	var identities []*X25519Identity
+	for i := 0; i < 3; i++ {
+		identity, err := GenerateX25519Identity()
+		if err != nil {
+			t.Fatal(err)
+		}
+		identities = append(identities, identity)
+	}
+
+	encryptor := &RecipientsSecret{Recipients: []*X25519Recipient{identities[0].Recipient(), identities[1].Recipient()}}
+
+	encodedData, err := encryptor.Generate([]byte("flant"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := encryptor.Extract(encodedData); err == nil {
+		t.Error("expected error extracting data without identities")
+	}
+
+	for _, identity := range identities[:2] {
+		s := &RecipientsSecret{Identities: []*X25519Identity{identity}}
+
+		result, err := s.Extract(encodedData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(result) != "flant" {
+			t.Errorf("\n[EXPECTED]: flant\n[GOT]: %s", result)
+		}
+	}
+
+	notRecipient := &RecipientsSecret{Identities: []*X25519Identity{identities[2]}}
+	if _, err := notRecipient.Extract(encodedData); err == nil || !strings.HasPrefix(err.Error(), "data is not encrypted to any of available identities") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	tampered := []byte(string(encodedData))
+	if tampered[len(tampered)-10] == 'A' {
+		tampered[len(tampered)-10] = 'B'
+	} else {
+		tampered[len(tampered)-10] = 'A'
+	}
+
+	s := &RecipientsSecret{Identities: []*X25519Identity{identities[0]}}
+	if _, err := s.Extract(tampered); err == nil || !strings.HasPrefix(err.Error(), "data authentication failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -5,11 +5,40 @@ type Secret interface {
 	Extract(encodedData []byte) ([]byte, error)
 }
 
+// VersionedSecret generates data in the versioned envelope format
+// and extracts both versioned and legacy AES-CBC data.
+type VersionedSecret struct {
+	Current *AesGcmSecret
+	Legacy  *AesSecret
+}
+
 func NewSecret(key []byte) (Secret, error) {
-	s, err := NewAesSecret(key)
+	legacy, err := NewAesSecret(key)
 	if err != nil {
 		return nil, err
 	}
 
-	return s, nil
+	current, err := NewAesGcmSecret(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VersionedSecret{Current: current, Legacy: legacy}, nil
+}
+
+func (s *VersionedSecret) Generate(data []byte) ([]byte, error) {
+	return s.Current.Generate(data)
+}
+
+func (s *VersionedSecret) Extract(data []byte) ([]byte, error) {
+	if IsVersionedData(data) {
+		return s.Current.Extract(data)
+	}
+
+	return s.Legacy.Extract(data)
+}
+
+// IsCurrentData checks whether data is in the versioned format.
+func (s *VersionedSecret) IsCurrentData(data []byte) bool {
+	return IsVersionedData(data)
 }
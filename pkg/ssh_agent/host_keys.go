@@ -0,0 +1,185 @@
+package ssh_agent
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/flant/dapp/pkg/logger"
+)
+
+var HostKeys []HostKey
+
+// HostKey maps private key to the hosts, Host is a space separated list of ssh patterns (*, ? and ! for negation).
+type HostKey struct {
+	Host string
+	Key  string
+}
+
+func (hostKey HostKey) Match(host string) bool {
+	var matched bool
+
+	for _, pattern := range strings.Fields(hostKey.Host) {
+		negated := strings.HasPrefix(pattern, "!")
+		if !matchHostPattern(strings.TrimPrefix(pattern, "!"), host) {
+			continue
+		}
+
+		if negated {
+			return false
+		}
+		matched = true
+	}
+
+	return matched
+}
+
+func matchHostPattern(pattern, host string) bool {
+	expr := regexp.QuoteMeta(strings.ToLower(pattern))
+	expr = strings.Replace(expr, `\*`, ".*", -1)
+	expr = strings.Replace(expr, `\?`, ".", -1)
+
+	return regexp.MustCompile(fmt.Sprintf("^%s$", expr)).MatchString(strings.ToLower(host))
+}
+
+// GetHostKeys returns keys mapped to the host in the order of definition.
+func GetHostKeys(host string) []string {
+	var keys []string
+	for _, hostKey := range HostKeys {
+		if hostKey.Match(host) {
+			keys = append(keys, hostKey.Key)
+		}
+	}
+
+	return uniqStrings(keys)
+}
+
+func hostKeysPaths() []string {
+	var keys []string
+	for _, hostKey := range HostKeys {
+		keys = append(keys, hostKey.Key)
+	}
+
+	return uniqStrings(keys)
+}
+
+// ParseHostKey parses key mapped to the hosts by ssh pattern with HOST=PATH, relative PATH is resolved by the base dir.
+func ParseHostKey(spec, baseDir string) (HostKey, error) {
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || parts[1] == "" {
+		return HostKey{}, fmt.Errorf("bad host key `%s`: expected HOST=PATH", spec)
+	}
+
+	return HostKey{Host: strings.TrimSpace(parts[0]), Key: ResolvePath(parts[1], baseDir)}, nil
+}
+
+// ParseConfigFile parses Host and IdentityFile directives of ~/.ssh/config-style file, other directives are ignored.
+// As ssh does, identity files which cannot be loaded (not existing or passphrase-protected) are skipped with a warning.
+func ParseConfigFile(path string) ([]HostKey, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var hostKeys []HostKey
+	var host string
+
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		keyword, value := splitConfigLine(line)
+		if value == "" {
+			return nil, fmt.Errorf("line %d: no value for %s", lineNumber, keyword)
+		}
+
+		switch strings.ToLower(keyword) {
+		case "host":
+			host = value
+		case "match":
+			return nil, fmt.Errorf("line %d: Match directive is not supported", lineNumber)
+		case "identityfile":
+			if host == "" {
+				host = "*"
+			}
+
+			identityFile, err := expandIdentityFileTokens(value)
+			if err != nil {
+				logger.LogWarningF("WARNING: %s line %d: IdentityFile %s skipped: %s\n", path, lineNumber, value, err)
+				continue
+			}
+
+			key := ResolvePath(identityFile, filepath.Dir(path))
+			if _, err := parsePrivateKey(key); err != nil {
+				logger.LogWarningF("WARNING: %s line %d: IdentityFile skipped: %s\n", path, lineNumber, err)
+				continue
+			}
+
+			hostKeys = append(hostKeys, HostKey{Host: host, Key: key})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return hostKeys, nil
+}
+
+// expandIdentityFileTokens expands %d (home dir) and %% tokens, other tokens depend on the connection and are not supported.
+func expandIdentityFileTokens(value string) (string, error) {
+	var res []rune
+
+	runes := []rune(value)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' {
+			res = append(res, runes[i])
+			continue
+		}
+
+		if i+1 == len(runes) {
+			return "", fmt.Errorf("incomplete token at the end")
+		}
+
+		i++
+		switch runes[i] {
+		case 'd':
+			res = append(res, []rune(os.Getenv("HOME"))...)
+		case '%':
+			res = append(res, '%')
+		default:
+			return "", fmt.Errorf("token %%%c is not supported", runes[i])
+		}
+	}
+
+	return string(res), nil
+}
+
+func splitConfigLine(line string) (string, string) {
+	parts := strings.SplitN(strings.Replace(line, "=", " ", 1), " ", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+
+	return parts[0], strings.Trim(strings.TrimSpace(parts[1]), `"`)
+}
+
+// ResolvePath expands ~ and makes relative path absolute by the base dir.
+func ResolvePath(path, baseDir string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		path = filepath.Join(os.Getenv("HOME"), strings.TrimPrefix(path, "~"))
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+
+	return path
+}
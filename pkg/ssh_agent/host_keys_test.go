@@ -0,0 +1,151 @@
+package ssh_agent
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestHostKey_Match(t *testing.T) {
+	hostKey := HostKey{Host: "*.example.com github.com !private.example.com", Key: "key"}
+
+	expectations := map[string]bool{
+		"github.com":          true,
+		"GitHub.com":          true,
+		"git.example.com":     true,
+		"example.com":         false,
+		"private.example.com": false,
+		"gitlab.com":          false,
+	}
+
+	for host, expected := range expectations {
+		if got := hostKey.Match(host); got != expected {
+			t.Errorf("\n[HOST]: %q\n[EXPECTED]: %v\n[GOT]: %v", host, expected, got)
+		}
+	}
+}
+
+func writeTestPrivateKey(t *testing.T, path string, passphrase string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}
+	if passphrase != "" {
+		block, err = x509.EncryptPEMBlock(rand.Reader, block.Type, block.Bytes, []byte(passphrase), x509.PEMCipherAES256)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dapp-ssh-agent-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", filepath.Join(dir, "home"))
+
+	writeTestPrivateKey(t, filepath.Join(dir, "keys", "default"), "")
+	writeTestPrivateKey(t, filepath.Join(dir, "keys", "github"), "")
+	writeTestPrivateKey(t, filepath.Join(dir, "keys", "gitlab"), "")
+	writeTestPrivateKey(t, filepath.Join(dir, "keys", "encrypted"), "passphrase")
+	writeTestPrivateKey(t, filepath.Join(dir, "home", ".ssh", "id_home"), "")
+
+	configPath := filepath.Join(dir, "config")
+	config := `# deploy keys
+IdentityFile keys/default
+Host github.com *.github.com
+  User git
+  IdentityFile=` + filepath.Join(dir, "keys", "github") + `
+  IdentityFile keys/missing
+Host gitlab.example.com
+  IdentityFile "keys/gitlab"
+  IdentityFile keys/encrypted
+  IdentityFile keys/%h
+Host home.example.com
+  IdentityFile %d/.ssh/id_home
+`
+	if err := ioutil.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	hostKeys, err := ParseConfigFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []HostKey{
+		{Host: "*", Key: filepath.Join(dir, "keys/default")},
+		{Host: "github.com *.github.com", Key: filepath.Join(dir, "keys/github")},
+		{Host: "gitlab.example.com", Key: filepath.Join(dir, "keys/gitlab")},
+		{Host: "home.example.com", Key: filepath.Join(dir, "home/.ssh/id_home")},
+	}
+
+	if !reflect.DeepEqual(hostKeys, expected) {
+		t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v", expected, hostKeys)
+	}
+}
+
+func TestExpandIdentityFileTokens(t *testing.T) {
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", "/home/user")
+
+	for value, expected := range map[string]string{
+		"~/.ssh/id_rsa":      "~/.ssh/id_rsa",
+		"%d/.ssh/id_rsa":     "/home/user/.ssh/id_rsa",
+		"%d/keys/100%%/key":  "/home/user/keys/100%/key",
+		"/keys/deploy_key_1": "/keys/deploy_key_1",
+	} {
+		got, err := expandIdentityFileTokens(value)
+		if err != nil {
+			t.Fatalf("\n[VALUE]: %s\n[ERROR]: %s", value, err)
+		}
+
+		if got != expected {
+			t.Errorf("\n[VALUE]: %s\n[EXPECTED]: %s\n[GOT]: %s", value, expected, got)
+		}
+	}
+
+	for _, value := range []string{"~/.ssh/%h", "%d/.ssh/id_%r", "/keys/%"} {
+		if _, err := expandIdentityFileTokens(value); err == nil {
+			t.Errorf("\n[VALUE]: %s\n[EXPECTED]: error\n[GOT]: no error", value)
+		}
+	}
+}
+
+func TestParseHostKey(t *testing.T) {
+	hostKey, err := ParseHostKey("*.example.com=keys/deploy_key", "/project")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := HostKey{Host: "*.example.com", Key: "/project/keys/deploy_key"}
+	if hostKey != expected {
+		t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v", expected, hostKey)
+	}
+
+	for _, spec := range []string{"/keys/deploy_key", "=/keys/deploy_key", "example.com="} {
+		if _, err := ParseHostKey(spec, "/project"); err == nil {
+			t.Errorf("\n[SPEC]: %s\n[EXPECTED]: error\n[GOT]: no error", spec)
+		}
+	}
+}
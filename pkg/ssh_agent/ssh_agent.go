@@ -20,17 +20,67 @@ import (
 var (
 	SSHAuthSock string
 	tmpSockPath string
+
+	defaultKeyFileNames = []string{"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519"}
 )
 
-func Init(keys []string) error {
-	for _, key := range keys {
+type Options struct {
+	// Keys are paths of private keys
+	Keys []string
+	// HostKeys are keys mapped to the hosts by cli options and dappfile git entries
+	HostKeys []HostKey
+	// ConfigFile is ~/.ssh/config-style file with Host and IdentityFile directives
+	ConfigFile string
+	// KnownHostsFile is enforced for remote git repos and build containers
+	KnownHostsFile string
+}
+
+func Init(opts Options) error {
+	keys := opts.Keys
+
+	HostKeys = append(HostKeys, opts.HostKeys...)
+
+	if opts.ConfigFile != "" {
+		configHostKeys, err := ParseConfigFile(opts.ConfigFile)
+		if err != nil {
+			return fmt.Errorf("bad ssh config %s: %s", opts.ConfigFile, err)
+		}
+		HostKeys = append(HostKeys, configHostKeys...)
+	}
+
+	for _, key := range append(keys, hostKeysPaths()...) {
 		if !util.IsFileExists(key) {
 			return fmt.Errorf("specified ssh key %s does not exist", key)
 		}
 	}
 
+	if opts.KnownHostsFile != "" {
+		if !util.IsFileExists(opts.KnownHostsFile) {
+			return fmt.Errorf("specified ssh known hosts file %s does not exist", opts.KnownHostsFile)
+		}
+		KnownHostsFile = opts.KnownHostsFile
+	}
+
+	if err := initSSHAuthSock(keys); err != nil {
+		return err
+	}
+
+	if len(HostKeys) > 0 || KnownHostsFile != "" {
+		configDir, err := writeSSHConfigDir()
+		if err != nil {
+			return fmt.Errorf("error writing ssh config: %s", err)
+		}
+		SSHConfigDir = configDir
+	}
+
+	return nil
+}
+
+func initSSHAuthSock(keys []string) error {
+	hostKeys := hostKeysPaths()
+
 	if len(keys) > 0 {
-		agentSock, err := runSSHAgentWithKeys(keys)
+		agentSock, err := runSSHAgentWithKeys(append(keys, hostKeys...), "")
 		if err != nil {
 			return err
 		}
@@ -41,48 +91,57 @@ func Init(keys []string) error {
 
 	systemAgentSock := os.Getenv("SSH_AUTH_SOCK")
 	if systemAgentSock != "" && util.IsFileExists(systemAgentSock) {
-		SSHAuthSock = systemAgentSock
-		fmt.Printf("Using system ssh-agent %s\n", systemAgentSock)
+		if len(hostKeys) == 0 {
+			SSHAuthSock = systemAgentSock
+			logger.LogF("Using system ssh-agent %s\n", systemAgentSock)
+			return nil
+		}
+
+		agentSock, err := runSSHAgentWithKeys(hostKeys, systemAgentSock)
+		if err != nil {
+			return err
+		}
+		SSHAuthSock = agentSock
+		logger.LogF("Using system ssh-agent %s for not mapped hosts\n", systemAgentSock)
+
 		return nil
 	}
 
-	var defaultKeys []string
-	for _, defaultFileName := range []string{"id_rsa", "id_dsa"} {
+	var validKeys []string
+	for _, defaultFileName := range defaultKeyFileNames {
 		path := filepath.Join(os.Getenv("HOME"), ".ssh", defaultFileName)
-		if util.IsFileExists(path) {
-			defaultKeys = append(defaultKeys, path)
+		if !util.IsFileExists(path) {
+			continue
 		}
-	}
 
-	if len(defaultKeys) > 0 {
-		var validKeys []string
+		if _, err := parsePrivateKey(path); err != nil {
+			continue
+		}
 
-		for _, key := range defaultKeys {
-			keyData, err := ioutil.ReadFile(key)
-			if err != nil {
-				continue
-			}
-			_, err = ssh.ParseRawPrivateKeyWithPassphrase(keyData, []byte{})
-			if err != nil {
-				continue
-			}
+		validKeys = append(validKeys, path)
+	}
 
-			validKeys = append(validKeys, key)
-		}
+	validKeys = append(validKeys, hostKeys...)
 
-		if len(validKeys) > 0 {
-			agentSock, err := runSSHAgentWithKeys(validKeys)
-			if err != nil {
-				return err
-			}
-			SSHAuthSock = agentSock
+	if len(validKeys) > 0 {
+		agentSock, err := runSSHAgentWithKeys(validKeys, "")
+		if err != nil {
+			return err
 		}
+		SSHAuthSock = agentSock
 	}
 
 	return nil
 }
 
 func Terminate() error {
+	if SSHConfigDir != "" {
+		err := os.RemoveAll(SSHConfigDir)
+		if err != nil {
+			return fmt.Errorf("unable to remove tmp ssh config dir %s: %s", SSHConfigDir, err)
+		}
+	}
+
 	if tmpSockPath != "" {
 		err := os.RemoveAll(tmpSockPath)
 		if err != nil {
@@ -93,13 +152,13 @@ func Terminate() error {
 	return nil
 }
 
-func runSSHAgentWithKeys(keys []string) (string, error) {
-	agentSock, err := runSSHAgent()
+func runSSHAgentWithKeys(keys []string, upstreamSock string) (string, error) {
+	agentSock, err := runSSHAgent(upstreamSock)
 	if err != nil {
 		return "", fmt.Errorf("error running ssh agent: %s", err)
 	}
 
-	for _, key := range keys {
+	for _, key := range uniqStrings(keys) {
 		err := addSSHKey(agentSock, key)
 		if err != nil {
 			return "", fmt.Errorf("error adding ssh key %s: %s", key, err)
@@ -109,7 +168,8 @@ func runSSHAgentWithKeys(keys []string) (string, error) {
 	return agentSock, nil
 }
 
-func runSSHAgent() (string, error) {
+// runSSHAgent runs in-process ssh agent, keys which are not found in the agent are requested from the upstream agent if specified.
+func runSSHAgent(upstreamSock string) (string, error) {
 	sockPath := filepath.Join(dapp.GetTmpDir(), "dapp-ssh-agent", uuid.NewV4().String())
 	tmpSockPath = sockPath
 
@@ -123,7 +183,7 @@ func runSSHAgent() (string, error) {
 		return "", fmt.Errorf("error listen unix sock %s: %s", sockPath, err)
 	}
 
-	fmt.Printf("Running ssh agent on unix sock %s\n", sockPath)
+	logger.LogF("Running ssh agent on unix sock %s\n", sockPath)
 
 	go func() {
 		agnt := agent.NewKeyring()
@@ -137,8 +197,19 @@ func runSSHAgent() (string, error) {
 
 			go func() {
 				var err error
+				var connAgent agent.Agent = agnt
+
+				if upstreamSock != "" {
+					upstreamConn, err := net.Dial("unix", upstreamSock)
+					if err != nil {
+						logger.LogWarningF("WARNING: failed to connect to upstream ssh-agent %s: %s\n", upstreamSock, err)
+					} else {
+						defer upstreamConn.Close()
+						connAgent = &upstreamAgent{Agent: agnt, upstream: agent.NewClient(upstreamConn)}
+					}
+				}
 
-				err = agent.ServeAgent(agnt, conn)
+				err = agent.ServeAgent(connAgent, conn)
 				if err != nil && err != io.EOF {
 					logger.LogWarningF("WARNING: ssh-agent server error: %s\n", err)
 					return
@@ -165,14 +236,9 @@ func addSSHKey(authSock string, key string) error {
 
 	agentClient := agent.NewClient(conn)
 
-	keyData, err := ioutil.ReadFile(key)
-	if err != nil {
-		return fmt.Errorf("error reading key file %s: %s", key, err)
-	}
-
-	privateKey, err := ssh.ParseRawPrivateKeyWithPassphrase(keyData, []byte{})
+	privateKey, err := parsePrivateKey(key)
 	if err != nil {
-		return fmt.Errorf("error parsing private key %s: %s", key, err)
+		return err
 	}
 
 	err = agentClient.Add(agent.AddedKey{PrivateKey: privateKey})
@@ -180,7 +246,62 @@ func addSSHKey(authSock string, key string) error {
 		return err
 	}
 
-	fmt.Printf("Added private key %s to ssh agent %s\n", key, authSock)
+	logger.LogF("Added private key %s to ssh agent %s\n", key, authSock)
 
 	return nil
 }
+
+func parsePrivateKey(key string) (interface{}, error) {
+	keyData, err := ioutil.ReadFile(key)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key file %s: %s", key, err)
+	}
+
+	privateKey, err := ssh.ParseRawPrivateKey(keyData)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key %s: %s", key, err)
+	}
+
+	return privateKey, nil
+}
+
+// GetSigners returns signers of the private keys to be used for ssh connections without agent.
+func GetSigners(keys []string) ([]ssh.Signer, error) {
+	var signers []ssh.Signer
+
+	for _, key := range keys {
+		privateKey, err := parsePrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		signer, err := ssh.NewSignerFromKey(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("error creating signer for private key %s: %s", key, err)
+		}
+
+		signers = append(signers, signer)
+	}
+
+	return signers, nil
+}
+
+func uniqStrings(values []string) []string {
+	var res []string
+
+	for _, value := range values {
+		var exists bool
+		for _, v := range res {
+			if v == value {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			res = append(res, value)
+		}
+	}
+
+	return res
+}
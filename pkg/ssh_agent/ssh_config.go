@@ -0,0 +1,95 @@
+package ssh_agent
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/satori/go.uuid"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/flant/dapp/pkg/dapp"
+)
+
+const ContainerSSHConfigDir = "/tmp/dapp-ssh"
+
+var (
+	KnownHostsFile string
+	// SSHConfigDir contains ssh config, known_hosts and public keys of the host keys to be mounted into build containers by ContainerSSHConfigDir path
+	SSHConfigDir string
+)
+
+// HostKeyCallback verifies host keys by the known hosts file, nil is returned if the file is not specified.
+func HostKeyCallback() (ssh.HostKeyCallback, error) {
+	if KnownHostsFile == "" {
+		return nil, nil
+	}
+
+	callback, err := knownhosts.New(KnownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("bad ssh known hosts file %s: %s", KnownHostsFile, err)
+	}
+
+	return callback, nil
+}
+
+// writeSSHConfigDir writes ssh config for build containers.
+// The host keys are selected by public keys with IdentitiesOnly, private keys are served by the agent.
+func writeSSHConfigDir() (string, error) {
+	dir := filepath.Join(dapp.GetTmpDir(), "dapp-ssh-config", uuid.NewV4().String())
+
+	if err := os.MkdirAll(filepath.Join(dir, "keys"), 0755); err != nil {
+		return "", err
+	}
+
+	config := &bytes.Buffer{}
+
+	for i, hostKey := range HostKeys {
+		publicKey, err := publicKeyData(hostKey.Key)
+		if err != nil {
+			return "", err
+		}
+
+		publicKeyName := fmt.Sprintf("%d.pub", i)
+		if err := ioutil.WriteFile(filepath.Join(dir, "keys", publicKeyName), publicKey, 0644); err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(config, "Host %s\n", hostKey.Host)
+		fmt.Fprintf(config, "  IdentityFile %s\n", filepath.Join(ContainerSSHConfigDir, "keys", publicKeyName))
+		fmt.Fprintf(config, "  IdentitiesOnly yes\n\n")
+	}
+
+	if KnownHostsFile != "" {
+		data, err := ioutil.ReadFile(KnownHostsFile)
+		if err != nil {
+			return "", fmt.Errorf("error reading ssh known hosts file %s: %s", KnownHostsFile, err)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dir, "known_hosts"), data, 0644); err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(config, "Host *\n")
+		fmt.Fprintf(config, "  UserKnownHostsFile %s\n", filepath.Join(ContainerSSHConfigDir, "known_hosts"))
+		fmt.Fprintf(config, "  StrictHostKeyChecking yes\n")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "config"), config.Bytes(), 0644); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+func publicKeyData(key string) ([]byte, error) {
+	signers, err := GetSigners([]string{key})
+	if err != nil {
+		return nil, err
+	}
+
+	return ssh.MarshalAuthorizedKey(signers[0].PublicKey()), nil
+}
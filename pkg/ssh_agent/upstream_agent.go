@@ -0,0 +1,49 @@
+package ssh_agent
+
+import (
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// upstreamAgent serves keys of the in-process keyring and keys of the upstream (system) agent.
+type upstreamAgent struct {
+	agent.Agent
+	upstream agent.Agent
+}
+
+func (a *upstreamAgent) List() ([]*agent.Key, error) {
+	keys, err := a.Agent.List()
+	if err != nil {
+		return nil, err
+	}
+
+	upstreamKeys, err := a.upstream.List()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(keys, upstreamKeys...), nil
+}
+
+func (a *upstreamAgent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	signature, err := a.Agent.Sign(key, data)
+	if err == nil {
+		return signature, nil
+	}
+
+	return a.upstream.Sign(key, data)
+}
+
+func (a *upstreamAgent) Signers() ([]ssh.Signer, error) {
+	signers, err := a.Agent.Signers()
+	if err != nil {
+		return nil, err
+	}
+
+	upstreamSigners, err := a.upstream.Signers()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(signers, upstreamSigners...), nil
+}
@@ -12,6 +12,8 @@ import (
 	"strings"
 
 	uuid "github.com/satori/go.uuid"
+
+	"github.com/flant/dapp/pkg/logger"
 )
 
 type ArchiveOptions struct {
@@ -39,10 +41,6 @@ func Archive(out io.Writer, gitDir, workTreeDir string, opts ArchiveOptions) (*A
 	return writeArchive(out, gitDir, workTreeDir, false, opts)
 }
 
-func debugArchive() bool {
-	return os.Getenv("DAPP_TRUE_GIT_DEBUG_ARCHIVE") == "1"
-}
-
 func writeArchive(out io.Writer, gitDir, workTreeDir string, withSubmodules bool, opts ArchiveOptions) (*ArchiveDescriptor, error) {
 	var err error
 
@@ -111,15 +109,11 @@ func writeArchive(out io.Writer, gitDir, workTreeDir string, withSubmodules bool
 			if info.IsDir() {
 				desc.Type = DirectoryArchive
 
-				if debugArchive() {
-					fmt.Printf("Found BasePath `%s` directory: directory archive type\n", path)
-				}
+				logger.LogDebugF("true_git.archive", "Found BasePath `%s` directory: directory archive type\n", path)
 			} else {
 				desc.Type = FileArchive
 
-				if debugArchive() {
-					fmt.Printf("Found BasePath `%s` file: file archive\n", path)
-				}
+				logger.LogDebugF("true_git.archive", "Found BasePath `%s` file: file archive\n", path)
 			}
 		}
 
@@ -128,9 +122,7 @@ func writeArchive(out io.Writer, gitDir, workTreeDir string, withSubmodules bool
 		}
 
 		if !opts.PathFilter.IsFilePathValid(path) {
-			if debugArchive() {
-				fmt.Printf("Excluded path `%s` by path filter %s\n", path, opts.PathFilter.String())
-			}
+			logger.LogDebugF("true_git.archive", "Excluded path `%s` by path filter %s\n", path, opts.PathFilter.String())
 			return nil
 		}
 
@@ -159,9 +151,7 @@ func writeArchive(out io.Writer, gitDir, workTreeDir string, withSubmodules bool
 				return fmt.Errorf("unable to write tar symlink header for file `%s`: %s", archivePath, err)
 			}
 
-			if debugArchive() {
-				fmt.Printf("Added archive symlink `%s` -> `%s`\n", path, linkname)
-			}
+			logger.LogDebugF("true_git.archive", "Added archive symlink `%s` -> `%s`\n", path, linkname)
 
 			return nil
 		}
@@ -194,9 +184,7 @@ func writeArchive(out io.Writer, gitDir, workTreeDir string, withSubmodules bool
 			return fmt.Errorf("error closing file `%s`: %s", absPath, err)
 		}
 
-		if debugArchive() {
-			fmt.Printf("Added archive file `%s`\n", path)
-		}
+		logger.LogDebugF("true_git.archive", "Added archive file `%s`\n", path)
 
 		return nil
 	})
@@ -223,7 +211,7 @@ func startMemprofile() {
 
 func stopMemprofile() {
 	memprofilePath := fmt.Sprintf("/tmp/create-tar-memprofile-%s", uuid.NewV4())
-	fmt.Printf("Creating mem profile: %s\n", memprofilePath)
+	logger.LogF("Creating mem profile: %s\n", memprofilePath)
 	f, err := os.Create(memprofilePath)
 	if err != nil {
 		log.Fatal("could not create memory profile: ", err)
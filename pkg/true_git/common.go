@@ -3,13 +3,14 @@ package true_git
 import (
 	"bytes"
 	"io"
-	"os"
 	"os/exec"
+
+	"github.com/flant/dapp/pkg/logger"
 )
 
 func setCommandRecordingLiveOutput(cmd *exec.Cmd) *bytes.Buffer {
 	recorder := &bytes.Buffer{}
-	cmd.Stdout = io.MultiWriter(recorder, os.Stdout)
-	cmd.Stderr = io.MultiWriter(recorder, os.Stderr)
+	cmd.Stdout = io.MultiWriter(recorder, logger.GetOutStream())
+	cmd.Stderr = io.MultiWriter(recorder, logger.GetErrStream())
 	return recorder
 }
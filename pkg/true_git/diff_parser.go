@@ -4,9 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"os"
 	"strconv"
 	"strings"
+
+	"github.com/flant/dapp/pkg/logger"
 )
 
 func makeDiffParser(out io.Writer, pathFilter PathFilter) *diffParser {
@@ -94,16 +95,12 @@ func (p *diffParser) writeUnrecognizedLine(line string) error {
 	return err
 }
 
-func debugPatchParser() bool {
-	return os.Getenv("DAPP_TRUE_GIT_DEBUG_PATCH_PARSER") == "1"
-}
-
 func (p *diffParser) handleDiffLine(line string) error {
-	if debugPatchParser() {
+	if logger.IsDebug("true_git.patch_parser") {
 		oldState := p.state
-		fmt.Printf("TRUE_GIT parse diff line: state=%#v line=%#v\n", oldState, line)
+		logger.LogDebugF("true_git.patch_parser", "TRUE_GIT parse diff line: state=%#v line=%#v\n", oldState, line)
 		defer func() {
-			fmt.Printf("TRUE_GIT parse diff line: state change: %#v => %#v\n", oldState, p.state)
+			logger.LogDebugF("true_git.patch_parser", "TRUE_GIT parse diff line: state change: %#v => %#v\n", oldState, p.state)
 		}()
 	}
 
@@ -3,11 +3,12 @@ package true_git
 import (
 	"fmt"
 	"io"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
+
+	"github.com/flant/dapp/pkg/logger"
 )
 
 type PatchOptions struct {
@@ -31,10 +32,6 @@ func Patch(out io.Writer, gitDir string, opts PatchOptions) (*PatchDescriptor, e
 	return writePatch(out, gitDir, "", false, opts)
 }
 
-func debugPatch() bool {
-	return os.Getenv("DAPP_TRUE_GIT_DEBUG_PATCH") == "1"
-}
-
 func writePatch(out io.Writer, gitDir, workTreeDir string, withSubmodules bool, opts PatchOptions) (*PatchDescriptor, error) {
 	var err error
 
@@ -101,9 +98,7 @@ func writePatch(out io.Writer, gitDir, workTreeDir string, withSubmodules bool,
 		gitArgs = append(gitArgs, diffOpts...)
 		gitArgs = append(gitArgs, opts.FromCommit, opts.ToCommit)
 
-		if debugPatch() {
-			fmt.Printf("# git %s\n", strings.Join(gitArgs, " "))
-		}
+		logger.LogDebugF("true_git.patch", "# git %s\n", strings.Join(gitArgs, " "))
 
 		cmd = exec.Command("git", gitArgs...)
 
@@ -113,9 +108,7 @@ func writePatch(out io.Writer, gitDir, workTreeDir string, withSubmodules bool,
 		gitArgs = append(gitArgs, diffOpts...)
 		gitArgs = append(gitArgs, opts.FromCommit, opts.ToCommit)
 
-		if debugPatch() {
-			fmt.Printf("# git %s\n", strings.Join(gitArgs, " "))
-		}
+		logger.LogDebugF("true_git.patch", "# git %s\n", strings.Join(gitArgs, " "))
 
 		cmd = exec.Command("git", gitArgs...)
 	}
@@ -180,8 +173,8 @@ func writePatch(out io.Writer, gitDir, workTreeDir string, withSubmodules bool,
 		doneChan <- true
 	}()
 
-	if debugPatch() {
-		out = io.MultiWriter(out, os.Stdout)
+	if logger.IsDebug("true_git.patch") {
+		out = io.MultiWriter(out, logger.GetOutStream())
 	}
 
 	p := makeDiffParser(out, opts.PathFilter)
@@ -213,13 +206,13 @@ WaitForData:
 		BinaryPaths: p.BinaryPaths,
 	}
 
-	if debugPatch() {
-		fmt.Printf("Patch paths count is %d, binary paths count is %d\n", len(desc.Paths), len(desc.BinaryPaths))
+	if logger.IsDebug("true_git.patch") {
+		logger.LogDebugF("true_git.patch", "Patch paths count is %d, binary paths count is %d\n", len(desc.Paths), len(desc.BinaryPaths))
 		for _, path := range desc.Paths {
-			fmt.Printf("Patch path `%s`\n", path)
+			logger.LogDebugF("true_git.patch", "Patch path `%s`\n", path)
 		}
 		for _, path := range desc.BinaryPaths {
-			fmt.Printf("Binary patch path `%s`\n", path)
+			logger.LogDebugF("true_git.patch", "Binary patch path `%s`\n", path)
 		}
 	}
 
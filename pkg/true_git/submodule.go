@@ -3,10 +3,12 @@ package true_git
 import (
 	"fmt"
 	"os/exec"
+
+	"github.com/flant/dapp/pkg/logger"
 )
 
 func deinitSubmodules(repoDir, workTreeDir string) error {
-	fmt.Printf("Deinit submodules in work tree `%s` ...\n", workTreeDir)
+	logger.LogF("Deinit submodules in work tree `%s` ...\n", workTreeDir)
 
 	cmd := exec.Command(
 		"git", "--git-dir", repoDir, "--work-tree", workTreeDir,
@@ -22,13 +24,13 @@ func deinitSubmodules(repoDir, workTreeDir string) error {
 		return fmt.Errorf("`git submodule deinit` failed: %s\n%s", err, output.String())
 	}
 
-	fmt.Printf("Deinit submodules in work tree `%s` OK\n", workTreeDir)
+	logger.LogF("Deinit submodules in work tree `%s` OK\n", workTreeDir)
 
 	return nil
 }
 
 func syncSubmodules(repoDir, workTreeDir string) error {
-	fmt.Printf("Sync submodules in work tree `%s` ...\n", workTreeDir)
+	logger.LogF("Sync submodules in work tree `%s` ...\n", workTreeDir)
 
 	cmd := exec.Command(
 		"git", "--git-dir", repoDir, "--work-tree", workTreeDir,
@@ -44,13 +46,13 @@ func syncSubmodules(repoDir, workTreeDir string) error {
 		return fmt.Errorf("`git submodule sync` failed: %s\n%s", err, output.String())
 	}
 
-	fmt.Printf("Sync submodules in work tree `%s` OK\n", workTreeDir)
+	logger.LogF("Sync submodules in work tree `%s` OK\n", workTreeDir)
 
 	return nil
 }
 
 func updateSubmodules(repoDir, workTreeDir string) error {
-	fmt.Printf("Update submodules in work tree `%s` ...\n", workTreeDir)
+	logger.LogF("Update submodules in work tree `%s` ...\n", workTreeDir)
 
 	cmd := exec.Command(
 		"git", "--git-dir", repoDir, "--work-tree", workTreeDir,
@@ -66,7 +68,7 @@ func updateSubmodules(repoDir, workTreeDir string) error {
 		return fmt.Errorf("`git submodule update` failed: %s\n%s", err, output.String())
 	}
 
-	fmt.Printf("Update submodules in work tree `%s` OK\n", workTreeDir)
+	logger.LogF("Update submodules in work tree `%s` OK\n", workTreeDir)
 
 	return nil
 }
@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+
+	"github.com/flant/dapp/pkg/logger"
 )
 
 func PrepareWorkTree(gitDir, workTreeDir string, commit string) error {
@@ -59,7 +61,7 @@ func prepareWorkTree(gitDir, workTreeDir string, commit string, withSubmodules b
 }
 
 func switchWorkTree(repoDir, workTreeDir string, commit string) error {
-	fmt.Printf("Switch work tree `%s` to commit `%s` ...\n", workTreeDir, commit)
+	logger.LogF("Switch work tree `%s` to commit `%s` ...\n", workTreeDir, commit)
 
 	var err error
 
@@ -91,7 +93,7 @@ func switchWorkTree(repoDir, workTreeDir string, commit string) error {
 		return fmt.Errorf("git clean failed: %s\n%s", err, output.String())
 	}
 
-	fmt.Printf("Switch work tree `%s` to commit `%s` OK\n", workTreeDir, commit)
+	logger.LogF("Switch work tree `%s` to commit `%s` OK\n", workTreeDir, commit)
 
 	return nil
 }
@@ -11,7 +11,7 @@ import (
 func main() {
 	fmt.Printf("keys: %v\n", os.Args[1:])
 
-	err := ssh_agent.Init(os.Args[1:])
+	err := ssh_agent.Init(ssh_agent.Options{Keys: os.Args[1:]})
 	if err != nil {
 		panic(err)
 	}